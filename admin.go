@@ -0,0 +1,476 @@
+package openai
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+)
+
+const (
+	OrganizationRoleOwner  = "owner"
+	OrganizationRoleReader = "reader"
+)
+
+const (
+	ProjectRoleOwner  = "owner"
+	ProjectRoleMember = "member"
+)
+
+const (
+	ProjectStatusActive   = "active"
+	ProjectStatusArchived = "archived"
+)
+
+const (
+	InviteStatusPending  = "pending"
+	InviteStatusAccepted = "accepted"
+	InviteStatusExpired  = "expired"
+)
+
+// AdminClient calls the organization administration APIs. They are authenticated with an
+// admin key, which is created in the organization settings and cannot call the other APIs.
+type AdminClient struct {
+	client *Client
+}
+
+func GetAdminClient(adminKey string) *AdminClient {
+	return &AdminClient{client: GetClient(adminKey)}
+}
+
+// Sets the http.Client used for every request, see Client.SetHTTPClient.
+func (a *AdminClient) SetHTTPClient(hc *http.Client) *AdminClient {
+	a.client.SetHTTPClient(hc)
+	return a
+}
+
+// Sets the base URL the requests are sent to, see Client.SetBaseURL.
+func (a *AdminClient) SetBaseURL(baseURL string) *AdminClient {
+	a.client.SetBaseURL(baseURL)
+	return a
+}
+
+type OrganizationUser struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	Role    string `json:"role"`
+	AddedAt int64  `json:"added_at"`
+}
+
+type InviteProject struct {
+	ID   string `json:"id"`
+	Role string `json:"role"`
+}
+
+type Invite struct {
+	ID         string          `json:"id"`
+	Object     string          `json:"object"`
+	Email      string          `json:"email"`
+	Role       string          `json:"role"`
+	Status     string          `json:"status"`
+	InvitedAt  int64           `json:"invited_at"`
+	ExpiresAt  int64           `json:"expires_at"`
+	AcceptedAt int64           `json:"accepted_at,omitempty"`
+	Projects   []InviteProject `json:"projects,omitempty"`
+}
+
+// Invites Email to the organization with Role, and to Projects once the invite is accepted.
+type InviteRequest struct {
+	Email    string          `json:"email"`
+	Role     string          `json:"role"`
+	Projects []InviteProject `json:"projects,omitempty"`
+}
+
+type Project struct {
+	ID         string `json:"id"`
+	Object     string `json:"object"`
+	Name       string `json:"name"`
+	CreatedAt  int64  `json:"created_at"`
+	ArchivedAt int64  `json:"archived_at,omitempty"`
+	Status     string `json:"status"`
+}
+
+type ProjectUser struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	Role    string `json:"role"`
+	AddedAt int64  `json:"added_at"`
+}
+
+type ProjectServiceAccountAPIKey struct {
+	ID        string `json:"id"`
+	Object    string `json:"object"`
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// APIKey is only set in the response of CreateProjectServiceAccount, it is the only time
+// the key value is returned.
+type ProjectServiceAccount struct {
+	ID        string                       `json:"id"`
+	Object    string                       `json:"object"`
+	Name      string                       `json:"name"`
+	Role      string                       `json:"role"`
+	CreatedAt int64                        `json:"created_at"`
+	APIKey    *ProjectServiceAccountAPIKey `json:"api_key,omitempty"`
+}
+
+// Type is user or service_account, the matching field is set.
+type ProjectAPIKeyOwner struct {
+	Type           string                 `json:"type"`
+	User           *ProjectUser           `json:"user,omitempty"`
+	ServiceAccount *ProjectServiceAccount `json:"service_account,omitempty"`
+}
+
+type ProjectAPIKey struct {
+	ID            string             `json:"id"`
+	Object        string             `json:"object"`
+	Name          string             `json:"name"`
+	RedactedValue string             `json:"redacted_value"`
+	CreatedAt     int64              `json:"created_at"`
+	LastUsedAt    int64              `json:"last_used_at,omitempty"`
+	Owner         ProjectAPIKeyOwner `json:"owner"`
+}
+
+type ProjectRateLimit struct {
+	ID                          string `json:"id"`
+	Object                      string `json:"object"`
+	Model                       string `json:"model"`
+	MaxRequestsPer1Minute       int    `json:"max_requests_per_1_minute"`
+	MaxTokensPer1Minute         int    `json:"max_tokens_per_1_minute"`
+	MaxImagesPer1Minute         int    `json:"max_images_per_1_minute,omitempty"`
+	MaxAudioMegabytesPer1Minute int    `json:"max_audio_megabytes_per_1_minute,omitempty"`
+	MaxRequestsPer1Day          int    `json:"max_requests_per_1_day,omitempty"`
+	Batch1DayMaxInputTokens     int    `json:"batch_1_day_max_input_tokens,omitempty"`
+}
+
+// Zero values leave the limit unchanged. Limits can only be lowered below the
+// organization limits of the model.
+type ProjectRateLimitRequest struct {
+	MaxRequestsPer1Minute       int `json:"max_requests_per_1_minute,omitempty"`
+	MaxTokensPer1Minute         int `json:"max_tokens_per_1_minute,omitempty"`
+	MaxImagesPer1Minute         int `json:"max_images_per_1_minute,omitempty"`
+	MaxAudioMegabytesPer1Minute int `json:"max_audio_megabytes_per_1_minute,omitempty"`
+	MaxRequestsPer1Day          int `json:"max_requests_per_1_day,omitempty"`
+	Batch1DayMaxInputTokens     int `json:"batch_1_day_max_input_tokens,omitempty"`
+}
+
+type roleRequest struct {
+	Role string `json:"role"`
+}
+
+type nameRequest struct {
+	Name string `json:"name"`
+}
+
+type projectUserRequest struct {
+	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+func projectPath(projectID string, parts ...string) string {
+	path := "organization/projects/" + projectID
+	for _, p := range parts {
+		path += "/" + p
+	}
+	return path
+}
+
+// Utilizes the ListUsers OpenAI Admin API. params may be nil.
+//
+// @Returns a page of openai.OrganizationUser.
+func (a *AdminClient) ListUsers(ctx context.Context, params *ListParams) (List[OrganizationUser], error) {
+	var res List[OrganizationUser]
+	err := a.client.sendJSON(ctx, http.MethodGet, params.encode("organization/users"), nil, &res)
+	return res, err
+}
+
+// Iterates over every openai.OrganizationUser, fetching the pages as needed. params may be nil.
+func (a *AdminClient) Users(ctx context.Context, params *ListParams) iter.Seq2[OrganizationUser, error] {
+	return paginate(ctx, params, a.ListUsers)
+}
+
+// Utilizes the RetrieveUser OpenAI Admin API.
+//
+// @Returns openai.OrganizationUser.
+func (a *AdminClient) GetUser(ctx context.Context, userID string) (OrganizationUser, error) {
+	var res OrganizationUser
+	err := a.client.sendJSON(ctx, http.MethodGet, "organization/users/"+userID, nil, &res)
+	return res, err
+}
+
+// Utilizes the ModifyUser OpenAI Admin API to change the organization role of the user.
+//
+// @Returns the modified openai.OrganizationUser.
+func (a *AdminClient) ModifyUserRole(ctx context.Context, userID, role string) (OrganizationUser, error) {
+	var res OrganizationUser
+	err := a.client.sendJSON(ctx, http.MethodPost, "organization/users/"+userID, roleRequest{Role: role}, &res)
+	return res, err
+}
+
+// Utilizes the DeleteUser OpenAI Admin API to remove the user from the organization.
+//
+// @Returns openai.DeletionStatus.
+func (a *AdminClient) DeleteUser(ctx context.Context, userID string) (DeletionStatus, error) {
+	var res DeletionStatus
+	err := a.client.sendJSON(ctx, http.MethodDelete, "organization/users/"+userID, nil, &res)
+	return res, err
+}
+
+// Utilizes the ListInvites OpenAI Admin API. params may be nil.
+//
+// @Returns a page of openai.Invite.
+func (a *AdminClient) ListInvites(ctx context.Context, params *ListParams) (List[Invite], error) {
+	var res List[Invite]
+	err := a.client.sendJSON(ctx, http.MethodGet, params.encode("organization/invites"), nil, &res)
+	return res, err
+}
+
+// Iterates over every openai.Invite, fetching the pages as needed. params may be nil.
+func (a *AdminClient) Invites(ctx context.Context, params *ListParams) iter.Seq2[Invite, error] {
+	return paginate(ctx, params, a.ListInvites)
+}
+
+// Utilizes the CreateInvite OpenAI Admin API.
+//
+// @Returns the pending openai.Invite.
+func (a *AdminClient) CreateInvite(ctx context.Context, inviteReq *InviteRequest) (Invite, error) {
+	var res Invite
+	err := a.client.sendJSON(ctx, http.MethodPost, "organization/invites", inviteReq, &res)
+	return res, err
+}
+
+// Utilizes the RetrieveInvite OpenAI Admin API.
+//
+// @Returns openai.Invite.
+func (a *AdminClient) GetInvite(ctx context.Context, inviteID string) (Invite, error) {
+	var res Invite
+	err := a.client.sendJSON(ctx, http.MethodGet, "organization/invites/"+inviteID, nil, &res)
+	return res, err
+}
+
+// Utilizes the DeleteInvite OpenAI Admin API. Only pending invites can be deleted.
+//
+// @Returns openai.DeletionStatus.
+func (a *AdminClient) DeleteInvite(ctx context.Context, inviteID string) (DeletionStatus, error) {
+	var res DeletionStatus
+	err := a.client.sendJSON(ctx, http.MethodDelete, "organization/invites/"+inviteID, nil, &res)
+	return res, err
+}
+
+// Utilizes the ListProjects OpenAI Admin API. params may be nil. Archived projects are
+// only listed with includeArchived.
+//
+// @Returns a page of openai.Project.
+func (a *AdminClient) ListProjects(ctx context.Context, params *ListParams, includeArchived bool) (List[Project], error) {
+	var res List[Project]
+	q := url.Values{}
+	if includeArchived {
+		q.Set("include_archived", "true")
+	}
+	err := a.client.sendJSON(ctx, http.MethodGet, params.encodeWith("organization/projects", q), nil, &res)
+	return res, err
+}
+
+// Iterates over every openai.Project, fetching the pages as needed. params may be nil.
+func (a *AdminClient) Projects(ctx context.Context, params *ListParams, includeArchived bool) iter.Seq2[Project, error] {
+	return paginate(ctx, params, func(ctx context.Context, params *ListParams) (List[Project], error) {
+		return a.ListProjects(ctx, params, includeArchived)
+	})
+}
+
+// Utilizes the CreateProject OpenAI Admin API.
+//
+// @Returns the created openai.Project.
+func (a *AdminClient) CreateProject(ctx context.Context, name string) (Project, error) {
+	var res Project
+	err := a.client.sendJSON(ctx, http.MethodPost, "organization/projects", nameRequest{Name: name}, &res)
+	return res, err
+}
+
+// Utilizes the RetrieveProject OpenAI Admin API.
+//
+// @Returns openai.Project.
+func (a *AdminClient) GetProject(ctx context.Context, projectID string) (Project, error) {
+	var res Project
+	err := a.client.sendJSON(ctx, http.MethodGet, projectPath(projectID), nil, &res)
+	return res, err
+}
+
+// Utilizes the ModifyProject OpenAI Admin API to rename the project.
+//
+// @Returns the modified openai.Project.
+func (a *AdminClient) ModifyProject(ctx context.Context, projectID, name string) (Project, error) {
+	var res Project
+	err := a.client.sendJSON(ctx, http.MethodPost, projectPath(projectID), nameRequest{Name: name}, &res)
+	return res, err
+}
+
+// Utilizes the ArchiveProject OpenAI Admin API. Archived projects cannot be used or updated.
+//
+// @Returns the archived openai.Project.
+func (a *AdminClient) ArchiveProject(ctx context.Context, projectID string) (Project, error) {
+	var res Project
+	err := a.client.sendJSON(ctx, http.MethodPost, projectPath(projectID, "archive"), nil, &res)
+	return res, err
+}
+
+// Utilizes the ListProjectUsers OpenAI Admin API. params may be nil.
+//
+// @Returns a page of openai.ProjectUser.
+func (a *AdminClient) ListProjectUsers(ctx context.Context, projectID string, params *ListParams) (List[ProjectUser], error) {
+	var res List[ProjectUser]
+	err := a.client.sendJSON(ctx, http.MethodGet, params.encode(projectPath(projectID, "users")), nil, &res)
+	return res, err
+}
+
+// Iterates over every openai.ProjectUser of the project, fetching the pages as needed. params may be nil.
+func (a *AdminClient) ProjectUsers(ctx context.Context, projectID string, params *ListParams) iter.Seq2[ProjectUser, error] {
+	return paginate(ctx, params, func(ctx context.Context, params *ListParams) (List[ProjectUser], error) {
+		return a.ListProjectUsers(ctx, projectID, params)
+	})
+}
+
+// Utilizes the CreateProjectUser OpenAI Admin API to add an organization user to the project.
+//
+// @Returns openai.ProjectUser.
+func (a *AdminClient) AddProjectUser(ctx context.Context, projectID, userID, role string) (ProjectUser, error) {
+	var res ProjectUser
+	body := projectUserRequest{UserID: userID, Role: role}
+	err := a.client.sendJSON(ctx, http.MethodPost, projectPath(projectID, "users"), body, &res)
+	return res, err
+}
+
+// Utilizes the RetrieveProjectUser OpenAI Admin API.
+//
+// @Returns openai.ProjectUser.
+func (a *AdminClient) GetProjectUser(ctx context.Context, projectID, userID string) (ProjectUser, error) {
+	var res ProjectUser
+	err := a.client.sendJSON(ctx, http.MethodGet, projectPath(projectID, "users", userID), nil, &res)
+	return res, err
+}
+
+// Utilizes the ModifyProjectUser OpenAI Admin API to change the project role of the user.
+//
+// @Returns the modified openai.ProjectUser.
+func (a *AdminClient) ModifyProjectUserRole(ctx context.Context, projectID, userID, role string) (ProjectUser, error) {
+	var res ProjectUser
+	err := a.client.sendJSON(ctx, http.MethodPost, projectPath(projectID, "users", userID), roleRequest{Role: role}, &res)
+	return res, err
+}
+
+// Utilizes the DeleteProjectUser OpenAI Admin API to remove the user from the project.
+//
+// @Returns openai.DeletionStatus.
+func (a *AdminClient) DeleteProjectUser(ctx context.Context, projectID, userID string) (DeletionStatus, error) {
+	var res DeletionStatus
+	err := a.client.sendJSON(ctx, http.MethodDelete, projectPath(projectID, "users", userID), nil, &res)
+	return res, err
+}
+
+// Utilizes the ListProjectServiceAccounts OpenAI Admin API. params may be nil.
+//
+// @Returns a page of openai.ProjectServiceAccount.
+func (a *AdminClient) ListProjectServiceAccounts(ctx context.Context, projectID string, params *ListParams) (List[ProjectServiceAccount], error) {
+	var res List[ProjectServiceAccount]
+	err := a.client.sendJSON(ctx, http.MethodGet, params.encode(projectPath(projectID, "service_accounts")), nil, &res)
+	return res, err
+}
+
+// Iterates over every openai.ProjectServiceAccount of the project, fetching the pages as needed. params may be nil.
+func (a *AdminClient) ProjectServiceAccounts(ctx context.Context, projectID string, params *ListParams) iter.Seq2[ProjectServiceAccount, error] {
+	return paginate(ctx, params, func(ctx context.Context, params *ListParams) (List[ProjectServiceAccount], error) {
+		return a.ListProjectServiceAccounts(ctx, projectID, params)
+	})
+}
+
+// Utilizes the CreateProjectServiceAccount OpenAI Admin API.
+//
+// @Returns the openai.ProjectServiceAccount together with its API key.
+func (a *AdminClient) CreateProjectServiceAccount(ctx context.Context, projectID, name string) (ProjectServiceAccount, error) {
+	var res ProjectServiceAccount
+	err := a.client.sendJSON(ctx, http.MethodPost, projectPath(projectID, "service_accounts"), nameRequest{Name: name}, &res)
+	return res, err
+}
+
+// Utilizes the RetrieveProjectServiceAccount OpenAI Admin API.
+//
+// @Returns openai.ProjectServiceAccount.
+func (a *AdminClient) GetProjectServiceAccount(ctx context.Context, projectID, serviceAccountID string) (ProjectServiceAccount, error) {
+	var res ProjectServiceAccount
+	err := a.client.sendJSON(ctx, http.MethodGet, projectPath(projectID, "service_accounts", serviceAccountID), nil, &res)
+	return res, err
+}
+
+// Utilizes the DeleteProjectServiceAccount OpenAI Admin API.
+//
+// @Returns openai.DeletionStatus.
+func (a *AdminClient) DeleteProjectServiceAccount(ctx context.Context, projectID, serviceAccountID string) (DeletionStatus, error) {
+	var res DeletionStatus
+	err := a.client.sendJSON(ctx, http.MethodDelete, projectPath(projectID, "service_accounts", serviceAccountID), nil, &res)
+	return res, err
+}
+
+// Utilizes the ListProjectAPIKeys OpenAI Admin API. params may be nil.
+//
+// @Returns a page of openai.ProjectAPIKey.
+func (a *AdminClient) ListProjectAPIKeys(ctx context.Context, projectID string, params *ListParams) (List[ProjectAPIKey], error) {
+	var res List[ProjectAPIKey]
+	err := a.client.sendJSON(ctx, http.MethodGet, params.encode(projectPath(projectID, "api_keys")), nil, &res)
+	return res, err
+}
+
+// Iterates over every openai.ProjectAPIKey of the project, fetching the pages as needed. params may be nil.
+func (a *AdminClient) ProjectAPIKeys(ctx context.Context, projectID string, params *ListParams) iter.Seq2[ProjectAPIKey, error] {
+	return paginate(ctx, params, func(ctx context.Context, params *ListParams) (List[ProjectAPIKey], error) {
+		return a.ListProjectAPIKeys(ctx, projectID, params)
+	})
+}
+
+// Utilizes the RetrieveProjectAPIKey OpenAI Admin API.
+//
+// @Returns openai.ProjectAPIKey.
+func (a *AdminClient) GetProjectAPIKey(ctx context.Context, projectID, keyID string) (ProjectAPIKey, error) {
+	var res ProjectAPIKey
+	err := a.client.sendJSON(ctx, http.MethodGet, projectPath(projectID, "api_keys", keyID), nil, &res)
+	return res, err
+}
+
+// Utilizes the DeleteProjectAPIKey OpenAI Admin API.
+//
+// @Returns openai.DeletionStatus.
+func (a *AdminClient) DeleteProjectAPIKey(ctx context.Context, projectID, keyID string) (DeletionStatus, error) {
+	var res DeletionStatus
+	err := a.client.sendJSON(ctx, http.MethodDelete, projectPath(projectID, "api_keys", keyID), nil, &res)
+	return res, err
+}
+
+// Utilizes the ListProjectRateLimits OpenAI Admin API. params may be nil.
+//
+// @Returns a page of openai.ProjectRateLimit, one per model.
+func (a *AdminClient) ListProjectRateLimits(ctx context.Context, projectID string, params *ListParams) (List[ProjectRateLimit], error) {
+	var res List[ProjectRateLimit]
+	err := a.client.sendJSON(ctx, http.MethodGet, params.encode(projectPath(projectID, "rate_limits")), nil, &res)
+	return res, err
+}
+
+// Iterates over every openai.ProjectRateLimit of the project, fetching the pages as needed. params may be nil.
+func (a *AdminClient) ProjectRateLimits(ctx context.Context, projectID string, params *ListParams) iter.Seq2[ProjectRateLimit, error] {
+	return paginate(ctx, params, func(ctx context.Context, params *ListParams) (List[ProjectRateLimit], error) {
+		return a.ListProjectRateLimits(ctx, projectID, params)
+	})
+}
+
+// Utilizes the ModifyProjectRateLimit OpenAI Admin API.
+//
+// @Returns the modified openai.ProjectRateLimit.
+func (a *AdminClient) ModifyProjectRateLimit(ctx context.Context, projectID, rateLimitID string, limitReq *ProjectRateLimitRequest) (ProjectRateLimit, error) {
+	var res ProjectRateLimit
+	err := a.client.sendJSON(ctx, http.MethodPost, projectPath(projectID, "rate_limits", rateLimitID), limitReq, &res)
+	return res, err
+}
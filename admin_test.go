@@ -0,0 +1,96 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	. "github.com/EthanCampana/go-openai"
+)
+
+func newTestAdminClient(t *testing.T, handler http.HandlerFunc) *AdminClient {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	target, _ := url.Parse(srv.URL)
+	return GetAdminClient("admin-key").SetHTTPClient(&http.Client{Transport: rewriteTransport{target: target}})
+}
+
+func TestAdminClient_ProvisionProject(t *testing.T) {
+	var requests []string
+	admin := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer admin-key" {
+			t.Errorf("Authorization = %q", auth)
+		}
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v1/organization/projects":
+			fmt.Fprint(w, `{"object":"list","data":[{"id":"proj_old","status":"archived","archived_at":1700000000}],"has_more":false}`)
+		case "POST /v1/organization/projects":
+			fmt.Fprintf(w, `{"id":"proj_1","object":"organization.project","name":%q,"status":"active"}`, body["name"])
+		case "POST /v1/organization/projects/proj_1/users":
+			if body["user_id"] != "user_1" || body["role"] != ProjectRoleOwner {
+				t.Errorf("add user body = %v", body)
+			}
+			fmt.Fprint(w, `{"id":"user_1","object":"organization.project.user","role":"owner"}`)
+		case "POST /v1/organization/projects/proj_1/service_accounts":
+			fmt.Fprintf(w, `{"id":"svc_1","name":%q,"role":"member","api_key":{"id":"key_1","value":"sk-svcacct-secret"}}`, body["name"])
+		case "POST /v1/organization/projects/proj_1/rate_limits/rl-gpt-4o":
+			if body["max_requests_per_1_minute"] != 100.0 || len(body) != 1 {
+				t.Errorf("rate limit body = %v", body)
+			}
+			fmt.Fprint(w, `{"id":"rl-gpt-4o","model":"gpt-4o","max_requests_per_1_minute":100,"max_tokens_per_1_minute":30000}`)
+		case "GET /v1/organization/projects/proj_1/api_keys/key_2":
+			w.Header().Set("x-request-id", "req_404")
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"type":"invalid_request_error","code":"not_found","message":"No such API key"}}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+	ctx := context.Background()
+
+	projects, err := admin.ListProjects(ctx, &ListParams{Limit: 10}, true)
+	if err != nil || len(projects.Data) != 1 || projects.Data[0].Status != ProjectStatusArchived {
+		t.Fatalf("ListProjects() = %+v, %v", projects, err)
+	}
+	project, err := admin.CreateProject(ctx, "team-a")
+	if err != nil || project.ID != "proj_1" || project.Name != "team-a" {
+		t.Fatalf("CreateProject() = %+v, %v", project, err)
+	}
+	if _, err = admin.AddProjectUser(ctx, project.ID, "user_1", ProjectRoleOwner); err != nil {
+		t.Fatal(err)
+	}
+	account, err := admin.CreateProjectServiceAccount(ctx, project.ID, "ci")
+	if err != nil || account.APIKey == nil || account.APIKey.Value != "sk-svcacct-secret" {
+		t.Fatalf("CreateProjectServiceAccount() = %+v, %v", account, err)
+	}
+	limit, err := admin.ModifyProjectRateLimit(ctx, project.ID, "rl-gpt-4o", &ProjectRateLimitRequest{MaxRequestsPer1Minute: 100})
+	if err != nil || limit.MaxRequestsPer1Minute != 100 || limit.MaxTokensPer1Minute != 30000 {
+		t.Fatalf("ModifyProjectRateLimit() = %+v, %v", limit, err)
+	}
+
+	_, err = admin.GetProjectAPIKey(ctx, project.ID, "key_2")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("GetProjectAPIKey() error = %v, want an *APIError", err)
+	}
+	want := APIError{StatusCode: 404, Type: "invalid_request_error", Code: "not_found", Message: "No such API key", RequestID: "req_404"}
+	if *apiErr != want {
+		t.Errorf("APIError = %+v, want %+v", *apiErr, want)
+	}
+	if err.Error() != "error, status code: 404, message: No such API key" || apiErr.Temporary() {
+		t.Errorf("Error() = %q, Temporary() = %v", err, apiErr.Temporary())
+	}
+
+	if requests[0] != "GET /v1/organization/projects?include_archived=true&limit=10" {
+		t.Errorf("ListProjects request = %q", requests[0])
+	}
+}
@@ -0,0 +1,126 @@
+package openai
+
+import (
+	"context"
+	"iter"
+	"net/http"
+)
+
+const (
+	AssistantToolTypeCodeInterpreter = "code_interpreter"
+	AssistantToolTypeFileSearch      = "file_search"
+	AssistantToolTypeFunction        = ToolTypeFunction
+)
+
+// A tool of an assistant: code_interpreter, file_search or a function.
+type AssistantTool struct {
+	Type       string              `json:"type"`
+	Function   *FunctionDefinition `json:"function,omitempty"`
+	FileSearch *FileSearchOptions  `json:"file_search,omitempty"`
+}
+
+type FileSearchOptions struct {
+	MaxNumResults int `json:"max_num_results,omitempty"`
+}
+
+// Creates a function tool for an assistant from a chat completion Tool.
+func NewAssistantFunctionTool(tool Tool) AssistantTool {
+	fn := tool.Function
+	return AssistantTool{Type: AssistantToolTypeFunction, Function: &fn}
+}
+
+// Files made available to the tools of an assistant or thread.
+type ToolResources struct {
+	CodeInterpreter *CodeInterpreterResources `json:"code_interpreter,omitempty"`
+	FileSearch      *FileSearchResources      `json:"file_search,omitempty"`
+}
+
+type CodeInterpreterResources struct {
+	FileIDs []string `json:"file_ids,omitempty"`
+}
+
+type FileSearchResources struct {
+	VectorStoreIDs []string `json:"vector_store_ids,omitempty"`
+}
+
+type Assistant struct {
+	ID            string            `json:"id"`
+	Object        string            `json:"object"`
+	CreatedAt     int64             `json:"created_at"`
+	Name          string            `json:"name,omitempty"`
+	Description   string            `json:"description,omitempty"`
+	Model         string            `json:"model"`
+	Instructions  string            `json:"instructions,omitempty"`
+	Tools         []AssistantTool   `json:"tools"`
+	ToolResources *ToolResources    `json:"tool_resources,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	Temperature   *float32          `json:"temperature,omitempty"`
+	TopP          *float32          `json:"top_p,omitempty"`
+	// "auto" or a ChatResponseFormat.
+	ResponseFormat interface{} `json:"response_format,omitempty"`
+}
+
+// Creates or modifies an assistant. Model is required on creation, fields left empty are
+// not changed by ModifyAssistant.
+type AssistantRequest struct {
+	Model          string            `json:"model,omitempty"`
+	Name           string            `json:"name,omitempty"`
+	Description    string            `json:"description,omitempty"`
+	Instructions   string            `json:"instructions,omitempty"`
+	Tools          []AssistantTool   `json:"tools,omitempty"`
+	ToolResources  *ToolResources    `json:"tool_resources,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+	Temperature    *float32          `json:"temperature,omitempty"`
+	TopP           *float32          `json:"top_p,omitempty"`
+	ResponseFormat interface{}       `json:"response_format,omitempty"`
+}
+
+// Utilizes the CreateAssistant OpenAI API.
+//
+// @Returns the created openai.Assistant.
+func (c *Client) CreateAssistant(ctx context.Context, assistantReq *AssistantRequest) (Assistant, error) {
+	var res Assistant
+	err := c.sendJSON(ctx, http.MethodPost, "assistants", assistantReq, &res)
+	return res, err
+}
+
+// Utilizes the RetrieveAssistant OpenAI API.
+//
+// @Returns openai.Assistant.
+func (c *Client) GetAssistant(ctx context.Context, assistantID string) (Assistant, error) {
+	var res Assistant
+	err := c.sendJSON(ctx, http.MethodGet, "assistants/"+assistantID, nil, &res)
+	return res, err
+}
+
+// Utilizes the ModifyAssistant OpenAI API.
+//
+// @Returns the modified openai.Assistant.
+func (c *Client) ModifyAssistant(ctx context.Context, assistantID string, assistantReq *AssistantRequest) (Assistant, error) {
+	var res Assistant
+	err := c.sendJSON(ctx, http.MethodPost, "assistants/"+assistantID, assistantReq, &res)
+	return res, err
+}
+
+// Utilizes the DeleteAssistant OpenAI API.
+//
+// @Returns openai.DeletionStatus.
+func (c *Client) DeleteAssistant(ctx context.Context, assistantID string) (DeletionStatus, error) {
+	var res DeletionStatus
+	err := c.sendJSON(ctx, http.MethodDelete, "assistants/"+assistantID, nil, &res)
+	return res, err
+}
+
+// Utilizes the ListAssistants OpenAI API. params may be nil.
+//
+// @Returns a page of openai.Assistant.
+func (c *Client) ListAssistants(ctx context.Context, params *ListParams) (List[Assistant], error) {
+	var res List[Assistant]
+	err := c.sendJSON(ctx, http.MethodGet, params.encode("assistants"), nil, &res)
+	return res, err
+}
+
+// Iterates over every openai.Assistant, fetching the pages as needed. params may be nil.
+func (c *Client) Assistants(ctx context.Context, params *ListParams) iter.Seq2[Assistant, error] {
+	return paginate(ctx, params, c.ListAssistants)
+}
@@ -0,0 +1,189 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/EthanCampana/go-openai"
+)
+
+func TestClient_Assistants(t *testing.T) {
+	var got []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Method+" "+r.URL.RequestURI())
+		if beta := r.Header.Get("OpenAI-Beta"); beta != "assistants=v2" {
+			t.Errorf("%s OpenAI-Beta = %q, want assistants=v2", r.URL.Path, beta)
+		}
+		switch {
+		case r.Method == http.MethodDelete:
+			fmt.Fprint(w, `{"id":"asst_1","object":"assistant.deleted","deleted":true}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/assistants":
+			fmt.Fprint(w, `{"object":"list","data":[{"id":"asst_1"}],"first_id":"asst_1","last_id":"asst_1","has_more":false}`)
+		case strings.HasPrefix(r.URL.Path, "/v1/assistants"):
+			fmt.Fprint(w, `{"id":"asst_1","object":"assistant","model":"gpt-4o","tools":[{"type":"code_interpreter"}],"response_format":"auto"}`)
+		case r.URL.Path == "/v1/threads/thread_1/messages":
+			fmt.Fprint(w, `{"id":"msg_1","thread_id":"thread_1","role":"user","content":[{"type":"text","text":{"value":"hello","annotations":[]}}]}`)
+		default:
+			fmt.Fprint(w, `{"id":"thread_1","object":"thread"}`)
+		}
+	})
+	ctx := context.Background()
+
+	asst, err := client.CreateAssistant(ctx, &AssistantRequest{
+		Model: "gpt-4o",
+		Tools: []AssistantTool{{Type: AssistantToolTypeCodeInterpreter}},
+	})
+	if err != nil || asst.ID != "asst_1" || asst.ResponseFormat != "auto" {
+		t.Fatalf("CreateAssistant() = %+v, %v", asst, err)
+	}
+	if _, err = client.ModifyAssistant(ctx, "asst_1", &AssistantRequest{Name: "Math"}); err != nil {
+		t.Fatal(err)
+	}
+	list, err := client.ListAssistants(ctx, &ListParams{Limit: 10, Order: ListOrderDesc})
+	if err != nil || len(list.Data) != 1 || list.LastID != "asst_1" {
+		t.Fatalf("ListAssistants() = %+v, %v", list, err)
+	}
+	if del, err := client.DeleteAssistant(ctx, "asst_1"); err != nil || !del.Deleted {
+		t.Fatalf("DeleteAssistant() = %+v, %v", del, err)
+	}
+	thread, err := client.CreateThread(ctx, nil)
+	if err != nil || thread.ID != "thread_1" {
+		t.Fatalf("CreateThread() = %+v, %v", thread, err)
+	}
+	msg, err := client.CreateMessage(ctx, thread.ID, &MessageRequest{Role: ChatMessageRoleUser, Content: "hello"})
+	if err != nil || msg.Text() != "hello" {
+		t.Fatalf("CreateMessage() = %+v, %v", msg, err)
+	}
+
+	want := []string{
+		"POST /v1/assistants",
+		"POST /v1/assistants/asst_1",
+		"GET /v1/assistants?limit=10&order=desc",
+		"DELETE /v1/assistants/asst_1",
+		"POST /v1/threads",
+		"POST /v1/threads/thread_1/messages",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+}
+
+func TestClient_WaitForRun(t *testing.T) {
+	statuses := []string{RunStatusQueued, RunStatusInProgress, RunStatusRequiresAction}
+	polls := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/threads/thread_1/runs/run_1" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		status := statuses[polls]
+		polls++
+		fmt.Fprintf(w, `{"id":"run_1","thread_id":"thread_1","status":%q,"required_action":{"type":"submit_tool_outputs",`+
+			`"submit_tool_outputs":{"tool_calls":[{"id":"call_1","type":"function","function":{"name":"add","arguments":"{}"}}]}}}`, status)
+	})
+	run, err := client.WaitForRun(context.Background(), "thread_1", "run_1", time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if polls != 3 || run.Status != RunStatusRequiresAction || len(run.ToolCalls()) != 1 {
+		t.Errorf("WaitForRun() = %+v after %d polls", run, polls)
+	}
+}
+
+func TestRunStream_Consume(t *testing.T) {
+	var submitted []ToolOutput
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Stream      bool         `json:"stream"`
+			ToolOutputs []ToolOutput `json:"tool_outputs"`
+		}
+		b, _ := io.ReadAll(r.Body)
+		json.Unmarshal(b, &body)
+		if !body.Stream {
+			t.Errorf("%s was sent without stream", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		switch r.URL.Path {
+		case "/v1/threads/thread_1/runs":
+			fmt.Fprint(w, "event: thread.run.created\ndata: {\"id\":\"run_1\",\"thread_id\":\"thread_1\",\"status\":\"queued\"}\n\n")
+			fmt.Fprint(w, "event: thread.run.requires_action\ndata: {\"id\":\"run_1\",\"thread_id\":\"thread_1\",\"status\":\"requires_action\","+
+				"\"required_action\":{\"type\":\"submit_tool_outputs\",\"submit_tool_outputs\":{\"tool_calls\":"+
+				"[{\"id\":\"call_1\",\"type\":\"function\",\"function\":{\"name\":\"add\",\"arguments\":\"{\\\"a\\\":1,\\\"b\\\":2}\"}}]}}}\n\n")
+		case "/v1/threads/thread_1/runs/run_1/submit_tool_outputs":
+			submitted = body.ToolOutputs
+			fmt.Fprint(w, "event: thread.message.delta\ndata: {\"id\":\"msg_1\",\"delta\":{\"content\":[{\"index\":0,\"type\":\"text\",\"text\":{\"value\":\"The sum \"}}]}}\n\n")
+			fmt.Fprint(w, "event: thread.message.delta\ndata: {\"id\":\"msg_1\",\"delta\":{\"content\":[{\"index\":0,\"type\":\"text\",\"text\":{\"value\":\"is 3\"}}]}}\n\n")
+			fmt.Fprint(w, "event: thread.run.completed\ndata: {\"id\":\"run_1\",\"thread_id\":\"thread_1\",\"status\":\"completed\"}\n\n")
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		fmt.Fprint(w, "event: done\ndata: [DONE]\n\n")
+	})
+	tools := NewToolRunner(client)
+	type addArgs struct {
+		A int `json:"a"`
+		B int `json:"b"`
+	}
+	RegisterTool(tools, "add", "Adds two numbers", func(ctx context.Context, args addArgs) (int, error) {
+		return args.A + args.B, nil
+	})
+
+	ctx := context.Background()
+	stream, err := client.CreateRunStream(ctx, "thread_1", &RunRequest{AssistantID: "asst_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var text strings.Builder
+	run, err := stream.Consume(RunEventHandler{
+		Tools: tools,
+		OnTextDelta: func(messageID, delta string) error {
+			text.WriteString(delta)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Status != RunStatusCompleted {
+		t.Errorf("Consume() status = %s, want completed", run.Status)
+	}
+	if len(submitted) != 1 || submitted[0] != (ToolOutput{ToolCallID: "call_1", Output: "3"}) {
+		t.Errorf("submitted tool outputs = %+v", submitted)
+	}
+	if text.String() != "The sum is 3" {
+		t.Errorf("streamed text = %q", text.String())
+	}
+}
+
+func TestRunStream_ErrorEvent(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: error\ndata: {\"code\":\"server_error\",\"message\":\"overloaded\"}\n\n")
+	})
+	stream, err := client.CreateRunStream(context.Background(), "thread_1", &RunRequest{AssistantID: "asst_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+	_, err = stream.Recv()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 0 || apiErr.Code != "server_error" || apiErr.Message != "overloaded" {
+		t.Errorf("Recv() error = %#v, want an *APIError with status 0", err)
+	}
+}
+
+func TestClient_ModifyThread_NilRequest(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"thread_1","object":"thread"}`)
+	})
+	thread, err := client.ModifyThread(context.Background(), "thread_1", nil)
+	if err != nil || thread.ID != "thread_1" {
+		t.Errorf("ModifyThread(nil) = %+v, %v", thread, err)
+	}
+}
@@ -0,0 +1,268 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Types of audit log events.
+const (
+	AuditLogAPIKeyCreated         = "api_key.created"
+	AuditLogAPIKeyUpdated         = "api_key.updated"
+	AuditLogAPIKeyDeleted         = "api_key.deleted"
+	AuditLogInviteSent            = "invite.sent"
+	AuditLogInviteAccepted        = "invite.accepted"
+	AuditLogInviteDeleted         = "invite.deleted"
+	AuditLogLoginSucceeded        = "login.succeeded"
+	AuditLogLoginFailed           = "login.failed"
+	AuditLogLogoutSucceeded       = "logout.succeeded"
+	AuditLogLogoutFailed          = "logout.failed"
+	AuditLogOrganizationUpdated   = "organization.updated"
+	AuditLogProjectCreated        = "project.created"
+	AuditLogProjectUpdated        = "project.updated"
+	AuditLogProjectArchived       = "project.archived"
+	AuditLogRateLimitUpdated      = "rate_limit.updated"
+	AuditLogRateLimitDeleted      = "rate_limit.deleted"
+	AuditLogServiceAccountCreated = "service_account.created"
+	AuditLogServiceAccountUpdated = "service_account.updated"
+	AuditLogServiceAccountDeleted = "service_account.deleted"
+	AuditLogUserAdded             = "user.added"
+	AuditLogUserUpdated           = "user.updated"
+	AuditLogUserDeleted           = "user.deleted"
+)
+
+const (
+	AuditLogActorSession = "session"
+	AuditLogActorAPIKey  = "api_key"
+)
+
+// Filters of ListAuditLogs. Zero values do not filter.
+type AuditLogQuery struct {
+	// Only return events effective at or after Since and before Until.
+	Since time.Time
+	Until time.Time
+	// Return events that match any of the values of a filter.
+	ProjectIDs  []string
+	EventTypes  []string
+	ActorIDs    []string
+	ActorEmails []string
+	ResourceIDs []string
+	// Limit, After and Before paginate the events, Order is not supported.
+	ListParams
+}
+
+func (q *AuditLogQuery) encode(path string) string {
+	if q == nil {
+		return path
+	}
+	v := url.Values{}
+	if !q.Since.IsZero() {
+		v.Set("effective_at[gte]", strconv.FormatInt(q.Since.Unix(), 10))
+	}
+	if !q.Until.IsZero() {
+		v.Set("effective_at[lt]", strconv.FormatInt(q.Until.Unix(), 10))
+	}
+	for key, values := range map[string][]string{
+		"project_ids[]":  q.ProjectIDs,
+		"event_types[]":  q.EventTypes,
+		"actor_ids[]":    q.ActorIDs,
+		"actor_emails[]": q.ActorEmails,
+		"resource_ids[]": q.ResourceIDs,
+	} {
+		for _, value := range values {
+			v.Add(key, value)
+		}
+	}
+	return q.ListParams.encodeWith(path, v)
+}
+
+type AuditLogProject struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type AuditLogUser struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+}
+
+type AuditLogSession struct {
+	User      AuditLogUser `json:"user"`
+	IPAddress string       `json:"ip_address"`
+	UserAgent string       `json:"user_agent,omitempty"`
+}
+
+// Type is user or service_account, the matching field is set.
+type AuditLogAPIKey struct {
+	ID             string        `json:"id"`
+	Type           string        `json:"type"`
+	User           *AuditLogUser `json:"user,omitempty"`
+	ServiceAccount *struct {
+		ID string `json:"id"`
+	} `json:"service_account,omitempty"`
+}
+
+// Type is AuditLogActorSession or AuditLogActorAPIKey, the matching field is set.
+type AuditLogActor struct {
+	Type    string           `json:"type"`
+	Session *AuditLogSession `json:"session,omitempty"`
+	APIKey  *AuditLogAPIKey  `json:"api_key,omitempty"`
+}
+
+// Payload of api_key.* events.
+type AuditLogAPIKeyEvent struct {
+	ID   string `json:"id"`
+	Data *struct {
+		Scopes []string `json:"scopes"`
+	} `json:"data,omitempty"`
+	ChangesRequested *struct {
+		Scopes []string `json:"scopes"`
+	} `json:"changes_requested,omitempty"`
+}
+
+// Payload of invite.* events, Data is only set for invite.sent.
+type AuditLogInviteEvent struct {
+	ID   string `json:"id"`
+	Data *struct {
+		Email string `json:"email"`
+		Role  string `json:"role"`
+	} `json:"data,omitempty"`
+}
+
+// Payload of login.failed and logout.failed events.
+type AuditLogAuthFailedEvent struct {
+	ErrorCode    string `json:"error_code"`
+	ErrorMessage string `json:"error_message"`
+}
+
+// Payload of organization.updated events.
+type AuditLogOrganizationEvent struct {
+	ID               string `json:"id"`
+	ChangesRequested *struct {
+		Title       string            `json:"title,omitempty"`
+		Description string            `json:"description,omitempty"`
+		Name        string            `json:"name,omitempty"`
+		Settings    map[string]string `json:"settings,omitempty"`
+	} `json:"changes_requested,omitempty"`
+}
+
+// Payload of project.* events.
+type AuditLogProjectEvent struct {
+	ID   string `json:"id"`
+	Data *struct {
+		Name  string `json:"name"`
+		Title string `json:"title"`
+	} `json:"data,omitempty"`
+	ChangesRequested *struct {
+		Title string `json:"title"`
+	} `json:"changes_requested,omitempty"`
+}
+
+// Payload of rate_limit.* events. ChangesRequested holds the changed limits by their
+// ProjectRateLimit field name, e.g. max_requests_per_1_minute.
+type AuditLogRateLimitEvent struct {
+	ID               string           `json:"id"`
+	ChangesRequested map[string]int64 `json:"changes_requested,omitempty"`
+}
+
+// Payload of service_account.* and user.* events.
+type AuditLogRoleEvent struct {
+	ID   string `json:"id"`
+	Data *struct {
+		Role string `json:"role"`
+	} `json:"data,omitempty"`
+	ChangesRequested *struct {
+		Role string `json:"role"`
+	} `json:"changes_requested,omitempty"`
+}
+
+// An audit log event. The API stores the event payload under the event type, it is
+// kept in Payload and decoded into Details:
+//
+//   - api_key.*: *AuditLogAPIKeyEvent
+//   - invite.*: *AuditLogInviteEvent
+//   - login.failed, logout.failed: *AuditLogAuthFailedEvent
+//   - organization.updated: *AuditLogOrganizationEvent
+//   - project.*: *AuditLogProjectEvent
+//   - rate_limit.*: *AuditLogRateLimitEvent
+//   - service_account.*, user.*: *AuditLogRoleEvent
+//
+// Details is nil for events without payload and for event types unknown to this package.
+type AuditLog struct {
+	ID          string           `json:"id"`
+	Type        string           `json:"type"`
+	EffectiveAt int64            `json:"effective_at"`
+	Project     *AuditLogProject `json:"project,omitempty"`
+	Actor       AuditLogActor    `json:"actor"`
+	Payload     json.RawMessage  `json:"-"`
+	Details     interface{}      `json:"-"`
+}
+
+func (l *AuditLog) UnmarshalJSON(data []byte) error {
+	type auditLog AuditLog
+	if err := json.Unmarshal(data, (*auditLog)(l)); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	l.Payload = fields[l.Type]
+	l.Details = nil
+	if len(l.Payload) == 0 || string(l.Payload) == "null" {
+		return nil
+	}
+	var details interface{}
+	resource, _, _ := strings.Cut(l.Type, ".")
+	switch {
+	case resource == "api_key":
+		details = &AuditLogAPIKeyEvent{}
+	case resource == "invite":
+		details = &AuditLogInviteEvent{}
+	case l.Type == AuditLogLoginFailed || l.Type == AuditLogLogoutFailed:
+		details = &AuditLogAuthFailedEvent{}
+	case l.Type == AuditLogOrganizationUpdated:
+		details = &AuditLogOrganizationEvent{}
+	case resource == "project":
+		details = &AuditLogProjectEvent{}
+	case resource == "rate_limit":
+		details = &AuditLogRateLimitEvent{}
+	case resource == "service_account" || resource == "user":
+		details = &AuditLogRoleEvent{}
+	default:
+		return nil
+	}
+	if err := json.Unmarshal(l.Payload, details); err != nil {
+		return err
+	}
+	l.Details = details
+	return nil
+}
+
+// Utilizes the ListAuditLogs OpenAI Admin API. q may be nil.
+//
+// @Returns a page of openai.AuditLog, most recent first.
+func (a *AdminClient) ListAuditLogs(ctx context.Context, q *AuditLogQuery) (List[AuditLog], error) {
+	var res List[AuditLog]
+	err := a.client.sendJSON(ctx, http.MethodGet, q.encode("organization/audit_logs"), nil, &res)
+	return res, err
+}
+
+// Iterates over every audit log matching q, fetching the pages as needed, see Pager. q may be nil.
+func (a *AdminClient) AuditLogs(ctx context.Context, q *AuditLogQuery) iter.Seq2[AuditLog, error] {
+	var query AuditLogQuery
+	if q != nil {
+		query = *q
+	}
+	return paginate(ctx, &query.ListParams, func(ctx context.Context, params *ListParams) (List[AuditLog], error) {
+		pageQuery := query
+		pageQuery.ListParams = *params
+		return a.ListAuditLogs(ctx, &pageQuery)
+	})
+}
@@ -0,0 +1,85 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/EthanCampana/go-openai"
+)
+
+func TestAdminClient_AuditLogs(t *testing.T) {
+	var pages []string
+	admin := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/v1/organization/audit_logs" || q.Get("effective_at[gte]") != "1730419200" ||
+			len(q["event_types[]"]) != 2 || q.Get("limit") != "2" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		pages = append(pages, q.Get("after"))
+		switch q.Get("after") {
+		case "":
+			fmt.Fprint(w, `{"object":"list","has_more":true,"first_id":"audit_1","last_id":"audit_2","data":[
+				{"id":"audit_1","type":"api_key.created","effective_at":1730419300,
+				 "actor":{"type":"session","session":{"user":{"id":"user_1","email":"a@example.com"},"ip_address":"127.0.0.1"}},
+				 "api_key.created":{"id":"key_1","data":{"scopes":["/v1/*"]}}},
+				{"id":"audit_2","type":"user.updated","effective_at":1730419200,
+				 "actor":{"type":"api_key","api_key":{"id":"key_0","type":"service_account","service_account":{"id":"svc_1"}}},
+				 "user.updated":{"id":"user_2","changes_requested":{"role":"owner"}}}]}`)
+		case "audit_2":
+			fmt.Fprint(w, `{"object":"list","has_more":true,"first_id":"audit_3","last_id":"audit_4","data":[
+				{"id":"audit_3","type":"login.succeeded","effective_at":1730419200,"actor":{"type":"session"}},
+				{"id":"audit_4","type":"certificate.created","effective_at":1730419200,"actor":{"type":"session"},"certificate.created":{"id":"cert_1"}}]}`)
+		default:
+			fmt.Fprint(w, `{"object":"list","has_more":false,"data":[]}`)
+		}
+	})
+
+	q := &AuditLogQuery{
+		Since:      time.Unix(1730419200, 0),
+		EventTypes: []string{AuditLogAPIKeyCreated, AuditLogUserUpdated},
+		ListParams: ListParams{Limit: 2},
+	}
+	var logs []AuditLog
+	for log, err := range admin.AuditLogs(context.Background(), q) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		logs = append(logs, log)
+	}
+	if len(logs) != 4 || len(pages) != 3 || pages[1] != "audit_2" || pages[2] != "audit_4" {
+		t.Fatalf("got %d logs from pages %q", len(logs), pages)
+	}
+	if ev, ok := logs[0].Details.(*AuditLogAPIKeyEvent); !ok || ev.ID != "key_1" || ev.Data.Scopes[0] != "/v1/*" ||
+		logs[0].Actor.Session.User.Email != "a@example.com" {
+		t.Errorf("logs[0] = %+v, details %+v", logs[0], logs[0].Details)
+	}
+	if ev, ok := logs[1].Details.(*AuditLogRoleEvent); !ok || ev.ChangesRequested.Role != "owner" ||
+		logs[1].Actor.APIKey.ServiceAccount.ID != "svc_1" {
+		t.Errorf("logs[1] = %+v, details %+v", logs[1], logs[1].Details)
+	}
+	if logs[2].Details != nil || logs[3].Details != nil || string(logs[3].Payload) != `{"id":"cert_1"}` {
+		t.Errorf("logs[2:] = %+v", logs[2:])
+	}
+
+	pages = nil
+	for range admin.AuditLogs(context.Background(), q) {
+		break
+	}
+	if len(pages) != 1 {
+		t.Errorf("fetched %d pages after breaking out of the first one", len(pages))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var errs []error
+	for _, err := range admin.AuditLogs(ctx, q) {
+		errs = append(errs, err)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], context.Canceled) {
+		t.Errorf("errors = %v, want context.Canceled", errs)
+	}
+}
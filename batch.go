@@ -0,0 +1,417 @@
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	BatchStatusValidating = "validating"
+	BatchStatusFailed     = "failed"
+	BatchStatusInProgress = "in_progress"
+	BatchStatusFinalizing = "finalizing"
+	BatchStatusCompleted  = "completed"
+	BatchStatusExpired    = "expired"
+	BatchStatusCancelling = "cancelling"
+	BatchStatusCancelled  = "cancelled"
+)
+
+// Endpoints supported by the Batch API.
+const (
+	BatchEndpointChatCompletions = "/v1/chat/completions"
+	BatchEndpointCompletions     = "/v1/completions"
+	BatchEndpointEmbeddings      = "/v1/embeddings"
+	BatchEndpointModerations     = "/v1/moderations"
+	BatchEndpointResponses       = "/v1/responses"
+)
+
+// The only completion window supported by the Batch API.
+const BatchCompletionWindow24h = "24h"
+
+// Largest line accepted in batch output files; embedding responses can get long.
+const maxBatchLineSize = 64 << 20
+
+type BatchError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Param   string `json:"param,omitempty"`
+	Line    int    `json:"line,omitempty"`
+}
+
+func (e *BatchError) Error() string {
+	if e.Code == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+type BatchErrors struct {
+	Object string       `json:"object"`
+	Data   []BatchError `json:"data"`
+}
+
+type BatchRequestCounts struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+}
+
+type Batch struct {
+	ID               string             `json:"id"`
+	Object           string             `json:"object"`
+	Endpoint         string             `json:"endpoint"`
+	Errors           *BatchErrors       `json:"errors,omitempty"`
+	InputFileID      string             `json:"input_file_id"`
+	CompletionWindow string             `json:"completion_window"`
+	Status           string             `json:"status"`
+	OutputFileID     string             `json:"output_file_id,omitempty"`
+	ErrorFileID      string             `json:"error_file_id,omitempty"`
+	CreatedAt        int64              `json:"created_at"`
+	InProgressAt     int64              `json:"in_progress_at,omitempty"`
+	ExpiresAt        int64              `json:"expires_at,omitempty"`
+	FinalizingAt     int64              `json:"finalizing_at,omitempty"`
+	CompletedAt      int64              `json:"completed_at,omitempty"`
+	FailedAt         int64              `json:"failed_at,omitempty"`
+	ExpiredAt        int64              `json:"expired_at,omitempty"`
+	CancellingAt     int64              `json:"cancelling_at,omitempty"`
+	CancelledAt      int64              `json:"cancelled_at,omitempty"`
+	RequestCounts    BatchRequestCounts `json:"request_counts"`
+	Metadata         map[string]string  `json:"metadata,omitempty"`
+}
+
+// Reports whether the batch reached a final status and will not change anymore.
+func (b Batch) Done() bool {
+	switch b.Status {
+	case BatchStatusFailed, BatchStatusCompleted, BatchStatusExpired, BatchStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// Creates a batch from an uploaded JSONL file, see BatchWriter.
+type BatchRequest struct {
+	InputFileID string `json:"input_file_id"`
+	// API path all requests of the file are sent to, one of the BatchEndpoint constants.
+	Endpoint string `json:"endpoint"`
+	// Defaults to BatchCompletionWindow24h.
+	CompletionWindow string            `json:"completion_window"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+}
+
+// Generates the correct http.Request object for the given API Request Struct.
+func (br *BatchRequest) GenerateHTTPRequest(ctx context.Context) (*http.Request, error) {
+	body := *br
+	if body.CompletionWindow == "" {
+		body.CompletionWindow = BatchCompletionWindow24h
+	}
+	return newJSONRequest(ctx, http.MethodPost, "batches", body)
+}
+
+// Utilizes the CreateBatch OpenAI API.
+//
+// @Returns the created openai.Batch.
+func (c *Client) CreateBatch(ctx context.Context, batchReq *BatchRequest) (Batch, error) {
+	var res Batch
+	req, err := batchReq.GenerateHTTPRequest(ctx)
+	if err != nil {
+		return res, err
+	}
+	err = c.SendRequest(req, &res)
+	return res, err
+}
+
+// Utilizes the RetrieveBatch OpenAI API.
+//
+// @Returns openai.Batch.
+func (c *Client) GetBatch(ctx context.Context, batchID string) (Batch, error) {
+	var res Batch
+	err := c.sendJSON(ctx, http.MethodGet, "batches/"+batchID, nil, &res)
+	return res, err
+}
+
+// Utilizes the CancelBatch OpenAI API.
+//
+// @Returns openai.Batch, usually with status cancelling.
+func (c *Client) CancelBatch(ctx context.Context, batchID string) (Batch, error) {
+	var res Batch
+	err := c.sendJSON(ctx, http.MethodPost, "batches/"+batchID+"/cancel", nil, &res)
+	return res, err
+}
+
+// Utilizes the ListBatches OpenAI API. params may be nil.
+//
+// @Returns a page of openai.Batch.
+func (c *Client) ListBatches(ctx context.Context, params *ListParams) (List[Batch], error) {
+	var res List[Batch]
+	err := c.sendJSON(ctx, http.MethodGet, params.encode("batches"), nil, &res)
+	return res, err
+}
+
+// Iterates over every openai.Batch, fetching the pages as needed. params may be nil.
+func (c *Client) Batches(ctx context.Context, params *ListParams) iter.Seq2[Batch, error] {
+	return paginate(ctx, params, c.ListBatches)
+}
+
+// Polls the batch every interval until it is done. An interval of 0 uses DefaultPollInterval,
+// batches usually take minutes to hours so a longer interval is advisable.
+//
+// @Returns the last retrieved openai.Batch.
+func (c *Client) WaitForBatch(ctx context.Context, batchID string, interval time.Duration) (Batch, error) {
+	var batch Batch
+	err := poll(ctx, interval, func() (done bool, err error) {
+		batch, err = c.GetBatch(ctx, batchID)
+		return batch.Done(), err
+	})
+	return batch, err
+}
+
+type batchLine struct {
+	CustomID string          `json:"custom_id"`
+	Method   string          `json:"method"`
+	URL      string          `json:"url"`
+	Body     json.RawMessage `json:"body"`
+}
+
+// BatchWriter converts requests into the JSONL input format of the Batch API.
+//
+// Any Request with a JSON body can be added, e.g. *ChatCompletionRequest. All requests of a
+// batch must target the same endpoint. The requests are kept so results can be joined back.
+type BatchWriter struct {
+	endpoint string
+	lines    []batchLine
+	requests map[string]Request
+}
+
+// Creates an empty BatchWriter.
+func NewBatchWriter() *BatchWriter {
+	return &BatchWriter{requests: map[string]Request{}}
+}
+
+// Adds a request to the batch. An empty customID is replaced by "request-<n>",
+// n being the position of the request in the batch.
+//
+// @Returns the custom_id of the request.
+func (w *BatchWriter) Add(customID string, r Request) (string, error) {
+	if customID == "" {
+		customID = fmt.Sprintf("request-%d", len(w.lines))
+	}
+	if _, ok := w.requests[customID]; ok {
+		return "", fmt.Errorf("duplicate custom_id %s", customID)
+	}
+	req, err := r.GenerateHTTPRequest(context.Background())
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(req.Header.Get("Content-Type"), "application/json") {
+		return "", fmt.Errorf("%T cannot be batched, only JSON requests are supported", r)
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return "", err
+	}
+	if w.endpoint == "" {
+		switch req.URL.Path {
+		case BatchEndpointChatCompletions, BatchEndpointCompletions, BatchEndpointEmbeddings,
+			BatchEndpointModerations, BatchEndpointResponses:
+		default:
+			return "", fmt.Errorf("%T targets %s, which the Batch API does not support", r, req.URL.Path)
+		}
+		w.endpoint = req.URL.Path
+	} else if req.URL.Path != w.endpoint {
+		return "", fmt.Errorf("%T targets %s, the batch already targets %s", r, req.URL.Path, w.endpoint)
+	}
+	w.lines = append(w.lines, batchLine{CustomID: customID, Method: req.Method, URL: req.URL.Path, Body: body})
+	w.requests[customID] = r
+	return customID, nil
+}
+
+// Returns the endpoint all requests of the batch target.
+func (w *BatchWriter) Endpoint() string {
+	return w.endpoint
+}
+
+// Returns the number of requests in the batch.
+func (w *BatchWriter) Len() int {
+	return len(w.lines)
+}
+
+// Returns the request added with customID, nil if there is none.
+func (w *BatchWriter) Request(customID string) Request {
+	return w.requests[customID]
+}
+
+// Writes the batch as JSONL, one request per line.
+func (w *BatchWriter) WriteTo(out io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, line := range w.lines {
+		if err := enc.Encode(line); err != nil {
+			return 0, err
+		}
+	}
+	return buf.WriteTo(out)
+}
+
+// The response to a batched request as found in the output file.
+type BatchResponse struct {
+	StatusCode int             `json:"status_code"`
+	RequestID  string          `json:"request_id"`
+	Body       json.RawMessage `json:"body"`
+}
+
+// The result of a single request of a batch. Response and Error are both nil when the
+// request was never processed, e.g. because the batch expired or was cancelled.
+type BatchResult struct {
+	ID       string         `json:"id"`
+	CustomID string         `json:"custom_id"`
+	Response *BatchResponse `json:"response"`
+	Error    *BatchError    `json:"error"`
+	// The request added to the BatchWriter under CustomID.
+	Request Request `json:"-"`
+}
+
+// Returns the error of the request: the batch error, the API error of a failed
+// response, or an error if the request was not processed at all.
+func (r BatchResult) Err() error {
+	switch {
+	case r.Error != nil:
+		return r.Error
+	case r.Response == nil:
+		return fmt.Errorf("request %s was not processed", r.CustomID)
+	case r.Response.StatusCode < http.StatusOK || r.Response.StatusCode >= http.StatusBadRequest:
+		apiErr := newAPIError(r.Response.StatusCode, r.Response.Body)
+		apiErr.RequestID = r.Response.RequestID
+		return apiErr
+	}
+	return nil
+}
+
+// Decodes the response body into v, e.g. a *ChatCompletionResponse.
+func (r BatchResult) Decode(v interface{}) error {
+	if err := r.Err(); err != nil {
+		return err
+	}
+	return json.Unmarshal(r.Response.Body, v)
+}
+
+// Uploads the requests of w and creates a batch for them. The uploaded file is deleted
+// again when the batch cannot be created.
+//
+// @Returns the created openai.Batch.
+func (c *Client) SubmitBatch(ctx context.Context, w *BatchWriter, metadata map[string]string) (Batch, error) {
+	if w.Len() == 0 {
+		return Batch{}, errors.New("batch contains no requests")
+	}
+	var buf bytes.Buffer
+	if _, err := w.WriteTo(&buf); err != nil {
+		return Batch{}, err
+	}
+	file, err := c.UploadFile(ctx, &FileRequest{Content: &buf, Name: "batch.jsonl", Purpose: FilePurposeBatch})
+	if err != nil {
+		return Batch{}, fmt.Errorf("uploading batch input: %w", err)
+	}
+	batch, err := c.CreateBatch(ctx, &BatchRequest{
+		InputFileID: file.ID,
+		Endpoint:    w.Endpoint(),
+		Metadata:    metadata,
+	})
+	if err != nil {
+		return batch, c.deleteFiles(ctx, []string{file.ID}, err)
+	}
+	return batch, nil
+}
+
+// Downloads the output and error files of a finished batch and joins them with the requests
+// of w by custom_id. w may be nil, the results then carry no Request.
+//
+// @Returns one BatchResult per request of w in the order they were added, followed by results
+// for unknown custom_ids. Without w the results are in file order.
+func (c *Client) GetBatchResults(ctx context.Context, batch Batch, w *BatchWriter) ([]BatchResult, error) {
+	byID := map[string]BatchResult{}
+	var order []string
+	for _, fileID := range []string{batch.OutputFileID, batch.ErrorFileID} {
+		if fileID == "" {
+			continue
+		}
+		if err := c.readBatchResults(ctx, fileID, func(r BatchResult) {
+			if _, ok := byID[r.CustomID]; !ok {
+				order = append(order, r.CustomID)
+			}
+			byID[r.CustomID] = r
+		}); err != nil {
+			return nil, err
+		}
+	}
+	var results []BatchResult
+	if w != nil {
+		for _, line := range w.lines {
+			r, ok := byID[line.CustomID]
+			if !ok {
+				r = BatchResult{CustomID: line.CustomID}
+			}
+			r.Request = w.requests[line.CustomID]
+			results = append(results, r)
+			delete(byID, line.CustomID)
+		}
+	}
+	for _, id := range order {
+		if r, ok := byID[id]; ok {
+			results = append(results, r)
+		}
+	}
+	return results, nil
+}
+
+func (c *Client) readBatchResults(ctx context.Context, fileID string, fn func(BatchResult)) error {
+	body, err := c.GetFileContent(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("downloading batch file %s: %w", fileID, err)
+	}
+	defer body.Close()
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(nil, maxBatchLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var r BatchResult
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return fmt.Errorf("batch file %s line %d: %w", fileID, line, err)
+		}
+		fn(r)
+	}
+	return scanner.Err()
+}
+
+// Submits the requests of w as a batch, polls it every interval until it is done and joins
+// the results back to the requests, see SubmitBatch, WaitForBatch and GetBatchResults.
+//
+// @Returns the results together with the final openai.Batch. A batch that failed
+// validation returns an error describing its errors.
+func (c *Client) RunBatch(ctx context.Context, w *BatchWriter, interval time.Duration) ([]BatchResult, Batch, error) {
+	batch, err := c.SubmitBatch(ctx, w, nil)
+	if err != nil {
+		return nil, batch, err
+	}
+	if batch, err = c.WaitForBatch(ctx, batch.ID, interval); err != nil {
+		return nil, batch, err
+	}
+	if batch.Status == BatchStatusFailed {
+		msg := "batch failed"
+		if batch.Errors != nil && len(batch.Errors.Data) > 0 {
+			msg = fmt.Sprintf("batch failed: %v", &batch.Errors.Data[0])
+		}
+		return nil, batch, errors.New(msg)
+	}
+	results, err := c.GetBatchResults(ctx, batch, w)
+	return results, batch, err
+}
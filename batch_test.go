@@ -0,0 +1,155 @@
+package openai_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	. "github.com/EthanCampana/go-openai"
+)
+
+func TestBatchWriter(t *testing.T) {
+	w := NewBatchWriter()
+	if _, err := w.Add("greeting", &ChatCompletionRequest{Model: "gpt-4o-mini", Messages: []ChatMessage{{Role: ChatMessageRoleUser, Content: "hi"}}}); err != nil {
+		t.Fatal(err)
+	}
+	id, err := w.Add("", &ChatCompletionRequest{Model: "gpt-4o-mini"})
+	if err != nil || id != "request-1" {
+		t.Fatalf("Add() = %q, %v, want request-1", id, err)
+	}
+
+	tests := []struct {
+		name     string
+		customID string
+		req      Request
+	}{
+		{name: "DuplicateID", customID: "greeting", req: &ChatCompletionRequest{Model: "gpt-4o-mini"}},
+		{name: "OtherEndpoint", req: &ImageRequest{Prompt: "cat"}},
+		{name: "Multipart", req: &ImageVariationRequest{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := w.Add(tt.customID, tt.req); err == nil {
+				t.Errorf("Add(%T) succeeded, want error", tt.req)
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	if _, err := w.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	want := `{"custom_id":"greeting","method":"POST","url":"/v1/chat/completions","body":{"model":"gpt-4o-mini","messages":[{"content":"hi","role":"user"}]}}`
+	if len(lines) != 2 || lines[0] != want {
+		t.Errorf("WriteTo() =\n%s\nwant first line\n%s", buf.String(), want)
+	}
+	if w.Endpoint() != "/v1/chat/completions" || w.Len() != 2 {
+		t.Errorf("Endpoint() = %s, Len() = %d", w.Endpoint(), w.Len())
+	}
+
+	if _, err := NewBatchWriter().Add("", &ImageRequest{Prompt: "cat"}); err == nil {
+		t.Error("Add() of an image request succeeded, the Batch API does not support images")
+	}
+}
+
+func TestClient_SubmitBatch_DeletesInputOnFailure(t *testing.T) {
+	var deleted []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/files":
+			fmt.Fprint(w, `{"id":"file-in"}`)
+		case r.URL.Path == "/v1/batches":
+			http.Error(w, `{"error":{"message":"too many batches"}}`, http.StatusTooManyRequests)
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			fmt.Fprint(w, `{"id":"file-in","deleted":true}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	bw := NewBatchWriter()
+	bw.Add("", &ChatCompletionRequest{Model: "gpt-4o-mini"})
+	_, err := client.SubmitBatch(context.Background(), bw, nil)
+	if err == nil || !strings.Contains(err.Error(), "429") {
+		t.Errorf("SubmitBatch() error = %v, want the CreateBatch error", err)
+	}
+	if len(deleted) != 1 || deleted[0] != "/v1/files/file-in" {
+		t.Errorf("deleted %v, want the uploaded input file", deleted)
+	}
+}
+
+func TestClient_RunBatch(t *testing.T) {
+	var input string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/files":
+			if r.FormValue("purpose") != FilePurposeBatch {
+				t.Errorf("purpose = %q", r.FormValue("purpose"))
+			}
+			f, _, err := r.FormFile("file")
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, _ := io.ReadAll(f)
+			input = string(b)
+			fmt.Fprint(w, `{"id":"file-in"}`)
+		case "/v1/batches":
+			var req BatchRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.InputFileID != "file-in" || req.Endpoint != "/v1/chat/completions" || req.CompletionWindow != "24h" {
+				t.Errorf("CreateBatch request = %+v", req)
+			}
+			fmt.Fprint(w, `{"id":"batch_1","status":"validating"}`)
+		case "/v1/batches/batch_1":
+			fmt.Fprint(w, `{"id":"batch_1","status":"completed","output_file_id":"file-out","error_file_id":"file-err"}`)
+		case "/v1/files/file-out/content":
+			// Output order does not follow the input order.
+			fmt.Fprint(w, `{"id":"r2","custom_id":"b","response":{"status_code":400,"body":{"error":{"message":"bad model"}}}}`+"\n")
+			fmt.Fprint(w, `{"id":"r1","custom_id":"a","response":{"status_code":200,"request_id":"req_1","body":{"choices":[{"message":{"role":"assistant","content":"A"}}]}}}`+"\n")
+		case "/v1/files/file-err/content":
+			fmt.Fprint(w, `{"id":"r3","custom_id":"c","error":{"code":"batch_expired","message":"not run"}}`+"\n")
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	})
+
+	bw := NewBatchWriter()
+	reqs := map[string]*ChatCompletionRequest{}
+	for _, id := range []string{"a", "b", "c", "d"} {
+		reqs[id] = &ChatCompletionRequest{Model: "gpt-4o-mini", User: id}
+		if _, err := bw.Add(id, reqs[id]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	results, batch, err := client.RunBatch(context.Background(), bw, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batch.Status != BatchStatusCompleted || strings.Count(input, "\n") != 4 {
+		t.Fatalf("RunBatch() batch = %+v, input = %q", batch, input)
+	}
+	if len(results) != 4 {
+		t.Fatalf("RunBatch() returned %d results, want 4", len(results))
+	}
+	for i, id := range []string{"a", "b", "c", "d"} {
+		if results[i].CustomID != id || results[i].Request != reqs[id] {
+			t.Errorf("results[%d] = %s %v, want %s joined to its request", i, results[i].CustomID, results[i].Request, id)
+		}
+	}
+	var res ChatCompletionResponse
+	if err = results[0].Decode(&res); err != nil || res.Choices[0].Message.Content != "A" {
+		t.Errorf("results[0].Decode() = %+v, %v", res, err)
+	}
+	for i, want := range []string{"", "bad model", "not run", "not processed"} {
+		err := results[i].Err()
+		if (want == "") != (err == nil) || err != nil && !strings.Contains(err.Error(), want) {
+			t.Errorf("results[%d].Err() = %v, want %q", i, err, want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package openai
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrUnknownBuilder is returned when no BuilderFactory is registered under the requested name.
+var ErrUnknownBuilder = errors.New("unknown request builder")
+
+// A BuilderFactory creates a fresh RequestBuilder every time it is called.
+type BuilderFactory func() RequestBuilder
+
+type builderRegistry struct {
+	mu        sync.RWMutex
+	factories map[string]BuilderFactory
+}
+
+func defaultBuilderFactories() map[string]BuilderFactory {
+	return map[string]BuilderFactory{
+		"image":           func() RequestBuilder { return *NewImageRequestBuilder() },
+		"image-variation": func() RequestBuilder { return *NewImageVariationRequestBuilder() },
+		"image-edit":      func() RequestBuilder { return *NewImageEditRequestBuilder() },
+	}
+}
+
+func newBuilderRegistry() *builderRegistry {
+	return &builderRegistry{factories: defaultBuilderFactories()}
+}
+
+// Registers a BuilderFactory under name so it can be created through GetRequestBuilder
+// and LookupRequestBuilder. Registering an existing name replaces its factory,
+// which also allows overriding the default builders.
+func (c *Client) RegisterRequestBuilder(name string, factory BuilderFactory) error {
+	if name == "" {
+		return errors.New("builder name must not be empty")
+	}
+	if factory == nil {
+		return fmt.Errorf("builder factory for %s must not be nil", name)
+	}
+	c.builders.mu.Lock()
+	defer c.builders.mu.Unlock()
+	c.builders.factories[name] = factory
+	return nil
+}
+
+// Creates the RequestBuilder registered under name.
+//
+// @Returns an error wrapping ErrUnknownBuilder if name is not registered.
+func (c *Client) LookupRequestBuilder(name string) (RequestBuilder, error) {
+	c.builders.mu.RLock()
+	factory, ok := c.builders.factories[name]
+	c.builders.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBuilder, name)
+	}
+	return factory(), nil
+}
+
+// Returns the names of every registered RequestBuilder in sorted order.
+func (c *Client) RequestBuilders() []string {
+	c.builders.mu.RLock()
+	defer c.builders.mu.RUnlock()
+	names := make([]string, 0, len(c.builders.factories))
+	for name := range c.builders.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,214 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	ChatMessageRoleSystem    = "system"
+	ChatMessageRoleDeveloper = "developer"
+	ChatMessageRoleUser      = "user"
+	ChatMessageRoleAssistant = "assistant"
+	ChatMessageRoleTool      = "tool"
+)
+
+const (
+	FinishReasonStop          = "stop"
+	FinishReasonLength        = "length"
+	FinishReasonToolCalls     = "tool_calls"
+	FinishReasonContentFilter = "content_filter"
+)
+
+// A message of the conversation. Content holds plain text, MultiContent holds text and image parts
+// and takes precedence over Content when set.
+type ChatMessage struct {
+	Role         string            `json:"role"`
+	Content      string            `json:"content,omitempty"`
+	MultiContent []ChatMessagePart `json:"-"`
+	Name         string            `json:"name,omitempty"`
+	Refusal      string            `json:"refusal,omitempty"`
+	ToolCalls    []ToolCall        `json:"tool_calls,omitempty"`
+	ToolCallID   string            `json:"tool_call_id,omitempty"`
+}
+
+type chatMessageAlias ChatMessage
+
+func (m ChatMessage) MarshalJSON() ([]byte, error) {
+	var content interface{}
+	switch {
+	case len(m.MultiContent) > 0:
+		content = m.MultiContent
+	case m.Content != "" || len(m.ToolCalls) == 0:
+		content = m.Content
+	}
+	return json.Marshal(struct {
+		Content interface{} `json:"content,omitempty"`
+		chatMessageAlias
+	}{Content: content, chatMessageAlias: chatMessageAlias(m)})
+}
+
+func (m *ChatMessage) UnmarshalJSON(b []byte) error {
+	var msg struct {
+		Content json.RawMessage `json:"content"`
+		chatMessageAlias
+	}
+	if err := json.Unmarshal(b, &msg); err != nil {
+		return err
+	}
+	*m = ChatMessage(msg.chatMessageAlias)
+	m.Content = ""
+	switch {
+	case len(msg.Content) == 0 || string(msg.Content) == "null":
+	case msg.Content[0] == '[':
+		return json.Unmarshal(msg.Content, &m.MultiContent)
+	default:
+		return json.Unmarshal(msg.Content, &m.Content)
+	}
+	return nil
+}
+
+const (
+	ResponseFormatText       = "text"
+	ResponseFormatJSONObject = "json_object"
+	ResponseFormatJSONSchema = "json_schema"
+)
+
+type ChatResponseFormatJSONSchema struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Schema      interface{} `json:"schema"`
+	Strict      bool        `json:"strict,omitempty"`
+}
+
+type ChatResponseFormat struct {
+	Type       string                        `json:"type"`
+	JSONSchema *ChatResponseFormatJSONSchema `json:"json_schema,omitempty"`
+}
+
+type StreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type ChatCompletionRequest struct {
+	Model               string              `json:"model"`
+	Messages            []ChatMessage       `json:"messages"`
+	MaxTokens           int                 `json:"max_tokens,omitempty"`
+	MaxCompletionTokens int                 `json:"max_completion_tokens,omitempty"`
+	Temperature         *float32            `json:"temperature,omitempty"`
+	TopP                *float32            `json:"top_p,omitempty"`
+	N                   int                 `json:"n,omitempty"`
+	Stop                []string            `json:"stop,omitempty"`
+	PresencePenalty     float32             `json:"presence_penalty,omitempty"`
+	FrequencyPenalty    float32             `json:"frequency_penalty,omitempty"`
+	Seed                *int                `json:"seed,omitempty"`
+	Tools               []Tool              `json:"tools,omitempty"`
+	ToolChoice          *ToolChoice         `json:"tool_choice,omitempty"`
+	ParallelToolCalls   *bool               `json:"parallel_tool_calls,omitempty"`
+	ResponseFormat      *ChatResponseFormat `json:"response_format,omitempty"`
+	Stream              bool                `json:"stream,omitempty"`
+	StreamOptions       *StreamOptions      `json:"stream_options,omitempty"`
+	User                string              `json:"user,omitempty"`
+	Metadata            map[string]string   `json:"metadata,omitempty"`
+}
+
+type PromptTokensDetails struct {
+	CachedTokens int `json:"cached_tokens"`
+	AudioTokens  int `json:"audio_tokens"`
+}
+
+type CompletionTokensDetails struct {
+	ReasoningTokens          int `json:"reasoning_tokens"`
+	AudioTokens              int `json:"audio_tokens"`
+	AcceptedPredictionTokens int `json:"accepted_prediction_tokens"`
+	RejectedPredictionTokens int `json:"rejected_prediction_tokens"`
+}
+
+// Token usage of a request. Cached tokens are included in PromptTokens,
+// reasoning tokens are included in CompletionTokens.
+type Usage struct {
+	PromptTokens            int                     `json:"prompt_tokens"`
+	CompletionTokens        int                     `json:"completion_tokens"`
+	TotalTokens             int                     `json:"total_tokens"`
+	PromptTokensDetails     PromptTokensDetails     `json:"prompt_tokens_details"`
+	CompletionTokensDetails CompletionTokensDetails `json:"completion_tokens_details"`
+}
+
+// Adds the token counts of o to u.
+func (u *Usage) Add(o Usage) {
+	u.PromptTokens += o.PromptTokens
+	u.CompletionTokens += o.CompletionTokens
+	u.TotalTokens += o.TotalTokens
+	u.PromptTokensDetails.CachedTokens += o.PromptTokensDetails.CachedTokens
+	u.PromptTokensDetails.AudioTokens += o.PromptTokensDetails.AudioTokens
+	u.CompletionTokensDetails.ReasoningTokens += o.CompletionTokensDetails.ReasoningTokens
+	u.CompletionTokensDetails.AudioTokens += o.CompletionTokensDetails.AudioTokens
+	u.CompletionTokensDetails.AcceptedPredictionTokens += o.CompletionTokensDetails.AcceptedPredictionTokens
+	u.CompletionTokensDetails.RejectedPredictionTokens += o.CompletionTokensDetails.RejectedPredictionTokens
+}
+
+type ChatCompletionChoice struct {
+	Index        int         `json:"index"`
+	Message      ChatMessage `json:"message"`
+	FinishReason string      `json:"finish_reason"`
+}
+
+type ChatCompletionResponse struct {
+	ID                string                 `json:"id"`
+	Object            string                 `json:"object"`
+	Created           int64                  `json:"created"`
+	Model             string                 `json:"model"`
+	Choices           []ChatCompletionChoice `json:"choices"`
+	Usage             Usage                  `json:"usage"`
+	SystemFingerprint string                 `json:"system_fingerprint,omitempty"`
+}
+
+// Generates the correct http.Request object for the given API Request Struct.
+func (ccr *ChatCompletionRequest) GenerateHTTPRequest(ctx context.Context) (response *http.Request, err error) {
+	return newJSONRequest(ctx, "POST", "chat/completions", ccr)
+}
+
+// Utilizes the CreateChatCompletion OpenAI API to generate the next message of the conversation.
+//
+// @Returns openai.ChatCompletionResponse.
+func (c *Client) CreateChatCompletion(ctx context.Context, chatReq *ChatCompletionRequest) (ChatCompletionResponse, error) {
+	var chatRes ChatCompletionResponse
+	if chatReq.Stream {
+		return chatRes, errStreamNotSupported
+	}
+	req, err := chatReq.GenerateHTTPRequest(ctx)
+	if err != nil {
+		return chatRes, err
+	}
+	if err = c.SendRequest(req, &chatRes); err != nil {
+		return chatRes, err
+	}
+	c.recordChatUsage(ctx, chatReq, chatRes.Model, chatRes.Usage)
+	return chatRes, nil
+}
+
+// Creates a tool role message answering the tool call with the given id.
+func NewToolMessage(toolCallID, content string) ChatMessage {
+	return ChatMessage{
+		Role:       ChatMessageRoleTool,
+		Content:    content,
+		ToolCallID: toolCallID,
+	}
+}
+
+// Creates a strict json_schema response format from the type of v, which must be a struct.
+func NewJSONSchemaResponseFormat(name string, v interface{}) (*ChatResponseFormat, error) {
+	schema, err := GenerateStrictSchema(v)
+	if err != nil {
+		return nil, err
+	}
+	return &ChatResponseFormat{
+		Type: ResponseFormatJSONSchema,
+		JSONSchema: &ChatResponseFormatJSONSchema{
+			Name:   name,
+			Schema: schema,
+			Strict: true,
+		},
+	}, nil
+}
@@ -0,0 +1,170 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/EthanCampana/go-openai"
+)
+
+// Redirects every request of the client to a local test server.
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (rt rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme = rt.target.Scheme
+	r.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	target, _ := url.Parse(srv.URL)
+	return GetClient("test-token").SetHTTPClient(&http.Client{Transport: rewriteTransport{target: target}})
+}
+
+func TestToolChoice_JSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		choice *ToolChoice
+		want   string
+	}{
+		{name: "Mode", choice: ToolChoiceMode(ToolChoiceRequired), want: `"required"`},
+		{name: "Function", choice: ToolChoiceFunction("get_weather"), want: `{"type":"function","function":{"name":"get_weather"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.choice)
+			if err != nil || string(b) != tt.want {
+				t.Fatalf("json.Marshal(ToolChoice) = %s, %v, want %s", b, err, tt.want)
+			}
+			var got ToolChoice
+			if err = json.Unmarshal(b, &got); err != nil || got != *tt.choice {
+				t.Errorf("json.Unmarshal(ToolChoice) = %+v, %v", got, err)
+			}
+		})
+	}
+}
+
+func TestClient_CreateChatCompletion_ToolCalls(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var req ChatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if len(req.Tools) != 1 || req.ToolChoice == nil || req.ToolChoice.Mode != ToolChoiceAuto || !*req.ParallelToolCalls {
+			t.Errorf("tools were not sent: %+v", req)
+		}
+		if last := req.Messages[len(req.Messages)-1]; last.Role != ChatMessageRoleTool || last.ToolCallID != "call_0" {
+			t.Errorf("tool message was not sent: %+v", last)
+		}
+		fmt.Fprint(w, `{"id":"chatcmpl-1","choices":[{"index":0,"finish_reason":"tool_calls","message":{"role":"assistant","content":null,
+			"tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Paris\"}"}}]}}],
+			"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`)
+	})
+	parallel := true
+	res, err := c.CreateChatCompletion(context.Background(), &ChatCompletionRequest{
+		Model: "gpt-4o",
+		Messages: []ChatMessage{
+			{Role: ChatMessageRoleUser, Content: "Weather?"},
+			{Role: ChatMessageRoleAssistant, ToolCalls: []ToolCall{{ID: "call_0", Type: ToolTypeFunction, Function: FunctionCall{Name: "get_weather", Arguments: "{}"}}}},
+			NewToolMessage("call_0", "sunny"),
+		},
+		Tools:             []Tool{NewFunctionTool("get_weather", "Gets the weather", json.RawMessage(`{"type":"object"}`))},
+		ToolChoice:        ToolChoiceMode(ToolChoiceAuto),
+		ParallelToolCalls: &parallel,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := res.Choices[0].Message.ToolCalls
+	if len(calls) != 1 || res.Choices[0].FinishReason != FinishReasonToolCalls {
+		t.Fatalf("unexpected response %+v", res)
+	}
+	var args struct{ City string }
+	if err = calls[0].DecodeArguments(&args); err != nil || args.City != "Paris" {
+		t.Errorf("ToolCall.DecodeArguments() = %+v, %v", args, err)
+	}
+}
+
+func TestClient_CreateChatCompletionStream(t *testing.T) {
+	chunks := []string{
+		`{"id":"c1","model":"gpt-4o","choices":[{"index":0,"delta":{"role":"assistant","tool_calls":[{"index":0,"id":"call_a","type":"function","function":{"name":"lookup","arguments":""}}]}}]}`,
+		`{"id":"c1","choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"id":"call_b","type":"function","function":{"name":"search","arguments":"{\"q\":"}}]}}]}`,
+		`{"id":"c1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"id\":"}}]}}]}`,
+		`{"id":"c1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"7}"}},{"index":1,"function":{"arguments":"\"go\"}"}}]}}]}`,
+		`{"id":"c1","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
+		`{"id":"c1","choices":[],"usage":{"prompt_tokens":3,"completion_tokens":4,"total_tokens":7}}`,
+	}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"stream":true`) {
+			t.Errorf("stream flag not set: %s", body)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range chunks {
+			fmt.Fprintf(w, ": keep-alive\n\ndata: %s\n\n", chunk)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+	stream, err := c.CreateChatCompletionStream(context.Background(), &ChatCompletionRequest{Model: "gpt-4o"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+	res, err := stream.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ToolCall{
+		{ID: "call_a", Type: ToolTypeFunction, Function: FunctionCall{Name: "lookup", Arguments: `{"id":7}`}},
+		{ID: "call_b", Type: ToolTypeFunction, Function: FunctionCall{Name: "search", Arguments: `{"q":"go"}`}},
+	}
+	if got := res.Choices[0].Message.ToolCalls; !reflect.DeepEqual(got, want) {
+		t.Errorf("reassembled tool calls = %+v, want %+v", got, want)
+	}
+	if res.Choices[0].FinishReason != FinishReasonToolCalls || res.Usage.TotalTokens != 7 || res.Model != "gpt-4o" {
+		t.Errorf("unexpected response %+v", res)
+	}
+}
+
+func TestClient_CreateChatCompletionStream_ErrorEvent(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"id\":\"c1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"error\":{\"message\":\"Rate limit reached\",\"type\":\"requests\",\"param\":null,\"code\":\"rate_limit_exceeded\"}}\n\n")
+	})
+	stream, err := c.CreateChatCompletionStream(context.Background(), &ChatCompletionRequest{Model: "gpt-4o"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+	if _, err = stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	chunk, err := stream.Recv()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Recv() = %+v, %v, want *APIError", chunk, err)
+	}
+	if apiErr.Code != "rate_limit_exceeded" || apiErr.Type != "requests" || apiErr.StatusCode != 0 ||
+		apiErr.Error() != "stream error: Rate limit reached" {
+		t.Errorf("Recv() error = %+v", apiErr)
+	}
+}
@@ -0,0 +1,185 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+)
+
+type ChatCompletionStreamChoiceDelta struct {
+	Role      string     `json:"role,omitempty"`
+	Content   string     `json:"content,omitempty"`
+	Refusal   string     `json:"refusal,omitempty"`
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+}
+
+type ChatCompletionStreamChoice struct {
+	Index        int                             `json:"index"`
+	Delta        ChatCompletionStreamChoiceDelta `json:"delta"`
+	FinishReason string                          `json:"finish_reason,omitempty"`
+}
+
+type ChatCompletionStreamResponse struct {
+	ID                string                       `json:"id"`
+	Object            string                       `json:"object"`
+	Created           int64                        `json:"created"`
+	Model             string                       `json:"model"`
+	Choices           []ChatCompletionStreamChoice `json:"choices"`
+	Usage             *Usage                       `json:"usage,omitempty"`
+	SystemFingerprint string                       `json:"system_fingerprint,omitempty"`
+}
+
+// A stream of chat completion chunks. Close must be called once done with the stream.
+type ChatCompletionStream struct {
+	body    io.ReadCloser
+	sse     *sseReader
+	onUsage func(model string, usage Usage)
+}
+
+// Utilizes the CreateChatCompletion OpenAI API with stream enabled.
+//
+// @Returns openai.ChatCompletionStream to read the chunks from.
+func (c *Client) CreateChatCompletionStream(ctx context.Context, chatReq *ChatCompletionRequest) (*ChatCompletionStream, error) {
+	streamReq := *chatReq
+	streamReq.Stream = true
+	req, err := streamReq.GenerateHTTPRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	return &ChatCompletionStream{
+		body: res.Body,
+		sse:  newSSEReader(res.Body),
+		onUsage: func(model string, usage Usage) {
+			c.recordChatUsage(ctx, chatReq, model, usage)
+		},
+	}, nil
+}
+
+// Returns the next chunk of the stream or io.EOF once the stream is finished.
+func (s *ChatCompletionStream) Recv() (ChatCompletionStreamResponse, error) {
+	var chunk ChatCompletionStreamResponse
+	_, data, err := s.sse.Next()
+	if err != nil {
+		return chunk, err
+	}
+	if err = json.Unmarshal(data, &chunk); err != nil {
+		return chunk, err
+	}
+	if chunk.Usage != nil && s.onUsage != nil {
+		s.onUsage(chunk.Model, *chunk.Usage)
+	}
+	return chunk, nil
+}
+
+// Reads the remaining chunks and reassembles them into a single response.
+func (s *ChatCompletionStream) Collect() (ChatCompletionResponse, error) {
+	var acc ChatCompletionAccumulator
+	for {
+		chunk, err := s.Recv()
+		if err == io.EOF {
+			return acc.Response(), nil
+		}
+		if err != nil {
+			return acc.Response(), err
+		}
+		acc.Add(chunk)
+	}
+}
+
+// Closes the underlying response body.
+func (s *ChatCompletionStream) Close() error {
+	return s.body.Close()
+}
+
+// Reassembles streamed chunks into a ChatCompletionResponse.
+//
+// Content is concatenated per choice and tool call deltas are merged by their index,
+// so the arguments of parallel tool calls end up on the right call.
+type ChatCompletionAccumulator struct {
+	res       ChatCompletionResponse
+	toolIndex map[int]map[int]int
+}
+
+// Merges a chunk into the accumulated response.
+func (a *ChatCompletionAccumulator) Add(chunk ChatCompletionStreamResponse) {
+	if a.toolIndex == nil {
+		a.toolIndex = map[int]map[int]int{}
+	}
+	if a.res.ID == "" {
+		a.res.ID = chunk.ID
+		a.res.Object = "chat.completion"
+		a.res.Created = chunk.Created
+		a.res.Model = chunk.Model
+	}
+	if chunk.SystemFingerprint != "" {
+		a.res.SystemFingerprint = chunk.SystemFingerprint
+	}
+	if chunk.Usage != nil {
+		a.res.Usage = *chunk.Usage
+	}
+	for _, delta := range chunk.Choices {
+		choice := a.choice(delta.Index)
+		if delta.Delta.Role != "" {
+			choice.Message.Role = delta.Delta.Role
+		}
+		choice.Message.Content += delta.Delta.Content
+		choice.Message.Refusal += delta.Delta.Refusal
+		if delta.FinishReason != "" {
+			choice.FinishReason = delta.FinishReason
+		}
+		for _, tc := range delta.Delta.ToolCalls {
+			a.addToolCall(choice, tc)
+		}
+	}
+}
+
+// Returns the response assembled from every chunk added so far.
+func (a *ChatCompletionAccumulator) Response() ChatCompletionResponse {
+	return a.res
+}
+
+func (a *ChatCompletionAccumulator) choice(index int) *ChatCompletionChoice {
+	for len(a.res.Choices) <= index {
+		a.res.Choices = append(a.res.Choices, ChatCompletionChoice{
+			Index:   len(a.res.Choices),
+			Message: ChatMessage{Role: ChatMessageRoleAssistant},
+		})
+	}
+	return &a.res.Choices[index]
+}
+
+func (a *ChatCompletionAccumulator) addToolCall(choice *ChatCompletionChoice, delta ToolCall) {
+	positions := a.toolIndex[choice.Index]
+	if positions == nil {
+		positions = map[int]int{}
+		a.toolIndex[choice.Index] = positions
+	}
+	calls := choice.Message.ToolCalls
+	// Deltas without an index continue the last call unless they start a new one with an id.
+	streamIndex := len(calls) - 1
+	if delta.Index != nil {
+		streamIndex = *delta.Index
+	} else if delta.ID != "" || streamIndex < 0 {
+		streamIndex = len(calls)
+	}
+	pos, ok := positions[streamIndex]
+	if !ok {
+		pos = len(calls)
+		positions[streamIndex] = pos
+		choice.Message.ToolCalls = append(calls, ToolCall{Type: ToolTypeFunction})
+	}
+	call := &choice.Message.ToolCalls[pos]
+	if delta.ID != "" {
+		call.ID = delta.ID
+	}
+	if delta.Type != "" {
+		call.Type = delta.Type
+	}
+	call.Function.Name += delta.Function.Name
+	call.Function.Arguments += delta.Function.Arguments
+}
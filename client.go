@@ -1,11 +1,15 @@
 package openai
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 )
 
@@ -15,10 +19,17 @@ type Request interface {
 
 const apiURL = "https://api.openai.com/v1"
 
+// DefaultPollInterval is the interval the Wait methods poll at when none is given.
+const DefaultPollInterval = time.Second
+
 type Client struct {
 	authToken  string
 	orgID      string
 	httpClient *http.Client
+	imageSink  ImageSink
+	builders   *builderRegistry
+	usage      *UsageTracker
+	baseURL    string
 }
 
 func getTransportClient() *http.Client {
@@ -31,6 +42,7 @@ func GetClient(authToken string) *Client {
 	return &Client{authToken: authToken,
 		orgID:      "",
 		httpClient: getTransportClient(),
+		builders:   newBuilderRegistry(),
 	}
 }
 
@@ -38,6 +50,7 @@ func GetOrgClient(authToken string, orgID string) *Client {
 	return &Client{authToken: authToken,
 		orgID:      orgID,
 		httpClient: getTransportClient(),
+		builders:   newBuilderRegistry(),
 	}
 }
 func (c *Client) setHeaders(r *http.Request) *http.Request {
@@ -46,17 +59,111 @@ func (c *Client) setHeaders(r *http.Request) *http.Request {
 	if len(c.orgID) > 0 {
 		r.Header.Set("OpenAI-Organization", c.orgID)
 	}
+	if beta := betaHeader(r.URL.Path); beta != "" && r.Header.Get("OpenAI-Beta") == "" {
+		r.Header.Set("OpenAI-Beta", beta)
+	}
 	return r
 }
 
+// Points the request at the base URL of the client, if one is set.
+func (c *Client) resolveURL(r *http.Request) error {
+	if c.baseURL == "" || !strings.HasPrefix(r.URL.String(), apiURL) {
+		return nil
+	}
+	base, err := url.Parse(strings.TrimSuffix(c.baseURL, "/"))
+	if err != nil {
+		return err
+	}
+	if base.Scheme == "" || base.Host == "" {
+		return fmt.Errorf("base url %q is not absolute", c.baseURL)
+	}
+	u := *r.URL
+	u.Scheme = base.Scheme
+	u.Host = base.Host
+	u.Path = base.Path + strings.TrimPrefix(r.URL.Path, "/v1")
+	u.RawPath = ""
+	r.URL = &u
+	r.Host = u.Host
+	return nil
+}
+
+// Beta versions required by API resources, keyed by the first path segment after the version.
+var betaResources = map[string]string{
+	"assistants":    "assistants=v2",
+	"threads":       "assistants=v2",
+	"vector_stores": "assistants=v2",
+	"realtime":      "realtime=v1",
+}
+
+// Returns the OpenAI-Beta header value required for the request path, if any.
+func betaHeader(path string) string {
+	_, rest, ok := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	if !ok {
+		return ""
+	}
+	resource, _, _ := strings.Cut(rest, "/")
+	return betaResources[resource]
+}
+
+// Creates a request against the OpenAI API for the given path, encoding body as JSON when it is not nil.
+func newJSONRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		reqBytes, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(reqBytes)
+	}
+	url := fmt.Sprintf("%s/%s", apiURL, path)
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	}
+	return req, nil
+}
+
+// Sends a JSON request for path and decodes the response into res. body may be nil.
+func (c *Client) sendJSON(ctx context.Context, method, path string, body, res interface{}) error {
+	req, err := newJSONRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	return c.SendRequest(req, res)
+}
+
+// Calls fn every interval, starting immediately, until it reports done or fails.
+// An interval of 0 uses DefaultPollInterval.
+func poll(ctx context.Context, interval time.Duration, fn func() (done bool, err error)) error {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+		done, err := fn()
+		if err != nil || done {
+			return err
+		}
+		timer.Reset(interval)
+	}
+}
+
 func checkResponse(resp *http.Response) error {
 	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
-		var errResp APIErrorResponse
-		err := json.NewDecoder(resp.Body).Decode(&errResp)
-		if err != nil || errResp.Error == nil {
-			return fmt.Errorf("error, status code: %d", resp.StatusCode)
-		}
-		return fmt.Errorf("error, status code: %d, message: %s", resp.StatusCode, errResp.Error.Message)
+		body, _ := io.ReadAll(resp.Body)
+		apiErr := newAPIError(resp.StatusCode, body)
+		apiErr.RequestID = resp.Header.Get("x-request-id")
+		return apiErr
 	}
 	return nil
 }
@@ -96,24 +203,84 @@ func (c *Client) ListModels(ctx context.Context) (Models, error) {
 	return res, nil
 }
 
+// Iterates over every openai.Model. The models endpoint is not paginated, the whole list is
+// fetched with ListModels on the first iteration.
+func (c *Client) Models(ctx context.Context) iter.Seq2[Model, error] {
+	return func(yield func(Model, error) bool) {
+		models, err := c.ListModels(ctx)
+		if err != nil {
+			yield(Model{}, err)
+			return
+		}
+		for _, m := range models.Data {
+			if !yield(m, nil) {
+				return
+			}
+		}
+	}
+}
+
 // Sends an HttpRequest to the OpenAI API and Loads information into the buffer that is passed.
 func (c *Client) SendRequest(req *http.Request, a interface{}) error {
-	res, err := c.httpClient.Do(c.setHeaders(req))
+	_, err := c.sendRequest(req, a)
+	return err
+}
+
+// Sends the request with the client headers and checks the status code.
+// The caller owns the body of the returned response.
+func (c *Client) doRequest(req *http.Request) (*http.Response, error) {
+	if err := c.usage.checkBudget(); err != nil {
+		return nil, err
+	}
+	if err := c.resolveURL(c.setHeaders(req)); err != nil {
+		return nil, err
+	}
+	res, err := c.httpClient.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if err = checkResponse(res); err != nil {
-		return err
+		res.Body.Close()
+		return nil, err
+	}
+	return res, nil
+}
+
+// Same as SendRequest but also returns the response headers, e.g. to read the x-request-id.
+func (c *Client) sendRequest(req *http.Request, a interface{}) (http.Header, error) {
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
 	}
 	defer res.Body.Close()
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		return err
+		return res.Header, err
 	}
 	if err = json.Unmarshal(body, a); err != nil {
-		return err
+		return res.Header, err
 	}
-	return nil
+	return res.Header, nil
+}
+
+// Sets the http.Client used for every request, e.g. to change the timeout for long streams
+// or to install a custom Transport.
+func (c *Client) SetHTTPClient(hc *http.Client) *Client {
+	c.httpClient = hc
+	return c
+}
+
+// Sets the base URL the requests are sent to instead of https://api.openai.com/v1, e.g. a
+// proxy or an openaitest.Server. The URL includes the API version, e.g. http://localhost:8080/v1.
+func (c *Client) SetBaseURL(baseURL string) *Client {
+	c.baseURL = baseURL
+	return c
+}
+
+// Sets the ImageSink every CreateImage result is written to. Pass nil to disable it.
+func (c *Client) SetImageSink(sink ImageSink) *Client {
+	c.imageSink = sink
+	return c
 }
 
 // Utilizes the CreateImage OpenAI API  to generate Art based on the Request parameters.
@@ -125,22 +292,34 @@ func (c *Client) CreateImage(ctx context.Context, imgReq Request) (ImageResponse
 	var err error
 	switch i := imgReq.(type) {
 	case *ImageRequest:
-		req, err = i.GenerateHTTPRequest(ctx)
+		if err = i.Validate(); err == nil {
+			req, err = i.GenerateHTTPRequest(ctx)
+		}
 	case *ImageVariationRequest:
-		req, err = i.GenerateHTTPRequest(ctx)
+		if err = i.Validate(); err == nil {
+			req, err = i.GenerateHTTPRequest(ctx)
+		}
 	case *ImageEditRequest:
-		req, err = i.GenerateHTTPRequest(ctx)
+		if err = i.Validate(); err == nil {
+			req, err = i.GenerateHTTPRequest(ctx)
+		}
 	default:
 		return imgRes, fmt.Errorf("got unsupported request type %T", imgReq)
 	}
 	if err != nil {
 		return imgRes, err
 	}
-	err = c.SendRequest(req, &imgRes)
+	header, err := c.sendRequest(req, &imgRes)
 	if err != nil {
 		return imgRes, err
 	}
-	return imgRes, err
+	c.recordImageUsage(ctx, imgReq, imgRes)
+	if c.imageSink != nil {
+		if err = c.imageSink.StoreImages(ctx, imgReq, imgRes, header.Get("x-request-id")); err != nil {
+			return imgRes, fmt.Errorf("storing images: %w", err)
+		}
+	}
+	return imgRes, nil
 }
 
 // Utilizes the CreateImageVariation OpenAI API to generate Art based on the Request parameters.
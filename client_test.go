@@ -0,0 +1,41 @@
+package openai_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/EthanCampana/go-openai"
+)
+
+func TestClient_SetBaseURL(t *testing.T) {
+	var gotPath, gotQuery, gotBeta string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery, gotBeta = r.URL.Path, r.URL.RawQuery, r.Header.Get("OpenAI-Beta")
+		w.Write([]byte(`{"object":"list","data":[]}`))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		baseURL  string
+		wantPath string
+	}{
+		{srv.URL + "/v1", "/v1/assistants"},
+		{srv.URL + "/openai/v1/", "/openai/v1/assistants"},
+	}
+	for _, tt := range tests {
+		client := GetClient("test-token").SetBaseURL(tt.baseURL)
+		if _, err := client.ListAssistants(context.Background(), &ListParams{Limit: 1}); err != nil {
+			t.Fatalf("%s: %v", tt.baseURL, err)
+		}
+		if gotPath != tt.wantPath || gotQuery != "limit=1" || gotBeta != "assistants=v2" {
+			t.Errorf("%s: request to %s?%s with OpenAI-Beta %q", tt.baseURL, gotPath, gotQuery, gotBeta)
+		}
+	}
+
+	_, err := GetClient("test-token").SetBaseURL("localhost:8080").ListModels(context.Background())
+	if err == nil {
+		t.Error("ListModels() with a relative base url succeeded")
+	}
+}
@@ -0,0 +1,344 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Default number of tokens kept free for the completion when MaxTokens is not set.
+const DefaultReserveTokens = 4096
+
+// Context window sizes in tokens, matched on the longest prefix of the model name.
+var ContextWindows = map[string]int{
+	"gpt-3.5-turbo": 16385,
+	"gpt-4":         8192,
+	"gpt-4-32k":     32768,
+	"gpt-4-turbo":   128000,
+	"gpt-4o":        128000,
+	"gpt-4.1":       1047576,
+	"gpt-5":         400000,
+	"o1":            200000,
+	"o3":            200000,
+	"o4-mini":       200000,
+}
+
+// Returns the context window of model, or the gpt-4 window if the model is unknown.
+func ContextWindow(model string) int {
+	best, size := "", ContextWindows["gpt-4"]
+	for prefix, window := range ContextWindows {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best, size = prefix, window
+		}
+	}
+	return size
+}
+
+// A TokenCounter counts the prompt tokens of messages for a model.
+type TokenCounter interface {
+	CountChatTokens(model string, messages []ChatMessage) (int, error)
+}
+
+// ApproximateTokenCounter estimates tokens at four bytes of text per token plus the
+// per message overhead of the API. It needs no vocabulary but may be off by a few percent.
+type ApproximateTokenCounter struct{}
+
+func (ApproximateTokenCounter) CountChatTokens(model string, messages []ChatMessage) (int, error) {
+	return countChatTokens(model, messages, func(s string) int {
+		return (utf8.RuneCountInString(s) + 3) / 4
+	}), nil
+}
+
+// Counts messages the way the API bills them: every message costs a fixed overhead on top
+// of its role, name and content, and every reply is primed with three tokens.
+func countChatTokens(model string, messages []ChatMessage, count func(string) int) int {
+	const replyPrimer = 3
+	tokensPerMessage, tokensPerName := 3, 1
+	if model == "gpt-3.5-turbo-0301" {
+		tokensPerMessage, tokensPerName = 4, -1
+	}
+	total := replyPrimer
+	for _, m := range messages {
+		total += tokensPerMessage + count(m.Role) + count(m.Content) + count(m.Refusal)
+		if m.Name != "" {
+			total += tokensPerName + count(m.Name)
+		}
+		for _, part := range m.MultiContent {
+			switch part.Type {
+			case ChatMessagePartTypeText:
+				total += count(part.Text)
+			case ChatMessagePartTypeImageURL:
+				total += imagePartTokens(part.ImageURL)
+			}
+		}
+		for _, tc := range m.ToolCalls {
+			total += tokensPerMessage + count(tc.Function.Name) + count(tc.Function.Arguments)
+		}
+	}
+	return total
+}
+
+// Images are billed per 512px tile, the size is unknown here so high detail assumes four tiles.
+func imagePartTokens(img *ChatMessageImageURL) int {
+	if img != nil && img.Detail == ImageDetailLow {
+		return 85
+	}
+	return 85 + 4*170
+}
+
+// A TruncationStrategy shortens a conversation until it fits into budget tokens.
+type TruncationStrategy interface {
+	Truncate(ctx context.Context, conv *Conversation, budget int) error
+}
+
+// Conversation accumulates the messages of a chat and keeps them within the context window
+// of its model. It can be persisted with encoding/json; Strategy and Counter are not
+// serialized and have to be set again after loading.
+type Conversation struct {
+	Model    string        `json:"model"`
+	Messages []ChatMessage `json:"messages"`
+	// Maximum number of prompt tokens. 0 uses the context window minus ReserveTokens.
+	MaxTokens int `json:"max_tokens,omitempty"`
+	// Tokens kept free for the completion when MaxTokens is not set. 0 uses DefaultReserveTokens.
+	ReserveTokens int `json:"reserve_tokens,omitempty"`
+
+	Strategy TruncationStrategy `json:"-"`
+	// nil counts with the encoding of Model when it is registered or found in TokenizerDir,
+	// see GetEncoding, and falls back to ApproximateTokenCounter otherwise.
+	Counter TokenCounter `json:"-"`
+}
+
+// Creates a Conversation for model that drops the oldest messages once it is full.
+func NewConversation(model string, messages ...ChatMessage) *Conversation {
+	return &Conversation{
+		Model:    model,
+		Messages: messages,
+		Strategy: DropOldest{},
+	}
+}
+
+// Appends messages to the conversation.
+func (c *Conversation) Append(messages ...ChatMessage) {
+	c.Messages = append(c.Messages, messages...)
+}
+
+// Returns the number of prompt tokens the conversation currently uses.
+func (c *Conversation) Tokens() (int, error) {
+	return c.count(c.Messages)
+}
+
+// Returns the maximum number of prompt tokens of the conversation.
+func (c *Conversation) Budget() int {
+	if c.MaxTokens > 0 {
+		return c.MaxTokens
+	}
+	reserve := c.ReserveTokens
+	if reserve <= 0 {
+		reserve = DefaultReserveTokens
+	}
+	return ContextWindow(c.Model) - reserve
+}
+
+// Applies the Strategy if the conversation does not fit into its budget.
+func (c *Conversation) Fit(ctx context.Context) error {
+	tokens, err := c.Tokens()
+	if err != nil || tokens <= c.Budget() {
+		return err
+	}
+	strategy := c.Strategy
+	if strategy == nil {
+		strategy = DropOldest{}
+	}
+	if err = strategy.Truncate(ctx, c, c.Budget()); err != nil {
+		return err
+	}
+	if tokens, err = c.Tokens(); err == nil && tokens > c.Budget() {
+		return fmt.Errorf("conversation uses %d tokens after truncation, budget is %d", tokens, c.Budget())
+	}
+	return err
+}
+
+// Fits the conversation, sends it with the settings of template and appends the reply.
+// template may be nil; its Model and Messages are replaced by the ones of the conversation.
+//
+// @Returns the ChatCompletionResponse of the request.
+func (c *Conversation) Send(ctx context.Context, client *Client, template *ChatCompletionRequest) (ChatCompletionResponse, error) {
+	if err := c.Fit(ctx); err != nil {
+		return ChatCompletionResponse{}, err
+	}
+	var req ChatCompletionRequest
+	if template != nil {
+		req = *template
+	}
+	req.Model = c.Model
+	req.Messages = append([]ChatMessage{}, c.Messages...)
+	res, err := client.CreateChatCompletion(ctx, &req)
+	if err != nil {
+		return res, err
+	}
+	if len(res.Choices) == 0 {
+		return res, errors.New("response contains no choices")
+	}
+	c.Append(res.Choices[0].Message)
+	return res, nil
+}
+
+func (c *Conversation) count(messages []ChatMessage) (int, error) {
+	counter := c.Counter
+	if counter == nil {
+		if enc, err := GetEncoding(EncodingForModel(c.Model)); err == nil {
+			counter = enc
+		} else {
+			counter = ApproximateTokenCounter{}
+		}
+	}
+	return counter.CountChatTokens(c.Model, messages)
+}
+
+// Splits the messages into the leading system and developer messages and the turns after them.
+// An assistant message with tool calls forms one turn with the tool results answering it,
+// so truncation never separates a tool result from its call.
+func splitTurns(messages []ChatMessage) (system []ChatMessage, turns [][]ChatMessage) {
+	i := 0
+	for ; i < len(messages); i++ {
+		role := messages[i].Role
+		if role != ChatMessageRoleSystem && role != ChatMessageRoleDeveloper {
+			break
+		}
+		system = append(system, messages[i])
+	}
+	for ; i < len(messages); i++ {
+		m := messages[i]
+		if m.Role == ChatMessageRoleTool && len(turns) > 0 {
+			last := turns[len(turns)-1]
+			if len(last[0].ToolCalls) > 0 {
+				turns[len(turns)-1] = append(last, m)
+				continue
+			}
+		}
+		turns = append(turns, []ChatMessage{m})
+	}
+	return system, turns
+}
+
+func joinTurns(system []ChatMessage, turns [][]ChatMessage) []ChatMessage {
+	messages := append([]ChatMessage{}, system...)
+	for _, t := range turns {
+		messages = append(messages, t...)
+	}
+	return messages
+}
+
+// DropOldest removes the oldest turns until the conversation fits. System messages and
+// the latest turn are always kept.
+type DropOldest struct{}
+
+func (DropOldest) Truncate(ctx context.Context, conv *Conversation, budget int) error {
+	system, turns := splitTurns(conv.Messages)
+	for len(turns) > 1 {
+		tokens, err := conv.count(joinTurns(system, turns))
+		if err != nil {
+			return err
+		}
+		if tokens <= budget {
+			break
+		}
+		turns = turns[1:]
+	}
+	conv.Messages = joinTurns(system, turns)
+	return nil
+}
+
+// KeepSystemAndLast keeps the system messages and the last N messages, extended to the start
+// of their turn, then drops further turns if the conversation still does not fit.
+type KeepSystemAndLast struct {
+	N int
+}
+
+func (k KeepSystemAndLast) Truncate(ctx context.Context, conv *Conversation, budget int) error {
+	system, turns := splitTurns(conv.Messages)
+	kept := 0
+	start := len(turns)
+	for start > 0 && kept < k.N {
+		start--
+		kept += len(turns[start])
+	}
+	conv.Messages = joinTurns(system, turns[start:])
+	return DropOldest{}.Truncate(ctx, conv, budget)
+}
+
+// Default instructions used by Summarize.
+const DefaultSummaryPrompt = "Summarize the conversation so far in a few sentences. " +
+	"Keep names, decisions, open questions and any facts needed to continue it."
+
+// Prefix of the system message holding the summary written by Summarize.
+const SummaryPrefix = "Summary of the earlier conversation: "
+
+// Summarize replaces older turns with a summary written by the model itself. The last
+// KeepLast messages are kept verbatim, the summary is added as a system message starting
+// with SummaryPrefix. A previous summary is summarized again together with the older turns
+// and replaced, so a conversation holds at most one.
+type Summarize struct {
+	Client *Client
+	// Model used to write the summary, defaults to the model of the conversation.
+	Model    string
+	KeepLast int
+	// Instructions for the summary, defaults to DefaultSummaryPrompt.
+	Prompt string
+}
+
+func (s Summarize) Truncate(ctx context.Context, conv *Conversation, budget int) error {
+	if s.Client == nil {
+		return errors.New("summarize strategy requires a client")
+	}
+	system, turns := splitTurns(conv.Messages)
+	kept := 0
+	start := len(turns)
+	for start > 0 && kept < s.KeepLast {
+		start--
+		kept += len(turns[start])
+	}
+	if start == 0 {
+		return DropOldest{}.Truncate(ctx, conv, budget)
+	}
+	model, prompt := s.Model, s.Prompt
+	if model == "" {
+		model = conv.Model
+	}
+	if prompt == "" {
+		prompt = DefaultSummaryPrompt
+	}
+	var summaries []ChatMessage
+	system, summaries = splitSummaries(system)
+	old := joinTurns(append(system, summaries...), turns[:start])
+	res, err := s.Client.CreateChatCompletion(ctx, &ChatCompletionRequest{
+		Model:    model,
+		Messages: append(old, ChatMessage{Role: ChatMessageRoleUser, Content: prompt}),
+	})
+	if err != nil {
+		return fmt.Errorf("summarizing conversation: %w", err)
+	}
+	if len(res.Choices) == 0 {
+		return errors.New("summary response contains no choices")
+	}
+	summary := ChatMessage{
+		Role:    ChatMessageRoleSystem,
+		Content: SummaryPrefix + res.Choices[0].Message.Content,
+	}
+	conv.Messages = joinTurns(append(system, summary), turns[start:])
+	return DropOldest{}.Truncate(ctx, conv, budget)
+}
+
+// Separates the summaries of earlier Summarize runs from the other system messages.
+func splitSummaries(system []ChatMessage) (rest, summaries []ChatMessage) {
+	for _, m := range system {
+		if m.Role == ChatMessageRoleSystem && strings.HasPrefix(m.Content, SummaryPrefix) {
+			summaries = append(summaries, m)
+		} else {
+			rest = append(rest, m)
+		}
+	}
+	return rest, summaries
+}
@@ -0,0 +1,167 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/EthanCampana/go-openai"
+)
+
+// Counts every message as 10 tokens so budgets are easy to reason about.
+type fixedCounter struct{}
+
+func (fixedCounter) CountChatTokens(model string, messages []ChatMessage) (int, error) {
+	return 10 * len(messages), nil
+}
+
+func testConversation() *Conversation {
+	conv := NewConversation("gpt-4o",
+		ChatMessage{Role: ChatMessageRoleSystem, Content: "You are helpful"},
+		ChatMessage{Role: ChatMessageRoleUser, Content: "u1"},
+		ChatMessage{Role: ChatMessageRoleAssistant, ToolCalls: []ToolCall{{ID: "c1", Type: ToolTypeFunction}}},
+		NewToolMessage("c1", "t1"),
+		ChatMessage{Role: ChatMessageRoleAssistant, Content: "a1"},
+		ChatMessage{Role: ChatMessageRoleUser, Content: "u2"},
+		ChatMessage{Role: ChatMessageRoleAssistant, Content: "a2"},
+	)
+	conv.Counter = fixedCounter{}
+	return conv
+}
+
+func contents(messages []ChatMessage) string {
+	parts := make([]string, len(messages))
+	for i, m := range messages {
+		parts[i] = m.Content
+		if len(m.ToolCalls) > 0 {
+			parts[i] = "call"
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestConversation_Fit(t *testing.T) {
+	tests := []struct {
+		name      string
+		strategy  TruncationStrategy
+		maxTokens int
+		want      string
+	}{
+		{name: "Fits", strategy: DropOldest{}, maxTokens: 70, want: "You are helpful,u1,call,t1,a1,u2,a2"},
+		{name: "Drop oldest keeps tool results with their call", strategy: DropOldest{}, maxTokens: 50, want: "You are helpful,a1,u2,a2"},
+		{name: "Drop oldest", strategy: DropOldest{}, maxTokens: 60, want: "You are helpful,call,t1,a1,u2,a2"},
+		{name: "Keep system and last", strategy: KeepSystemAndLast{N: 2}, maxTokens: 60, want: "You are helpful,u2,a2"},
+		{name: "Keep last extends to the tool call", strategy: KeepSystemAndLast{N: 4}, maxTokens: 60, want: "You are helpful,call,t1,a1,u2,a2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conv := testConversation()
+			conv.Strategy = tt.strategy
+			conv.MaxTokens = tt.maxTokens
+			if err := conv.Fit(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if got := contents(conv.Messages); got != tt.want {
+				t.Errorf("Conversation.Fit() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConversation_SummarizeAndSend(t *testing.T) {
+	var requests []ChatCompletionRequest
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req ChatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		requests = append(requests, req)
+		reply := "answer"
+		if len(requests) == 1 {
+			reply = "user asked twice"
+		}
+		fmt.Fprintf(w, `{"choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":%q}}]}`, reply)
+	})
+	conv := testConversation()
+	conv.Strategy = Summarize{Client: c, KeepLast: 2}
+	conv.MaxTokens = 50
+	conv.Append(ChatMessage{Role: ChatMessageRoleUser, Content: "u3"})
+
+	if _, err := conv.Send(context.Background(), c, &ChatCompletionRequest{Model: "ignored", MaxTokens: 5}); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("expected a summary and a chat request, got %d", len(requests))
+	}
+	if got := contents(requests[0].Messages); got != "You are helpful,u1,call,t1,a1,u2,"+DefaultSummaryPrompt {
+		t.Errorf("summary request = %s", got)
+	}
+	want := "You are helpful,Summary of the earlier conversation: user asked twice,a2,u3"
+	if got := contents(requests[1].Messages); got != want || requests[1].Model != "gpt-4o" || requests[1].MaxTokens != 5 {
+		t.Errorf("chat request = %s (%s)", got, requests[1].Model)
+	}
+	if got := contents(conv.Messages); got != want+",answer" {
+		t.Errorf("conversation = %s", got)
+	}
+
+	b, err := json.Marshal(conv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded Conversation
+	if err = json.Unmarshal(b, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Model != conv.Model || loaded.MaxTokens != 50 || !reflect.DeepEqual(loaded.Messages, conv.Messages) {
+		t.Errorf("json round trip = %+v", loaded)
+	}
+}
+
+func TestConversation_SummarizeRepeatedly(t *testing.T) {
+	var summaryRequests []ChatCompletionRequest
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req ChatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		summaryRequests = append(summaryRequests, req)
+		fmt.Fprintf(w, `{"choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"s%d"}}]}`, len(summaryRequests))
+	})
+	conv := testConversation()
+	conv.Strategy = Summarize{Client: c, KeepLast: 2}
+	conv.MaxTokens = 50
+
+	for i := 3; i <= 6; i++ {
+		conv.Append(
+			ChatMessage{Role: ChatMessageRoleUser, Content: fmt.Sprintf("u%d", i)},
+			ChatMessage{Role: ChatMessageRoleAssistant, Content: fmt.Sprintf("a%d", i)},
+		)
+		if err := conv.Fit(context.Background()); err != nil {
+			t.Fatalf("Fit() #%d = %v", i, err)
+		}
+		want := fmt.Sprintf("You are helpful,%ss%d,u%d,a%d", SummaryPrefix, len(summaryRequests), i, i)
+		if got := contents(conv.Messages); got != want {
+			t.Errorf("conversation after Fit() #%d = %s, want %s", i, got, want)
+		}
+	}
+	// Every summary after the first is written from the previous one.
+	last := summaryRequests[len(summaryRequests)-1]
+	want := fmt.Sprintf("You are helpful,%ss%d,u5,a5,%s", SummaryPrefix, len(summaryRequests)-1, DefaultSummaryPrompt)
+	if got := contents(last.Messages); got != want {
+		t.Errorf("last summary request = %s, want %s", got, want)
+	}
+}
+
+func TestApproximateTokenCounter(t *testing.T) {
+	n, err := ApproximateTokenCounter{}.CountChatTokens("gpt-4o", []ChatMessage{{Role: ChatMessageRoleUser, Content: "Hello World!"}})
+	if err != nil || n != 3+3+1+3 {
+		t.Errorf("CountChatTokens() = %d, %v", n, err)
+	}
+	if got := ContextWindow("gpt-4o-mini-2024-07-18"); got != 128000 {
+		t.Errorf("ContextWindow() = %d", got)
+	}
+}
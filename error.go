@@ -1,5 +1,15 @@
 package openai
 
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// The error body of the API.
+//
+// Deprecated: use *APIError, which also accepts string codes.
 type APIErrorResponse struct {
 	Error *struct {
 		Code    *int    `json:"code,omitempty"`
@@ -8,3 +18,65 @@ type APIErrorResponse struct {
 		Type    string  `json:"type"`
 	} `json:"error,omitempty"`
 }
+
+// APIError is returned for requests the API answered with an error status code.
+// Use errors.As to inspect it.
+type APIError struct {
+	// 0 for errors sent as events of a stream, whose response status was 200.
+	StatusCode int
+	// Error type and code, e.g. invalid_request_error and invalid_api_key. Either may be empty.
+	Type    string
+	Code    string
+	Message string
+	Param   string
+	// The x-request-id of the failed request, if any.
+	RequestID string
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("stream error: %s", e.Message)
+	}
+	if e.Message == "" {
+		return fmt.Sprintf("error, status code: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("error, status code: %d, message: %s", e.StatusCode, e.Message)
+}
+
+// Reports whether the request may succeed when sent again: rate limits and server errors.
+func (e *APIError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+// Builds the APIError of a failed response from its status code and body. The body is
+// allowed to be anything, the message is left empty when it is not an API error object.
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode}
+	var errResp struct {
+		Error *struct {
+			Code    json.RawMessage `json:"code"`
+			Message string          `json:"message"`
+			Param   *string         `json:"param"`
+			Type    string          `json:"type"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &errResp) != nil || errResp.Error == nil {
+		return apiErr
+	}
+	apiErr.Type = errResp.Error.Type
+	apiErr.Message = errResp.Error.Message
+	if errResp.Error.Param != nil {
+		apiErr.Param = *errResp.Error.Param
+	}
+	// The code is a string for most errors but a number for some.
+	var code interface{}
+	if json.Unmarshal(errResp.Error.Code, &code) == nil {
+		switch v := code.(type) {
+		case string:
+			apiErr.Code = v
+		case float64:
+			apiErr.Code = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	}
+	return apiErr
+}
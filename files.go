@@ -0,0 +1,139 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+)
+
+const (
+	FilePurposeAssistants = "assistants"
+	FilePurposeBatch      = "batch"
+	FilePurposeFineTune   = "fine-tune"
+	FilePurposeVision     = "vision"
+	FilePurposeUserData   = "user_data"
+)
+
+type File struct {
+	ID        string `json:"id"`
+	Object    string `json:"object"`
+	Bytes     int64  `json:"bytes"`
+	CreatedAt int64  `json:"created_at"`
+	ExpiresAt int64  `json:"expires_at,omitempty"`
+	Filename  string `json:"filename"`
+	Purpose   string `json:"purpose"`
+}
+
+// Uploads the file at FilePath, or the data of Content when it is set.
+// Name defaults to the base name of FilePath and is required with Content.
+type FileRequest struct {
+	FilePath string
+	Content  io.Reader
+	Name     string
+	Purpose  string
+}
+
+// Generates the correct http.Request object for the given API Request Struct.
+func (fr *FileRequest) GenerateHTTPRequest(ctx context.Context) (response *http.Request, err error) {
+	var buff bytes.Buffer
+	buffW := multipart.NewWriter(&buff)
+
+	if err = writeFormFields(buffW, map[string]string{"purpose": fr.Purpose}); err != nil {
+		return nil, err
+	}
+	name := fr.Name
+	if name == "" {
+		name = filepath.Base(fr.FilePath)
+	}
+	if fr.Content != nil {
+		if name == "" || name == "." {
+			return nil, errors.New("file name is required when uploading content")
+		}
+		fw, err := buffW.CreateFormFile("file", name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = io.Copy(fw, fr.Content); err != nil {
+			return nil, err
+		}
+	} else if err = writeFormFile(buffW, "file", name, fr.FilePath); err != nil {
+		return nil, err
+	}
+	if err = buffW.Close(); err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/%s", apiURL, "files")
+	req, err := http.NewRequest("POST", url, &buff)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", buffW.FormDataContentType())
+	return req, nil
+}
+
+// Utilizes the UploadFile OpenAI API.
+//
+// @Returns the uploaded openai.File.
+func (c *Client) UploadFile(ctx context.Context, fileReq *FileRequest) (File, error) {
+	var res File
+	req, err := fileReq.GenerateHTTPRequest(ctx)
+	if err != nil {
+		return res, err
+	}
+	err = c.SendRequest(req, &res)
+	return res, err
+}
+
+// Utilizes the RetrieveFile OpenAI API.
+//
+// @Returns openai.File.
+func (c *Client) GetFile(ctx context.Context, fileID string) (File, error) {
+	var res File
+	err := c.sendJSON(ctx, http.MethodGet, "files/"+fileID, nil, &res)
+	return res, err
+}
+
+// Utilizes the RetrieveFileContent OpenAI API. The caller must close the returned reader.
+//
+// @Returns the content of the file.
+func (c *Client) GetFileContent(ctx context.Context, fileID string) (io.ReadCloser, error) {
+	req, err := newJSONRequest(ctx, http.MethodGet, "files/"+fileID+"/content", nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+// Utilizes the ListFiles OpenAI API. params may be nil.
+//
+// @Returns a page of openai.File.
+func (c *Client) ListFiles(ctx context.Context, params *ListParams) (List[File], error) {
+	var res List[File]
+	err := c.sendJSON(ctx, http.MethodGet, params.encode("files"), nil, &res)
+	return res, err
+}
+
+// Iterates over every openai.File, fetching the pages as needed. params may be nil.
+func (c *Client) Files(ctx context.Context, params *ListParams) iter.Seq2[File, error] {
+	return paginate(ctx, params, c.ListFiles)
+}
+
+// Utilizes the DeleteFile OpenAI API.
+//
+// @Returns openai.DeletionStatus.
+func (c *Client) DeleteFile(ctx context.Context, fileID string) (DeletionStatus, error) {
+	var res DeletionStatus
+	err := c.sendJSON(ctx, http.MethodDelete, "files/"+fileID, nil, &res)
+	return res, err
+}
@@ -9,24 +9,38 @@ import (
 	"mime/multipart"
 	"net/http"
 	"os"
+	"sort"
+	"strconv"
 )
 
 const (
-	SMALL  string = "256x256"
-	MEDIUM string = "512x512"
-	LARGE  string = "1024x1024"
+	SMALL     string = "256x256"
+	MEDIUM    string = "512x512"
+	LARGE     string = "1024x1024"
+	LANDSCAPE string = "1792x1024"
+	PORTRAIT  string = "1024x1792"
+	WIDE      string = "1536x1024"
+	TALL      string = "1024x1536"
+	AUTO      string = "auto"
 )
 
 type ImageRequest struct {
-	Num            uint8  `json:"n,omitempty"`
-	Prompt         string `json:"prompt"`
-	Size           string `json:"size,omitempty"`
-	ResponseFormat string `json:"response_format,omitempty"`
-	User           string `json:"user,omitempty"`
+	Num               uint8  `json:"n,omitempty"`
+	Model             string `json:"model,omitempty"`
+	Prompt            string `json:"prompt"`
+	Size              string `json:"size,omitempty"`
+	Quality           string `json:"quality,omitempty"`
+	Style             string `json:"style,omitempty"`
+	Background        string `json:"background,omitempty"`
+	OutputFormat      string `json:"output_format,omitempty"`
+	OutputCompression *uint8 `json:"output_compression,omitempty"`
+	ResponseFormat    string `json:"response_format,omitempty"`
+	User              string `json:"user,omitempty"`
 }
 
 type ImageEditRequest struct {
 	Num            uint8  `json:"n,omitempty"`
+	Model          string `json:"model,omitempty"`
 	Image          string `json:"image"`
 	ImagePath      string `json:"-"`
 	Mask           string `json:"mask"`
@@ -39,6 +53,7 @@ type ImageEditRequest struct {
 
 type ImageVariationRequest struct {
 	Num            uint8  `json:"n,omitempty"`
+	Model          string `json:"model,omitempty"`
 	Image          string `json:"image"`
 	ImagePath      string `json:"-"`
 	Prompt         string `json:"prompt"`
@@ -47,13 +62,18 @@ type ImageVariationRequest struct {
 	User           string `json:"user,omitempty"`
 }
 
-type ImageURL struct {
-	URL string `json:"url"`
+type ImageData struct {
+	URL           string `json:"url,omitempty"`
+	B64JSON       string `json:"b64_json,omitempty"`
+	RevisedPrompt string `json:"revised_prompt,omitempty"`
 }
 
+// Deprecated: ImageURL is kept for backwards compatibility, use ImageData.
+type ImageURL = ImageData
+
 type ImageResponse struct {
-	Created int        `json:"created"`
-	Data    []ImageURL `json:"data"`
+	Created int         `json:"created"`
+	Data    []ImageData `json:"data"`
 }
 
 // Generates the correct http.Request object for the given API Request Struct.
@@ -76,20 +96,21 @@ func (ir *ImageRequest) GenerateHTTPRequest(ctx context.Context) (response *http
 func (ivr *ImageVariationRequest) GenerateHTTPRequest(ctx context.Context) (response *http.Request, err error) {
 	var buff bytes.Buffer
 	buffW := multipart.NewWriter(&buff)
-	defer buffW.Close()
 
-	fw, err := buffW.CreateFormFile("image", ivr.Image)
-	if err != nil {
+	if err = writeFormFile(buffW, "image", ivr.Image, ivr.ImagePath); err != nil {
 		return nil, err
 	}
-	imageData, err := os.Open(ivr.ImagePath)
+	err = writeFormFields(buffW, map[string]string{
+		"model":           ivr.Model,
+		"n":               formatNum(ivr.Num),
+		"size":            ivr.Size,
+		"response_format": ivr.ResponseFormat,
+		"user":            ivr.User,
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	_, err = io.Copy(fw, imageData)
-
-	if err != nil {
+	if err = buffW.Close(); err != nil {
 		return nil, err
 	}
 	url := fmt.Sprintf("%s/%s", apiURL, "images/variations")
@@ -98,7 +119,7 @@ func (ivr *ImageVariationRequest) GenerateHTTPRequest(ctx context.Context) (resp
 		return nil, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "multipart/form-data")
+	req.Header.Set("Content-Type", buffW.FormDataContentType())
 	return req, nil
 }
 
@@ -106,40 +127,74 @@ func (ivr *ImageVariationRequest) GenerateHTTPRequest(ctx context.Context) (resp
 func (ier *ImageEditRequest) GenerateHTTPRequest(ctx context.Context) (response *http.Request, err error) {
 	var buff bytes.Buffer
 	buffW := multipart.NewWriter(&buff)
-	defer buffW.Close()
 
-	fw, err := buffW.CreateFormFile("image", ier.Image)
-	if err != nil {
+	if err = writeFormFile(buffW, "image", ier.Image, ier.ImagePath); err != nil {
 		return nil, err
 	}
-	imageData, err := os.Open(ier.ImagePath)
+	if ier.MaskPath != "" {
+		if err = writeFormFile(buffW, "mask", ier.Mask, ier.MaskPath); err != nil {
+			return nil, err
+		}
+	}
+	err = writeFormFields(buffW, map[string]string{
+		"model":           ier.Model,
+		"prompt":          ier.Prompt,
+		"n":               formatNum(ier.Num),
+		"size":            ier.Size,
+		"response_format": ier.ResponseFormat,
+		"user":            ier.User,
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	_, err = io.Copy(fw, imageData)
-	if err != nil {
+	if err = buffW.Close(); err != nil {
 		return nil, err
 	}
-	mw, err := buffW.CreateFormFile("mask", ier.Mask)
+	url := fmt.Sprintf("%s/%s", apiURL, "images/edits")
+	req, err := http.NewRequest("POST", url, &buff)
 	if err != nil {
 		return nil, err
 	}
-	maskData, err := os.Open(ier.MaskPath)
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", buffW.FormDataContentType())
+	return req, nil
+}
+
+func writeFormFile(w *multipart.Writer, field, name, path string) error {
+	fw, err := w.CreateFormFile(field, name)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	_, err = io.Copy(mw, maskData)
+	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	defer f.Close()
+	_, err = io.Copy(fw, f)
+	return err
+}
 
-	url := fmt.Sprintf("%s/%s", apiURL, "images/edits")
-	req, err := http.NewRequest("POST", url, &buff)
-	if err != nil {
-		return nil, err
+// Writes the non empty fields in a stable order so request bodies are reproducible.
+func writeFormFields(w *multipart.Writer, fields map[string]string) error {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
 	}
-	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "multipart/form-data")
-	return req, nil
+	sort.Strings(keys)
+	for _, k := range keys {
+		if fields[k] == "" {
+			continue
+		}
+		if err := w.WriteField(k, fields[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatNum(n uint8) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(int(n))
 }
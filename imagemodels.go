@@ -0,0 +1,180 @@
+package openai
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	ImageModelDallE2     = "dall-e-2"
+	ImageModelDallE3     = "dall-e-3"
+	ImageModelGPTImage1  = "gpt-image-1"
+	DefaultImageModel    = ImageModelDallE2
+	MaxOutputCompression = 100
+)
+
+// Describes which parameters an image model accepts.
+//
+// The first entry of Sizes is the size the RequestBuilders fall back to.
+type ImageModelSpec struct {
+	Sizes           []string
+	Qualities       []string
+	Styles          []string
+	Backgrounds     []string
+	OutputFormats   []string
+	ResponseFormats []string
+	MaxNum          uint8
+	SupportsEdit    bool
+	SupportsVary    bool
+}
+
+// ImageModels holds the validation table for every known image model.
+var ImageModels = map[string]ImageModelSpec{
+	ImageModelDallE2: {
+		Sizes:           []string{SMALL, MEDIUM, LARGE},
+		Qualities:       []string{"standard"},
+		ResponseFormats: []string{"url", "b64_json"},
+		MaxNum:          MaxImageRequest,
+		SupportsEdit:    true,
+		SupportsVary:    true,
+	},
+	ImageModelDallE3: {
+		Sizes:           []string{LARGE, LANDSCAPE, PORTRAIT},
+		Qualities:       []string{"standard", "hd"},
+		Styles:          []string{"vivid", "natural"},
+		ResponseFormats: []string{"url", "b64_json"},
+		MaxNum:          1,
+	},
+	ImageModelGPTImage1: {
+		Sizes:         []string{AUTO, LARGE, WIDE, TALL},
+		Qualities:     []string{"auto", "low", "medium", "high"},
+		Backgrounds:   []string{"auto", "transparent", "opaque"},
+		OutputFormats: []string{"png", "jpeg", "webp"},
+		MaxNum:        MaxImageRequest,
+		SupportsEdit:  true,
+	},
+}
+
+// Returns the validation table entry of the given model. An empty model resolves to DefaultImageModel.
+// The error reports models missing from the table, which requests leave to the API to validate.
+func GetImageModelSpec(model string) (ImageModelSpec, error) {
+	model, spec, ok := lookupImageModel(model)
+	if !ok {
+		return spec, fmt.Errorf("%s is not in the image model table", model)
+	}
+	return spec, nil
+}
+
+func (s ImageModelSpec) defaultSize() string {
+	return s.Sizes[0]
+}
+
+func (s ImageModelSpec) acceptsNum(n uint8) bool {
+	return n <= s.MaxNum
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks an optional parameter against the allowed values. Empty values are always accepted.
+func checkOption(model, param, value string, allowed []string) error {
+	if value == "" || contains(allowed, value) {
+		return nil
+	}
+	if len(allowed) == 0 {
+		return fmt.Errorf("%s does not support the %s parameter", model, param)
+	}
+	return fmt.Errorf("%s is not a valid %s for %s, accepted values: %v", value, param, model, allowed)
+}
+
+// Returns the spec of model, DefaultImageModel when empty. ok is false for models missing
+// from ImageModels, which are left to the API to validate so that new models can be used
+// before they are added to the table.
+func lookupImageModel(model string) (name string, spec ImageModelSpec, ok bool) {
+	if model == "" {
+		model = DefaultImageModel
+	}
+	spec, ok = ImageModels[model]
+	return model, spec, ok
+}
+
+// Validates the parameters shared by every image endpoint against the spec of the model.
+func (s ImageModelSpec) validate(model string, num uint8, size, responseFormat string) error {
+	if !s.acceptsNum(num) {
+		return fmt.Errorf("%s accepts at most %d images per request, got %d", model, s.MaxNum, num)
+	}
+	if err := checkOption(model, "size", size, s.Sizes); err != nil {
+		return err
+	}
+	return checkOption(model, "response_format", responseFormat, s.ResponseFormats)
+}
+
+// Validates the Request against the table of its model. Models missing from ImageModels
+// are not validated.
+func (ir *ImageRequest) Validate() error {
+	model, spec, ok := lookupImageModel(ir.Model)
+	if !ok {
+		return nil
+	}
+	if err := spec.validate(model, ir.Num, ir.Size, ir.ResponseFormat); err != nil {
+		return err
+	}
+	checks := []error{
+		checkOption(model, "quality", ir.Quality, spec.Qualities),
+		checkOption(model, "style", ir.Style, spec.Styles),
+		checkOption(model, "background", ir.Background, spec.Backgrounds),
+		checkOption(model, "output_format", ir.OutputFormat, spec.OutputFormats),
+	}
+	for _, err := range checks {
+		if err != nil {
+			return err
+		}
+	}
+	if ir.OutputCompression != nil {
+		if len(spec.OutputFormats) == 0 {
+			return fmt.Errorf("%s does not support the output_compression parameter", model)
+		}
+		if *ir.OutputCompression > MaxOutputCompression {
+			return fmt.Errorf("output_compression must be between 0 and %d, got %d", MaxOutputCompression, *ir.OutputCompression)
+		}
+	}
+	return nil
+}
+
+// Validates the Request against the table of its model. Models missing from ImageModels
+// are not validated.
+func (ier *ImageEditRequest) Validate() error {
+	if ier.Image == "" {
+		return errors.New("an image is required")
+	}
+	model, spec, ok := lookupImageModel(ier.Model)
+	if !ok {
+		return nil
+	}
+	if !spec.SupportsEdit {
+		return fmt.Errorf("%s does not support image edits", model)
+	}
+	return spec.validate(model, ier.Num, ier.Size, ier.ResponseFormat)
+}
+
+// Validates the Request against the table of its model. Models missing from ImageModels
+// are not validated.
+func (ivr *ImageVariationRequest) Validate() error {
+	if ivr.Image == "" {
+		return errors.New("an image is required")
+	}
+	model, spec, ok := lookupImageModel(ivr.Model)
+	if !ok {
+		return nil
+	}
+	if !spec.SupportsVary {
+		return fmt.Errorf("%s does not support image variations", model)
+	}
+	return spec.validate(model, ivr.Num, ivr.Size, ivr.ResponseFormat)
+}
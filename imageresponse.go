@@ -0,0 +1,235 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"  // register gif decoder for ImageData.Decode
+	_ "image/jpeg" // register jpeg decoder for ImageData.Decode
+	_ "image/png"  // register png decoder for ImageData.Decode
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrNoImageData is returned when an ImageData holds neither a URL nor base64 data.
+var ErrNoImageData = errors.New("image data contains neither url nor b64_json")
+
+// ErrImageNotEncoded is returned when the raw bytes of an image are requested but
+// the API only returned a URL. Use ImageResponse.Download to fetch those images.
+var ErrImageNotEncoded = errors.New("image data was returned as a url, download it first")
+
+// Returns the raw bytes of a b64_json image.
+func (d ImageData) Bytes() ([]byte, error) {
+	if d.B64JSON == "" {
+		if d.URL != "" {
+			return nil, ErrImageNotEncoded
+		}
+		return nil, ErrNoImageData
+	}
+	return base64.StdEncoding.DecodeString(d.B64JSON)
+}
+
+// Decodes a b64_json image into an image.Image.
+func (d ImageData) Decode() (image.Image, error) {
+	b, err := d.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(b))
+	return img, err
+}
+
+// Downscales img so its longest side is at most maxSide pixels, keeping the aspect ratio.
+// Every destination pixel is the average of the source pixels it covers.
+// Images that already fit, or a maxSide <= 0, are returned unchanged.
+func ResizeImage(img image.Image, maxSide int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if maxSide <= 0 || (w <= maxSide && h <= maxSide) {
+		return img
+	}
+	dw, dh := maxSide, h*maxSide/w
+	if h > w {
+		dw, dh = w*maxSide/h, maxSide
+	}
+	if dw < 1 {
+		dw = 1
+	}
+	if dh < 1 {
+		dh = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := b.Min.Y+y*h/dh, b.Min.Y+(y+1)*h/dh
+		for x := 0; x < dw; x++ {
+			x0, x1 := b.Min.X+x*w/dw, b.Min.X+(x+1)*w/dw
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, bl, a, n = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca), n+1
+				}
+			}
+			off := dst.PixOffset(x, y)
+			dst.Pix[off] = uint8(r / n >> 8)
+			dst.Pix[off+1] = uint8(g / n >> 8)
+			dst.Pix[off+2] = uint8(bl / n >> 8)
+			dst.Pix[off+3] = uint8(a / n >> 8)
+		}
+	}
+	return dst
+}
+
+// Returns the raw bytes of every b64_json image in the response.
+func (r ImageResponse) Bytes() ([][]byte, error) {
+	res := make([][]byte, len(r.Data))
+	for i, d := range r.Data {
+		b, err := d.Bytes()
+		if err != nil {
+			return nil, fmt.Errorf("image %d: %w", i, err)
+		}
+		res[i] = b
+	}
+	return res, nil
+}
+
+// Decodes every b64_json image in the response into an image.Image.
+func (r ImageResponse) Images() ([]image.Image, error) {
+	res := make([]image.Image, len(r.Data))
+	for i, d := range r.Data {
+		img, err := d.Decode()
+		if err != nil {
+			return nil, fmt.Errorf("image %d: %w", i, err)
+		}
+		res[i] = img
+	}
+	return res, nil
+}
+
+// Fetches every image of the response concurrently.
+//
+// URL results are downloaded with hc, b64_json results are decoded in place. A nil hc uses
+// a default client, Client.DownloadImages uses the http.Client of the Client.
+// The caller is responsible for closing every returned reader.
+//
+// @Returns one io.ReadCloser per ImageData, in the same order.
+func (r ImageResponse) Download(ctx context.Context, hc *http.Client) ([]io.ReadCloser, error) {
+	if hc == nil {
+		hc = getTransportClient()
+	}
+	res := make([]io.ReadCloser, len(r.Data))
+	errs := make([]error, len(r.Data))
+	var wg sync.WaitGroup
+	for i, d := range r.Data {
+		wg.Add(1)
+		go func(i int, d ImageData) {
+			defer wg.Done()
+			res[i], errs[i] = d.open(ctx, hc)
+		}(i, d)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			for _, rc := range res {
+				if rc != nil {
+					rc.Close()
+				}
+			}
+			return nil, fmt.Errorf("image %d: %w", i, err)
+		}
+	}
+	return res, nil
+}
+
+// Downloads every image of the response with hc into dir, creating it if needed. A nil hc
+// uses a default client.
+//
+// Files are named image-<created>-<index> with an extension sniffed from the content.
+//
+// @Returns the paths of the written files, in the same order as Data.
+func (r ImageResponse) SaveToDir(ctx context.Context, hc *http.Client, dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	readers, err := r.Download(ctx, hc)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, rc := range readers {
+			rc.Close()
+		}
+	}()
+	paths := make([]string, len(readers))
+	for i, rc := range readers {
+		b, err := io.ReadAll(rc)
+		if err != nil {
+			return nil, fmt.Errorf("image %d: %w", i, err)
+		}
+		name := fmt.Sprintf("image-%d-%d%s", r.Created, i, imageExtension(b))
+		paths[i] = filepath.Join(dir, name)
+		if err = os.WriteFile(paths[i], b, 0o644); err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+func (d ImageData) open(ctx context.Context, hc *http.Client) (io.ReadCloser, error) {
+	if d.B64JSON != "" || d.URL == "" {
+		b, err := d.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(bytes.NewReader(b)), nil
+	}
+	req, err := http.NewRequest("GET", d.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	res, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("error, status code: %d", res.StatusCode)
+	}
+	return res.Body, nil
+}
+
+var imageExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// Sniffs the MIME type of encoded image bytes. ok is false if the bytes are not a known image format.
+func imageMIMEType(b []byte) (mime string, ok bool) {
+	mime = http.DetectContentType(b)
+	_, ok = imageExtensions[mime]
+	return mime, ok
+}
+
+func imageExtension(b []byte) string {
+	mime, ok := imageMIMEType(b)
+	if !ok {
+		return ".bin"
+	}
+	return imageExtensions[mime]
+}
+
+// Fetches every image of the response with the http.Client of the Client, see ImageResponse.Download.
+//
+// @Returns one io.ReadCloser per ImageData, in the same order.
+func (c *Client) DownloadImages(ctx context.Context, res ImageResponse) ([]io.ReadCloser, error) {
+	return res.Download(ctx, c.httpClient)
+}
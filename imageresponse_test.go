@@ -0,0 +1,145 @@
+package openai_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/EthanCampana/go-openai"
+)
+
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(1, 1, color.RGBA{R: 255, A: 255})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestImageResponse_Unmarshal(t *testing.T) {
+	body := `{"created":1,"data":[{"b64_json":"aGVsbG8=","revised_prompt":"a hen"}]}`
+	var res ImageResponse
+	if err := json.Unmarshal([]byte(body), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Data[0].B64JSON != "aGVsbG8=" || res.Data[0].RevisedPrompt != "a hen" {
+		t.Errorf("ImageResponse = %+v", res)
+	}
+}
+
+func TestImageData_Decode(t *testing.T) {
+	raw := testPNG(t)
+	tests := []struct {
+		name    string
+		data    ImageData
+		wantErr error
+	}{
+		{name: "Decode b64_json", data: ImageData{B64JSON: base64.StdEncoding.EncodeToString(raw)}},
+		{name: "URL only", data: ImageData{URL: "https://example.com/a.png"}, wantErr: ErrImageNotEncoded},
+		{name: "Empty", data: ImageData{}, wantErr: ErrNoImageData},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := tt.data.Decode()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ImageData.Decode() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && img.Bounds().Dx() != 2 {
+				t.Errorf("ImageData.Decode() bounds = %v", img.Bounds())
+			}
+		})
+	}
+}
+
+func TestImageResponse_Download(t *testing.T) {
+	raw := testPNG(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.png" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Error("image download must not send the api token")
+		}
+		w.Write(raw)
+	}))
+	defer srv.Close()
+
+	res := ImageResponse{Created: 42, Data: []ImageData{
+		{URL: srv.URL + "/a.png"},
+		{B64JSON: base64.StdEncoding.EncodeToString(raw)},
+	}}
+	readers, err := res.Download(context.Background(), srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, rc := range readers {
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		if !bytes.Equal(b, raw) {
+			t.Errorf("image %d content mismatch", i)
+		}
+	}
+
+	dir := t.TempDir()
+	paths, err := res.SaveToDir(context.Background(), nil, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "image-42-1.png"); paths[1] != want {
+		t.Errorf("SaveToDir() path = %s, want %s", paths[1], want)
+	}
+	if _, err := os.Stat(paths[0]); err != nil {
+		t.Error(err)
+	}
+
+	res.Data = append(res.Data, ImageData{URL: srv.URL + "/missing.png"})
+	if _, err := res.Download(context.Background(), srv.Client()); err == nil {
+		t.Error("Download() expected error for missing image")
+	}
+}
+
+type countingTransport struct {
+	n *int32
+}
+
+func (ct countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	atomic.AddInt32(ct.n, 1)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestClient_DownloadImages(t *testing.T) {
+	raw := testPNG(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(raw)
+	}))
+	defer srv.Close()
+	var n int32
+	c := GetClient("test-token").SetHTTPClient(&http.Client{Transport: countingTransport{n: &n}})
+	res := ImageResponse{Data: []ImageData{{URL: srv.URL + "/a.png"}, {URL: srv.URL + "/b.png"}}}
+	readers, err := c.DownloadImages(context.Background(), res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rc := range readers {
+		rc.Close()
+	}
+	if n != 2 {
+		t.Errorf("DownloadImages() sent %d requests through the client transport, want 2", n)
+	}
+}
@@ -0,0 +1,202 @@
+package openai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// An ImageSink receives every successful CreateImage result of a Client.
+type ImageSink interface {
+	StoreImages(ctx context.Context, req Request, res ImageResponse, requestID string) error
+}
+
+// Metadata written next to every stored image.
+type ImageRecord struct {
+	Hash          string    `json:"hash"`
+	File          string    `json:"file"`
+	Prompt        string    `json:"prompt,omitempty"`
+	RevisedPrompt string    `json:"revised_prompt,omitempty"`
+	Size          string    `json:"size,omitempty"`
+	Model         string    `json:"model,omitempty"`
+	User          string    `json:"user,omitempty"`
+	RequestID     string    `json:"request_id,omitempty"`
+	Created       time.Time `json:"created"`
+}
+
+// Filters used by ImageStore.Search. Zero values match everything.
+type ImageQuery struct {
+	PromptContains string
+	Since          time.Time
+	Until          time.Time
+}
+
+// ImageStore is a content addressed ImageSink on the local filesystem.
+//
+// Images are stored as <dir>/<hash[:2]>/<hash>.<ext>, so identical images are only written
+// once. Every generation of an image gets its own <hash>-<id>.json sidecar holding the
+// ImageRecord, where id is derived from the record, so storing the same bytes again keeps
+// the records of earlier generations.
+type ImageStore struct {
+	dir        string
+	httpClient *http.Client
+	mu         sync.Mutex
+}
+
+// Creates an ImageStore rooted at dir, creating the directory if needed.
+func NewImageStore(dir string) (*ImageStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &ImageStore{dir: dir}, nil
+}
+
+// Sets the http.Client URL images are downloaded with, e.g. the one passed to
+// Client.SetHTTPClient. A default client is used until it is set.
+func (s *ImageStore) SetHTTPClient(hc *http.Client) *ImageStore {
+	s.httpClient = hc
+	return s
+}
+
+// Writes every image of the response to the store.
+func (s *ImageStore) StoreImages(ctx context.Context, req Request, res ImageResponse, requestID string) error {
+	readers, err := res.Download(ctx, s.httpClient)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, rc := range readers {
+			rc.Close()
+		}
+	}()
+	base := imageRecordFromRequest(req)
+	base.RequestID = requestID
+	base.Created = time.Unix(int64(res.Created), 0).UTC()
+	for i, rc := range readers {
+		b, err := io.ReadAll(rc)
+		if err != nil {
+			return fmt.Errorf("image %d: %w", i, err)
+		}
+		rec := base
+		rec.RevisedPrompt = res.Data[i].RevisedPrompt
+		if _, err = s.Put(b, rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Writes a single image with its metadata. Hash and File of rec are filled in by the store.
+//
+// @Returns the stored ImageRecord.
+func (s *ImageStore) Put(image []byte, rec ImageRecord) (ImageRecord, error) {
+	sum := sha256.Sum256(image)
+	rec.Hash = hex.EncodeToString(sum[:])
+	shard := filepath.Join(s.dir, rec.Hash[:2])
+	rec.File = filepath.Join(rec.Hash[:2], rec.Hash+imageExtension(image))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(shard, 0o755); err != nil {
+		return rec, err
+	}
+	path := filepath.Join(s.dir, rec.File)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err = os.WriteFile(path, image, 0o644); err != nil {
+			return rec, err
+		}
+	}
+	meta, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return rec, err
+	}
+	id := sha256.Sum256(meta)
+	return rec, os.WriteFile(filepath.Join(shard, rec.Hash+"-"+hex.EncodeToString(id[:8])+".json"), meta, 0o644)
+}
+
+// Returns the absolute path of the image file of a record.
+func (s *ImageStore) Path(rec ImageRecord) string {
+	return filepath.Join(s.dir, rec.File)
+}
+
+// Returns every stored record, oldest first.
+func (s *ImageStore) List() ([]ImageRecord, error) {
+	return s.Search(ImageQuery{})
+}
+
+// Returns the stored records matching the query, oldest first.
+//
+// PromptContains is matched case insensitively against the prompt and the revised prompt.
+func (s *ImageStore) Search(q ImageQuery) ([]ImageRecord, error) {
+	var recs []ImageRecord
+	needle := strings.ToLower(q.PromptContains)
+	err := filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".json" {
+			return err
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var rec ImageRecord
+		if err = json.Unmarshal(b, &rec); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if q.matches(rec, needle) {
+			recs = append(recs, rec)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(recs, func(i, j int) bool {
+		if recs[i].Created.Equal(recs[j].Created) {
+			if recs[i].Hash == recs[j].Hash {
+				return recs[i].RequestID < recs[j].RequestID
+			}
+			return recs[i].Hash < recs[j].Hash
+		}
+		return recs[i].Created.Before(recs[j].Created)
+	})
+	return recs, nil
+}
+
+func (q ImageQuery) matches(rec ImageRecord, needle string) bool {
+	if needle != "" &&
+		!strings.Contains(strings.ToLower(rec.Prompt), needle) &&
+		!strings.Contains(strings.ToLower(rec.RevisedPrompt), needle) {
+		return false
+	}
+	if !q.Since.IsZero() && rec.Created.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && rec.Created.After(q.Until) {
+		return false
+	}
+	return true
+}
+
+func imageRecordFromRequest(req Request) ImageRecord {
+	switch r := req.(type) {
+	case *ImageRequest:
+		return ImageRecord{Prompt: r.Prompt, Size: r.Size, Model: r.Model, User: r.User}
+	case *ImageEditRequest:
+		return ImageRecord{Prompt: r.Prompt, Size: r.Size, Model: r.Model, User: r.User}
+	case *ImageVariationRequest:
+		return ImageRecord{Prompt: r.Prompt, Size: r.Size, Model: r.Model, User: r.User}
+	default:
+		return ImageRecord{}
+	}
+}
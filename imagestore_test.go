@@ -0,0 +1,92 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/EthanCampana/go-openai"
+)
+
+func TestImageStore_StoreImagesAndSearch(t *testing.T) {
+	store, err := NewImageStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := testPNG(t)
+	req := &ImageRequest{Prompt: "A Chicken With Glasses", Size: LARGE, Model: ImageModelDallE3, User: "u1"}
+	res := ImageResponse{Created: 1700000000, Data: []ImageData{
+		{B64JSON: base64.StdEncoding.EncodeToString(raw), RevisedPrompt: "A hen wearing round glasses"},
+	}}
+	if err = store.StoreImages(context.Background(), req, res, "req_123"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = store.Put([]byte("GIF89a-not-really"), ImageRecord{Prompt: "A Cat", Created: time.Unix(1800000000, 0)}); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[0].Prompt != req.Prompt {
+		t.Fatalf("ImageStore.List() = %+v", all)
+	}
+	rec := all[0]
+	if rec.RequestID != "req_123" || rec.Model != ImageModelDallE3 || rec.User != "u1" || rec.Size != LARGE {
+		t.Errorf("ImageStore.List() metadata = %+v", rec)
+	}
+	if b, err := os.ReadFile(store.Path(rec)); err != nil || string(b) != string(raw) {
+		t.Errorf("stored image mismatch, err = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query ImageQuery
+		want  int
+	}{
+		{name: "Prompt substring", query: ImageQuery{PromptContains: "chicken"}, want: 1},
+		{name: "Revised prompt substring", query: ImageQuery{PromptContains: "ROUND GLASSES"}, want: 1},
+		{name: "Since", query: ImageQuery{Since: time.Unix(1750000000, 0)}, want: 1},
+		{name: "Until", query: ImageQuery{Until: time.Unix(1600000000, 0)}, want: 0},
+		{name: "No match", query: ImageQuery{PromptContains: "dog"}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.Search(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("ImageStore.Search() = %d records, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestImageStore_PutSameImageTwice(t *testing.T) {
+	store, err := NewImageStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := testPNG(t)
+	first := ImageRecord{Prompt: "A red barn", RequestID: "req_1", Created: time.Unix(1700000000, 0).UTC()}
+	second := ImageRecord{Prompt: "A barn at dusk", RequestID: "req_2", Created: time.Unix(1700000500, 0).UTC()}
+	for _, rec := range []ImageRecord{first, second, first} {
+		if _, err = store.Put(raw, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	all, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[0].RequestID != "req_1" || all[1].RequestID != "req_2" || all[0].File != all[1].File {
+		t.Fatalf("ImageStore.List() = %+v", all)
+	}
+	if got, _ := store.Search(ImageQuery{PromptContains: "red barn"}); len(got) != 1 || !got[0].Created.Equal(first.Created) {
+		t.Errorf("ImageStore.Search() = %+v, want the first generation", got)
+	}
+}
@@ -0,0 +1,314 @@
+// Package websocket implements the parts of RFC 6455 needed by the realtime client and
+// its test servers: the opening handshake, text and binary messages, ping/pong and close.
+// Extensions and subprotocol negotiation are not supported.
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	OpContinuation = 0x0
+	OpText         = 0x1
+	OpBinary       = 0x2
+	OpClose        = 0x8
+	OpPing         = 0x9
+	OpPong         = 0xa
+)
+
+const (
+	CloseNormal        = 1000
+	CloseGoingAway     = 1001
+	CloseProtocolError = 1002
+	CloseNoStatus      = 1005
+)
+
+// Largest message accepted by ReadMessage.
+const maxMessageSize = 32 << 20
+
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// CloseError is returned by ReadMessage once the peer closed the connection.
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("websocket closed with code %d", e.Code)
+	}
+	return fmt.Sprintf("websocket closed with code %d: %s", e.Code, e.Reason)
+}
+
+// Conn is a websocket connection. ReadMessage must be called from a single goroutine,
+// WriteMessage and Close may be called concurrently.
+type Conn struct {
+	rw     io.ReadWriteCloser
+	br     *bufio.Reader
+	client bool
+
+	wmu        sync.Mutex
+	closeSent  bool
+	closeOnce  sync.Once
+	closeError error
+}
+
+// Creates a Conn on an established connection. Clients mask their frames, servers do not.
+func NewConn(rw io.ReadWriteCloser, client bool) *Conn {
+	return &Conn{rw: rw, br: bufio.NewReader(rw), client: client}
+}
+
+// Returns a random Sec-WebSocket-Key for the opening handshake.
+func NewKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// Returns the Sec-WebSocket-Accept value the server answers key with.
+func AcceptKey(key string) string {
+	h := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// Sets the headers of a client opening handshake and returns the key to verify the answer with.
+func SetHandshakeHeaders(h http.Header) (string, error) {
+	key, err := NewKey()
+	if err != nil {
+		return "", err
+	}
+	h.Set("Connection", "Upgrade")
+	h.Set("Upgrade", "websocket")
+	h.Set("Sec-WebSocket-Version", "13")
+	h.Set("Sec-WebSocket-Key", key)
+	return key, nil
+}
+
+// Checks the server answer to a client opening handshake.
+func CheckHandshake(res *http.Response, key string) error {
+	if res.StatusCode != http.StatusSwitchingProtocols {
+		return fmt.Errorf("websocket handshake failed with status code %d", res.StatusCode)
+	}
+	if !strings.EqualFold(res.Header.Get("Upgrade"), "websocket") {
+		return errors.New("websocket handshake: missing Upgrade header")
+	}
+	if res.Header.Get("Sec-WebSocket-Accept") != AcceptKey(key) {
+		return errors.New("websocket handshake: invalid Sec-WebSocket-Accept")
+	}
+	return nil
+}
+
+// Upgrades a server request to a websocket connection.
+func Accept(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || key == "" {
+		http.Error(w, "websocket upgrade required", http.StatusBadRequest)
+		return nil, errors.New("request is not a websocket upgrade")
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, errors.New("response writer does not support hijacking")
+	}
+	conn, brw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(brw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", AcceptKey(key))
+	if err = brw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	c := NewConn(conn, false)
+	c.br = brw.Reader
+	return c, nil
+}
+
+// Writes a single unfragmented message.
+func (c *Conn) WriteMessage(op int, data []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.closeSent {
+		return errors.New("websocket is closed")
+	}
+	if op == OpClose {
+		c.closeSent = true
+	}
+	return c.writeFrame(op, data)
+}
+
+func (c *Conn) writeFrame(op int, data []byte) error {
+	header := make([]byte, 2, 14)
+	header[0] = 0x80 | byte(op)
+	var mask byte
+	if c.client {
+		mask = 0x80
+	}
+	switch n := len(data); {
+	case n < 126:
+		header[1] = mask | byte(n)
+	case n <= 0xffff:
+		header[1] = mask | 126
+		header = append(header, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(n))
+	default:
+		header[1] = mask | 127
+		header = append(header, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(n))
+	}
+	payload := data
+	if c.client {
+		key := make([]byte, 4)
+		if _, err := rand.Read(key); err != nil {
+			return err
+		}
+		header = append(header, key...)
+		payload = make([]byte, len(data))
+		for i := range data {
+			payload[i] = data[i] ^ key[i%4]
+		}
+	}
+	if _, err := c.rw.Write(append(header, payload...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Reads the next text or binary message. Pings are answered and fragmented messages are
+// reassembled. Once the peer closes the connection the close is acknowledged and a
+// *CloseError is returned; a malformed close frame is answered with CloseProtocolError.
+func (c *Conn) ReadMessage() (op int, data []byte, err error) {
+	var msg []byte
+	msgOp := -1
+	for {
+		fin, frameOp, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+		switch frameOp {
+		case OpPing:
+			c.wmu.Lock()
+			if !c.closeSent {
+				err = c.writeFrame(OpPong, payload)
+			}
+			c.wmu.Unlock()
+			if err != nil {
+				return 0, nil, err
+			}
+			continue
+		case OpPong:
+			continue
+		case OpClose:
+			closeErr := &CloseError{Code: CloseNoStatus}
+			switch {
+			case len(payload) == 1:
+				// The status code takes two bytes, a single one is a protocol error.
+				closeErr.Code = CloseProtocolError
+				closeErr.Reason = "invalid close frame payload"
+				payload = binary.BigEndian.AppendUint16(nil, CloseProtocolError)
+			case len(payload) >= 2:
+				closeErr.Code = int(binary.BigEndian.Uint16(payload))
+				closeErr.Reason = string(payload[2:])
+				// Echo the status code only, as RFC 6455 suggests.
+				payload = payload[:2]
+			}
+			c.wmu.Lock()
+			if !c.closeSent {
+				c.closeSent = true
+				c.writeFrame(OpClose, payload)
+			}
+			c.wmu.Unlock()
+			return 0, nil, closeErr
+		case OpContinuation:
+			if msgOp < 0 {
+				return 0, nil, errors.New("websocket: unexpected continuation frame")
+			}
+		case OpText, OpBinary:
+			if msgOp >= 0 {
+				return 0, nil, errors.New("websocket: expected continuation frame")
+			}
+			msgOp = frameOp
+		default:
+			return 0, nil, fmt.Errorf("websocket: unknown opcode %#x", frameOp)
+		}
+		if len(msg)+len(payload) > maxMessageSize {
+			return 0, nil, errors.New("websocket: message too large")
+		}
+		msg = append(msg, payload...)
+		if fin {
+			return msgOp, msg, nil
+		}
+	}
+}
+
+func (c *Conn) readFrame() (fin bool, op int, payload []byte, err error) {
+	var head [2]byte
+	if _, err = io.ReadFull(c.br, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = head[0]&0x80 != 0
+	op = int(head[0] & 0x0f)
+	masked := head[1]&0x80 != 0
+	n := uint64(head[1] & 0x7f)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if n > maxMessageSize {
+		return false, 0, nil, errors.New("websocket: frame too large")
+	}
+	var key [4]byte
+	if masked {
+		if _, err = io.ReadFull(c.br, key[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload = make([]byte, n)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= key[i%4]
+		}
+	}
+	return fin, op, payload, nil
+}
+
+// Starts the closing handshake by sending a close frame. The connection stays readable
+// until the peer acknowledges the close, see ReadMessage.
+func (c *Conn) CloseHandshake(code int, reason string) error {
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	return c.WriteMessage(OpClose, append(payload, reason...))
+}
+
+// Closes the underlying connection without a closing handshake.
+func (c *Conn) Close() error {
+	c.closeOnce.Do(func() {
+		c.closeError = c.rw.Close()
+	})
+	return c.closeError
+}
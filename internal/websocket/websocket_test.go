@@ -0,0 +1,219 @@
+package websocket_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/EthanCampana/go-openai/internal/websocket"
+)
+
+// An in-memory connection reading the given frames and recording everything written.
+type fakeConn struct {
+	io.Reader
+	written bytes.Buffer
+}
+
+func (c *fakeConn) Write(p []byte) (int, error) { return c.written.Write(p) }
+func (c *fakeConn) Close() error                { return nil }
+
+// Encodes a single frame, masked with a fixed key when mask is set.
+func frame(fin bool, op int, payload []byte, mask bool) []byte {
+	b := []byte{byte(op), 0}
+	if fin {
+		b[0] |= 0x80
+	}
+	if mask {
+		b[1] = 0x80
+	}
+	switch n := len(payload); {
+	case n < 126:
+		b[1] |= byte(n)
+	case n <= 0xffff:
+		b[1] |= 126
+		b = binary.BigEndian.AppendUint16(b, uint16(n))
+	default:
+		b[1] |= 127
+		b = binary.BigEndian.AppendUint64(b, uint64(n))
+	}
+	if !mask {
+		return append(b, payload...)
+	}
+	key := []byte{0x12, 0x34, 0x56, 0x78}
+	b = append(b, key...)
+	for i, c := range payload {
+		b = append(b, c^key[i%4])
+	}
+	return b
+}
+
+func closePayload(code int, reason string) []byte {
+	return append(binary.BigEndian.AppendUint16(nil, uint16(code)), reason...)
+}
+
+func newConn(client bool, frames ...[]byte) (*websocket.Conn, *fakeConn) {
+	rw := &fakeConn{Reader: bytes.NewReader(bytes.Join(frames, nil))}
+	return websocket.NewConn(rw, client), rw
+}
+
+// Reads the frames written by a Conn.
+func written(t *testing.T, rw *fakeConn) []*fakeFrame {
+	t.Helper()
+	var frames []*fakeFrame
+	r := bytes.NewReader(rw.written.Bytes())
+	for r.Len() > 0 {
+		f, err := readFrame(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		frames = append(frames, f)
+	}
+	return frames
+}
+
+type fakeFrame struct {
+	op      int
+	masked  bool
+	payload []byte
+}
+
+func readFrame(r io.Reader) (*fakeFrame, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return nil, err
+	}
+	f := &fakeFrame{op: int(head[0] & 0x0f), masked: head[1]&0x80 != 0}
+	n := int(head[1] & 0x7f)
+	if n >= 126 {
+		return nil, errors.New("long frames are not written in these tests")
+	}
+	var key [4]byte
+	if f.masked {
+		if _, err := io.ReadFull(r, key[:]); err != nil {
+			return nil, err
+		}
+	}
+	f.payload = make([]byte, n)
+	if _, err := io.ReadFull(r, f.payload); err != nil {
+		return nil, err
+	}
+	for i := range f.payload {
+		f.payload[i] ^= key[i%4]
+	}
+	return f, nil
+}
+
+func TestConn_ReadMessage_Fragmented(t *testing.T) {
+	conn, rw := newConn(false,
+		frame(false, websocket.OpText, []byte("Hel"), true),
+		frame(true, websocket.OpPing, []byte("p1"), true),
+		frame(false, websocket.OpContinuation, []byte("lo, "), true),
+		frame(true, websocket.OpPong, nil, true),
+		frame(true, websocket.OpContinuation, []byte("world"), true),
+		frame(true, websocket.OpBinary, []byte{1, 2}, true),
+	)
+	op, data, err := conn.ReadMessage()
+	if err != nil || op != websocket.OpText || string(data) != "Hello, world" {
+		t.Errorf("ReadMessage() = %d %q, %v, want the reassembled text message", op, data, err)
+	}
+	op, data, err = conn.ReadMessage()
+	if err != nil || op != websocket.OpBinary || !bytes.Equal(data, []byte{1, 2}) {
+		t.Errorf("second ReadMessage() = %d %v, %v", op, data, err)
+	}
+	frames := written(t, rw)
+	if len(frames) != 1 || frames[0].op != websocket.OpPong || string(frames[0].payload) != "p1" {
+		t.Errorf("written frames = %+v, want a pong echoing the ping", frames)
+	}
+}
+
+func TestConn_ReadMessage_InvalidFragments(t *testing.T) {
+	tests := []struct {
+		name   string
+		frames [][]byte
+	}{
+		{"continuation without a message", [][]byte{frame(true, websocket.OpContinuation, []byte("x"), false)}},
+		{"new message before the last fragment", [][]byte{
+			frame(false, websocket.OpText, []byte("a"), false),
+			frame(true, websocket.OpText, []byte("b"), false),
+		}},
+		{"unknown opcode", [][]byte{frame(true, 0x3, nil, false)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, _ := newConn(true, tt.frames...)
+			if _, _, err := conn.ReadMessage(); err == nil {
+				t.Error("ReadMessage() succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestConn_WriteMessage_Masking(t *testing.T) {
+	for _, client := range []bool{true, false} {
+		conn, rw := newConn(client)
+		if err := conn.WriteMessage(websocket.OpText, []byte("hello")); err != nil {
+			t.Fatal(err)
+		}
+		frames := written(t, rw)
+		if len(frames) != 1 || frames[0].masked != client || string(frames[0].payload) != "hello" {
+			t.Errorf("client %v wrote %+v, want a text frame masked by clients only", client, frames)
+		}
+		// The frame is read back by the other side.
+		peer, _ := newConn(!client, rw.written.Bytes())
+		if _, data, err := peer.ReadMessage(); err != nil || string(data) != "hello" {
+			t.Errorf("peer ReadMessage() = %q, %v", data, err)
+		}
+	}
+
+	conn, rw := newConn(true)
+	long := strings.Repeat("x", 70000)
+	if err := conn.WriteMessage(websocket.OpBinary, []byte(long)); err != nil {
+		t.Fatal(err)
+	}
+	peer, _ := newConn(false, rw.written.Bytes())
+	if _, data, err := peer.ReadMessage(); err != nil || string(data) != long {
+		t.Errorf("ReadMessage() of a 64 bit length frame = %d bytes, %v", len(data), err)
+	}
+}
+
+func TestConn_ReadMessage_Oversized(t *testing.T) {
+	head := []byte{0x80 | websocket.OpBinary, 127}
+	head = binary.BigEndian.AppendUint64(head, 1<<40)
+	conn, _ := newConn(true, head)
+	if _, _, err := conn.ReadMessage(); err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Errorf("ReadMessage() error = %v, want a frame too large error", err)
+	}
+}
+
+func TestConn_ReadMessage_Close(t *testing.T) {
+	tests := []struct {
+		name      string
+		payload   []byte
+		wantCode  int
+		wantReply []byte
+	}{
+		{"status and reason", closePayload(websocket.CloseGoingAway, "bye"), websocket.CloseGoingAway, closePayload(websocket.CloseGoingAway, "")},
+		{"no status", nil, websocket.CloseNoStatus, nil},
+		{"one byte", []byte{0x03}, websocket.CloseProtocolError, closePayload(websocket.CloseProtocolError, "")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, rw := newConn(false, frame(true, websocket.OpClose, tt.payload, true))
+			_, _, err := conn.ReadMessage()
+			var closeErr *websocket.CloseError
+			if !errors.As(err, &closeErr) || closeErr.Code != tt.wantCode {
+				t.Fatalf("ReadMessage() error = %v, want close code %d", err, tt.wantCode)
+			}
+			frames := written(t, rw)
+			if len(frames) != 1 || frames[0].op != websocket.OpClose || !bytes.Equal(frames[0].payload, tt.wantReply) {
+				t.Errorf("written frames = %+v, want a close frame with %v", frames, tt.wantReply)
+			}
+			if err := conn.WriteMessage(websocket.OpText, []byte("late")); err == nil {
+				t.Error("WriteMessage() after the close succeeded")
+			}
+		})
+	}
+}
@@ -0,0 +1,591 @@
+package openai
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// A JSON Schema as accepted by tool parameters and the json_schema response format.
+type JSONSchema struct {
+	Type                 string                 `json:"type,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties interface{}            `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	MinLength            *int                   `json:"minLength,omitempty"`
+	MaxLength            *int                   `json:"maxLength,omitempty"`
+	MinItems             *int                   `json:"minItems,omitempty"`
+	MaxItems             *int                   `json:"maxItems,omitempty"`
+	AnyOf                []*JSONSchema          `json:"anyOf,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Defs                 map[string]*JSONSchema `json:"$defs,omitempty"`
+	// Nullable adds null to the accepted types. It is encoded as "type": [Type, "null"].
+	Nullable bool `json:"-"`
+}
+
+type jsonSchemaAlias JSONSchema
+
+func (s JSONSchema) MarshalJSON() ([]byte, error) {
+	if !s.Nullable || s.Type == "" {
+		return json.Marshal(jsonSchemaAlias(s))
+	}
+	alias := jsonSchemaAlias(s)
+	alias.Type = ""
+	return json.Marshal(struct {
+		Type []string `json:"type"`
+		jsonSchemaAlias
+	}{Type: []string{s.Type, "null"}, jsonSchemaAlias: alias})
+}
+
+// SchemaReflector generates a JSONSchema from Go types.
+//
+// Field names follow the json tag. Fields without omitempty are required. The jsonschema tag
+// accepts comma separated options: description=..., enum=a|b|c, required, minimum=, maximum=,
+// minLength=, maxLength=, minItems=, maxItems= and format=. Descriptions containing commas can
+// be set with the jsonschema_description tag instead.
+//
+// With Strict set the schema follows the rules of OpenAI strict mode: every property is required,
+// optional properties become nullable, objects reject additional properties and maps or
+// interfaces are reported as errors since strict mode can not express them.
+type SchemaReflector struct {
+	Strict bool
+}
+
+type reflectState struct {
+	root      reflect.Type
+	inflight  map[reflect.Type]bool
+	recursive map[reflect.Type]bool
+	defs      map[string]*JSONSchema
+	defNames  map[reflect.Type]string
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// Generates a non strict JSONSchema for the type of v.
+func GenerateSchema(v interface{}) (*JSONSchema, error) {
+	return SchemaReflector{}.Reflect(v)
+}
+
+// Generates a strict mode JSONSchema for the type of v, which must be a struct.
+func GenerateStrictSchema(v interface{}) (*JSONSchema, error) {
+	return SchemaReflector{Strict: true}.Reflect(v)
+}
+
+// Generates the JSONSchema of the type of v.
+func (r SchemaReflector) Reflect(v interface{}) (*JSONSchema, error) {
+	if v == nil {
+		return nil, fmt.Errorf("cannot reflect a schema from nil")
+	}
+	return r.ReflectType(reflect.TypeOf(v))
+}
+
+// Generates the JSONSchema of t. Recursive types are emitted through $defs.
+func (r SchemaReflector) ReflectType(t reflect.Type) (*JSONSchema, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if r.Strict && t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("strict schemas require a struct at the root, got %s", t)
+	}
+	st := &reflectState{
+		root:      t,
+		inflight:  map[reflect.Type]bool{},
+		recursive: map[reflect.Type]bool{},
+		defs:      map[string]*JSONSchema{},
+		defNames:  map[reflect.Type]string{},
+	}
+	schema, err := r.reflect(st, t)
+	if err != nil {
+		return nil, err
+	}
+	if len(st.defs) > 0 {
+		schema.Defs = st.defs
+	}
+	return schema, nil
+}
+
+func (r SchemaReflector) reflect(st *reflectState, t reflect.Type) (*JSONSchema, error) {
+	if t.Kind() == reflect.Ptr {
+		s, err := r.reflect(st, t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return nullable(s), nil
+	}
+	switch t {
+	case timeType:
+		return &JSONSchema{Type: "string", Format: "date-time"}, nil
+	case rawMessageType:
+		if r.Strict {
+			return nil, fmt.Errorf("strict schemas cannot describe %s", t)
+		}
+		return &JSONSchema{}, nil
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &JSONSchema{Type: "integer"}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		zero := 0.0
+		return &JSONSchema{Type: "integer", Minimum: &zero}, nil
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}, nil
+	case reflect.String:
+		return &JSONSchema{Type: "string"}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &JSONSchema{Type: "string"}, nil
+		}
+		items, err := r.reflect(st, t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &JSONSchema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		if r.Strict {
+			return nil, fmt.Errorf("strict schemas cannot describe map type %s", t)
+		}
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map keys must be strings, got %s", t)
+		}
+		values, err := r.reflect(st, t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &JSONSchema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Interface:
+		if r.Strict {
+			return nil, fmt.Errorf("strict schemas cannot describe interface type %s", t)
+		}
+		return &JSONSchema{}, nil
+	case reflect.Struct:
+		return r.reflectStruct(st, t)
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+func (r SchemaReflector) reflectStruct(st *reflectState, t reflect.Type) (*JSONSchema, error) {
+	if st.inflight[t] {
+		st.recursive[t] = true
+		return &JSONSchema{Ref: defRef(st, t)}, nil
+	}
+	st.inflight[t] = true
+	defer delete(st.inflight, t)
+
+	schema := &JSONSchema{Type: "object", Properties: map[string]*JSONSchema{}}
+	if r.Strict {
+		schema.AdditionalProperties = false
+	}
+	if err := r.addFields(st, t, schema); err != nil {
+		return nil, err
+	}
+	if st.recursive[t] && t != st.root {
+		st.defs[st.defName(t)] = schema
+		return &JSONSchema{Ref: defRef(st, t)}, nil
+	}
+	return schema, nil
+}
+
+// Allows null next to the schema. References can not carry a type so they are wrapped in anyOf.
+func nullable(s *JSONSchema) *JSONSchema {
+	if len(s.AnyOf) > 0 {
+		return s
+	}
+	if s.Ref == "" {
+		s.Nullable = true
+		// The enum restricts the value further, null has to be one of its values too.
+		if len(s.Enum) > 0 && !enumContains(s.Enum, nil) {
+			s.Enum = append(s.Enum, nil)
+		}
+		return s
+	}
+	return &JSONSchema{AnyOf: []*JSONSchema{s, {Type: "null"}}}
+}
+
+func defRef(st *reflectState, t reflect.Type) string {
+	if t == st.root {
+		return "#"
+	}
+	return "#/$defs/" + st.defName(t)
+}
+
+// Returns the $defs key of t: its package path and name, so that same-named types of
+// different packages do not collide. Characters other than letters, digits, '.', '_' and
+// '-' are replaced by '_' to keep the key usable in a $ref. Types that still share a key,
+// e.g. types declared in different functions, get a numeric suffix.
+func (st *reflectState) defName(t reflect.Type) string {
+	if name, ok := st.defNames[t]; ok {
+		return name
+	}
+	base := t.Name()
+	if t.PkgPath() != "" {
+		base = t.PkgPath() + "." + base
+	}
+	base = strings.Map(func(r rune) rune {
+		switch {
+		case r == '/':
+			return '.'
+		case r == '.' || r == '_' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			return r
+		}
+		return '_'
+	}, base)
+	name := base
+	for i := 2; st.defNameTaken(name); i++ {
+		name = base + "_" + strconv.Itoa(i)
+	}
+	st.defNames[t] = name
+	return name
+}
+
+func (st *reflectState) defNameTaken(name string) bool {
+	for _, n := range st.defNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r SchemaReflector) addFields(st *reflectState, t reflect.Type, schema *JSONSchema) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, omitempty, skip := jsonFieldName(f)
+		if skip {
+			continue
+		}
+		if f.Anonymous && f.Tag.Get("json") == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if err := r.addFields(st, ft, schema); err != nil {
+					return err
+				}
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+		}
+		prop, err := r.reflect(st, f.Type)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), f.Name, err)
+		}
+		required, err := applySchemaTags(prop, f)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), f.Name, err)
+		}
+		if prop.Nullable {
+			// The tags may have added an enum, which has to accept null as well.
+			prop = nullable(prop)
+		}
+		required = required || !omitempty
+		if r.Strict {
+			if !required {
+				prop = nullable(prop)
+			}
+			required = true
+		}
+		schema.Properties[name] = prop
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return nil
+}
+
+func jsonFieldName(f reflect.StructField) (name string, omitempty bool, skip bool) {
+	if !f.IsExported() && !f.Anonymous {
+		return "", false, true
+	}
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = f.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty, false
+}
+
+// Applies the jsonschema tags of the field to prop and reports whether the field is marked as required.
+func applySchemaTags(prop *JSONSchema, f reflect.StructField) (bool, error) {
+	required := false
+	if desc := f.Tag.Get("jsonschema_description"); desc != "" {
+		prop.Description = desc
+	}
+	tag := f.Tag.Get("jsonschema")
+	if tag == "" {
+		return false, nil
+	}
+	for _, opt := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(opt, "=")
+		var err error
+		switch key {
+		case "required":
+			required = true
+		case "description":
+			prop.Description = value
+		case "format":
+			prop.Format = value
+		case "enum":
+			prop.Enum, err = parseEnum(prop, f.Type, strings.Split(value, "|"))
+		case "minimum":
+			prop.Minimum, err = parseFloat(value)
+		case "maximum":
+			prop.Maximum, err = parseFloat(value)
+		case "minLength":
+			prop.MinLength, err = parseInt(value)
+		case "maxLength":
+			prop.MaxLength, err = parseInt(value)
+		case "minItems":
+			prop.MinItems, err = parseInt(value)
+		case "maxItems":
+			prop.MaxItems, err = parseInt(value)
+		default:
+			err = fmt.Errorf("unknown jsonschema option %q", key)
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	return required, nil
+}
+
+func parseEnum(prop *JSONSchema, t reflect.Type, values []string) ([]interface{}, error) {
+	target := prop
+	if prop.Type == "array" && prop.Items != nil {
+		target = prop.Items
+	}
+	enum := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		switch target.Type {
+		case "integer":
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("enum value %q of %s: %w", v, t, err)
+			}
+			enum = append(enum, n)
+		case "number":
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("enum value %q of %s: %w", v, t, err)
+			}
+			enum = append(enum, n)
+		case "boolean":
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("enum value %q of %s: %w", v, t, err)
+			}
+			enum = append(enum, b)
+		default:
+			enum = append(enum, v)
+		}
+	}
+	if target != prop {
+		target.Enum = enum
+		return nil, nil
+	}
+	return enum, nil
+}
+
+func parseFloat(v string) (*float64, error) {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func parseInt(v string) (*int, error) {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// Validates a decoded JSON value (as produced by json.Unmarshal into interface{}) against the schema.
+//
+// Supports the subset of JSON Schema produced by SchemaReflector.
+func (s *JSONSchema) Validate(v interface{}) error {
+	return s.validate(s, "$", v)
+}
+
+func (s *JSONSchema) validate(root *JSONSchema, path string, v interface{}) error {
+	if s.Ref != "" {
+		target, err := root.resolve(s.Ref)
+		if err != nil {
+			return err
+		}
+		return target.validate(root, path, v)
+	}
+	if len(s.AnyOf) > 0 {
+		var errs []error
+		for _, alt := range s.AnyOf {
+			err := alt.validate(root, path, v)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, err)
+		}
+		return fmt.Errorf("%s matches none of the alternatives: %v", path, errs[0])
+	}
+	if len(s.Enum) > 0 && !enumContains(s.Enum, v) {
+		return fmt.Errorf("%s must be one of %v, got %v", path, s.Enum, v)
+	}
+	if v == nil {
+		if s.Nullable || s.Type == "null" || s.Type == "" {
+			return nil
+		}
+		return fmt.Errorf("%s must not be null", path)
+	}
+	switch s.Type {
+	case "":
+		return nil
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return typeError(path, s.Type, v)
+		}
+		if s.MinLength != nil && len([]rune(str)) < *s.MinLength {
+			return fmt.Errorf("%s must be at least %d characters", path, *s.MinLength)
+		}
+		if s.MaxLength != nil && len([]rune(str)) > *s.MaxLength {
+			return fmt.Errorf("%s must be at most %d characters", path, *s.MaxLength)
+		}
+	case "integer", "number":
+		n, ok := v.(float64)
+		if !ok || (s.Type == "integer" && n != float64(int64(n))) {
+			return typeError(path, s.Type, v)
+		}
+		if s.Minimum != nil && n < *s.Minimum {
+			return fmt.Errorf("%s must be >= %v", path, *s.Minimum)
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			return fmt.Errorf("%s must be <= %v", path, *s.Maximum)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return typeError(path, s.Type, v)
+		}
+	case "array":
+		items, ok := v.([]interface{})
+		if !ok {
+			return typeError(path, s.Type, v)
+		}
+		if s.MinItems != nil && len(items) < *s.MinItems {
+			return fmt.Errorf("%s must have at least %d items", path, *s.MinItems)
+		}
+		if s.MaxItems != nil && len(items) > *s.MaxItems {
+			return fmt.Errorf("%s must have at most %d items", path, *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range items {
+				if err := s.Items.validate(root, fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+					return err
+				}
+			}
+		}
+	case "object":
+		return s.validateObject(root, path, v)
+	case "null":
+		return typeError(path, s.Type, v)
+	}
+	return nil
+}
+
+func (s *JSONSchema) validateObject(root *JSONSchema, path string, v interface{}) error {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return typeError(path, s.Type, v)
+	}
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			return fmt.Errorf("%s.%s is required", path, name)
+		}
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		prop, ok := s.Properties[k]
+		if !ok {
+			switch extra := s.AdditionalProperties.(type) {
+			case bool:
+				if !extra {
+					return fmt.Errorf("%s.%s is not allowed", path, k)
+				}
+				continue
+			case *JSONSchema:
+				prop = extra
+			default:
+				continue
+			}
+		}
+		if err := prop.validate(root, path+"."+k, obj[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *JSONSchema) resolve(ref string) (*JSONSchema, error) {
+	if ref == "#" {
+		return s, nil
+	}
+	const prefix = "#/$defs/"
+	if len(ref) > len(prefix) && ref[:len(prefix)] == prefix {
+		if def, ok := s.Defs[ref[len(prefix):]]; ok {
+			return def, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot resolve schema reference %s", ref)
+}
+
+func enumContains(enum []interface{}, v interface{}) bool {
+	for _, e := range enum {
+		switch n := e.(type) {
+		case int64:
+			if f, ok := v.(float64); ok && f == float64(n) {
+				return true
+			}
+		default:
+			if e == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func typeError(path, want string, v interface{}) error {
+	return fmt.Errorf("%s must be of type %s, got %T", path, want, v)
+}
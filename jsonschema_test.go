@@ -0,0 +1,203 @@
+package openai_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/EthanCampana/go-openai"
+)
+
+type schemaAddress struct {
+	City string `json:"city" jsonschema:"description=Name of the city"`
+	Zip  string `json:"zip,omitempty" jsonschema:"minLength=5,maxLength=5"`
+}
+
+type schemaNode struct {
+	Name     string       `json:"name"`
+	Children []schemaNode `json:"children"`
+	Parent   *schemaNode  `json:"parent,omitempty"`
+}
+
+type schemaPerson struct {
+	Name    string            `json:"name" jsonschema_description:"Full name, as written in the passport"`
+	Age     uint8             `json:"age" jsonschema:"maximum=150"`
+	Unit    string            `json:"unit" jsonschema:"enum=metric|imperial"`
+	Scores  []int             `json:"scores,omitempty" jsonschema:"minItems=1,enum=1|2|3"`
+	Home    *schemaAddress    `json:"home"`
+	Born    time.Time         `json:"born"`
+	Tags    map[string]string `json:"tags,omitempty"`
+	Tree    schemaNode        `json:"tree"`
+	private string
+	Skipped string `json:"-"`
+}
+
+func TestGenerateSchema(t *testing.T) {
+	schema, err := GenerateSchema(schemaPerson{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(schema)
+	want := `{"type":"object","properties":{` +
+		`"age":{"type":"integer","minimum":0,"maximum":150},` +
+		`"born":{"type":"string","format":"date-time"},` +
+		`"home":{"type":["object","null"],"properties":{"city":{"type":"string","description":"Name of the city"},"zip":{"type":"string","minLength":5,"maxLength":5}},"required":["city"]},` +
+		`"name":{"type":"string","description":"Full name, as written in the passport"},` +
+		`"scores":{"type":"array","items":{"type":"integer","enum":[1,2,3]},"minItems":1},` +
+		`"tags":{"type":"object","additionalProperties":{"type":"string"}},` +
+		`"tree":{"$ref":"#/$defs/github.com.EthanCampana.go-openai_test.schemaNode"},` +
+		`"unit":{"type":"string","enum":["metric","imperial"]}},` +
+		`"required":["name","age","unit","home","born","tree"],` +
+		`"$defs":{"github.com.EthanCampana.go-openai_test.schemaNode":{"type":"object","properties":{` +
+		`"children":{"type":"array","items":{"$ref":"#/$defs/github.com.EthanCampana.go-openai_test.schemaNode"}},` +
+		`"name":{"type":"string"},` +
+		`"parent":{"anyOf":[{"$ref":"#/$defs/github.com.EthanCampana.go-openai_test.schemaNode"},{"type":"null"}]}},` +
+		`"required":["name","children"]}}}`
+	if string(got) != want {
+		t.Errorf("GenerateSchema() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateStrictSchema(t *testing.T) {
+	if _, err := GenerateStrictSchema(schemaPerson{}); err == nil {
+		t.Error("GenerateStrictSchema() expected error for map field")
+	}
+	if _, err := GenerateStrictSchema("text"); err == nil {
+		t.Error("GenerateStrictSchema() expected error for non struct root")
+	}
+	schema, err := GenerateStrictSchema(&schemaNode{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(schema)
+	want := `{"type":"object","properties":{` +
+		`"children":{"type":"array","items":{"$ref":"#"}},` +
+		`"name":{"type":"string"},` +
+		`"parent":{"anyOf":[{"$ref":"#"},{"type":"null"}]}},` +
+		`"required":["name","children","parent"],"additionalProperties":false}`
+	if string(got) != want {
+		t.Errorf("GenerateStrictSchema() =\n%s\nwant\n%s", got, want)
+	}
+
+	format, err := NewJSONSchemaResponseFormat("address", schemaAddress{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ = json.Marshal(format)
+	want = `{"type":"json_schema","json_schema":{"name":"address","schema":{"type":"object","properties":{` +
+		`"city":{"type":"string","description":"Name of the city"},` +
+		`"zip":{"type":["string","null"],"minLength":5,"maxLength":5}},` +
+		`"required":["city","zip"],"additionalProperties":false},"strict":true}}`
+	if string(got) != want {
+		t.Errorf("NewJSONSchemaResponseFormat() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateStrictSchema_OptionalEnum(t *testing.T) {
+	type paint struct {
+		Color string `json:"color,omitempty" jsonschema:"enum=red|blue"`
+		Shade string `json:"shade" jsonschema:"enum=light|dark"`
+	}
+	schema, err := GenerateStrictSchema(paint{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(schema)
+	want := `{"type":"object","properties":{` +
+		`"color":{"type":["string","null"],"enum":["red","blue",null]},` +
+		`"shade":{"type":"string","enum":["light","dark"]}},` +
+		`"required":["color","shade"],"additionalProperties":false}`
+	if string(got) != want {
+		t.Errorf("GenerateStrictSchema() =\n%s\nwant\n%s", got, want)
+	}
+
+	tests := []struct {
+		doc   string
+		valid bool
+	}{
+		{`{"color":"red","shade":"dark"}`, true},
+		{`{"color":null,"shade":"dark"}`, true},
+		{`{"color":"green","shade":"dark"}`, false},
+		{`{"color":"red","shade":null}`, false},
+	}
+	for _, tt := range tests {
+		var v interface{}
+		json.Unmarshal([]byte(tt.doc), &v)
+		if err := schema.Validate(v); (err == nil) != tt.valid {
+			t.Errorf("Validate(%s) = %v, want valid %v", tt.doc, err, tt.valid)
+		}
+	}
+}
+
+func TestGenerateSchema_PointerEnum(t *testing.T) {
+	type order struct {
+		Status *string `json:"status" jsonschema:"enum=a|b"`
+	}
+	for _, strict := range []bool{false, true} {
+		schema, err := SchemaReflector{Strict: strict}.Reflect(order{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, _ := json.Marshal(schema.Properties["status"])
+		if want := `{"type":["string","null"],"enum":["a","b",null]}`; string(got) != want {
+			t.Errorf("strict %v: status = %s, want %s", strict, got, want)
+		}
+		var v interface{}
+		json.Unmarshal([]byte(`{"status":null}`), &v)
+		if err = schema.Validate(v); err != nil {
+			t.Errorf("strict %v: Validate(null status) = %v", strict, err)
+		}
+	}
+}
+
+func TestGenerateSchema_DefNames(t *testing.T) {
+	type list struct {
+		Next *list `json:"next"`
+	}
+	type pair struct {
+		A schemaNode `json:"a"`
+		B list       `json:"b"`
+		C struct {
+			D *schemaNode `json:"d"`
+		} `json:"c"`
+	}
+	schema, err := GenerateSchema(pair{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schema.Defs) != 2 {
+		t.Fatalf("$defs = %v, want schemaNode and list", schema.Defs)
+	}
+	for name := range schema.Defs {
+		if !strings.HasPrefix(name, "github.com.EthanCampana.go-openai_test.") {
+			t.Errorf("$defs key %s is not qualified by the package path", name)
+		}
+	}
+
+	// Types declared in different functions share package path and name.
+	first := reflect.TypeOf(func() interface{} {
+		type node struct {
+			Next *node `json:"next"`
+		}
+		return node{}
+	}())
+	second := reflect.TypeOf(func() interface{} {
+		type node struct {
+			Prev *node `json:"prev"`
+		}
+		return node{}
+	}())
+	both := reflect.StructOf([]reflect.StructField{
+		{Name: "First", Type: first, Tag: `json:"first"`},
+		{Name: "Second", Type: second, Tag: `json:"second"`},
+	})
+	schema, err = GenerateSchema(reflect.New(both).Elem().Interface())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schema.Defs) != 2 {
+		t.Errorf("$defs = %v, want one entry per local type", schema.Defs)
+	}
+}
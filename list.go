@@ -0,0 +1,173 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/url"
+	"strconv"
+)
+
+const (
+	ListOrderAsc  = "asc"
+	ListOrderDesc = "desc"
+)
+
+// A page of objects returned by the cursor based list endpoints.
+type List[T any] struct {
+	Object  string `json:"object"`
+	Data    []T    `json:"data"`
+	FirstID string `json:"first_id,omitempty"`
+	LastID  string `json:"last_id,omitempty"`
+	HasMore bool   `json:"has_more"`
+}
+
+// Pagination parameters of the list endpoints. Zero values are left to the API defaults.
+type ListParams struct {
+	// Number of objects per page, between 1 and 100.
+	Limit int
+	// ListOrderAsc or ListOrderDesc by creation time.
+	Order string
+	// Cursors: list the objects after or before the object with the given id.
+	After  string
+	Before string
+}
+
+// Returns path with the parameters appended as query string. p may be nil.
+func (p *ListParams) encode(path string) string {
+	return p.encodeWith(path, url.Values{})
+}
+
+// Same as encode but adds the parameters to the endpoint specific query q.
+func (p *ListParams) encodeWith(path string, q url.Values) string {
+	if p != nil {
+		if p.Limit > 0 {
+			q.Set("limit", strconv.Itoa(p.Limit))
+		}
+		if p.Order != "" {
+			q.Set("order", p.Order)
+		}
+		if p.After != "" {
+			q.Set("after", p.After)
+		}
+		if p.Before != "" {
+			q.Set("before", p.Before)
+		}
+	}
+	if len(q) == 0 {
+		return path
+	}
+	return path + "?" + q.Encode()
+}
+
+// Pager lazily fetches the pages of a cursor based list endpoint. Pages are walked forward
+// from ListParams.After, or backwards when only ListParams.Before is set. ListParams.Limit
+// sets the page size and ListParams.Order the order of the objects.
+//
+//	p := openai.NewPager(ctx, &openai.ListParams{Limit: 100}, c.ListFiles)
+//	for p.Next() {
+//		process(p.Page().Data)
+//	}
+//	if err := p.Err(); err != nil {
+//		...
+//	}
+type Pager[T any] struct {
+	ctx      context.Context
+	fetch    func(context.Context, *ListParams) (List[T], error)
+	params   ListParams
+	page     List[T]
+	started  bool
+	lastPage bool
+	err      error
+}
+
+// Creates a Pager that fetches the pages with fetch, starting at params. params may be nil.
+func NewPager[T any](ctx context.Context, params *ListParams, fetch func(context.Context, *ListParams) (List[T], error)) *Pager[T] {
+	p := &Pager[T]{ctx: ctx, fetch: fetch}
+	if params != nil {
+		p.params = *params
+	}
+	return p
+}
+
+// Fetches the next page. Returns false once all pages were fetched, or fetching failed
+// or the context was cancelled, see Err.
+func (p *Pager[T]) Next() bool {
+	if p.lastPage || p.err != nil {
+		return false
+	}
+	if err := p.ctx.Err(); err != nil {
+		p.err = err
+		return false
+	}
+	if p.started {
+		if p.backward() {
+			p.params.Before = p.page.FirstID
+		} else {
+			p.params.After = p.page.LastID
+		}
+	}
+	page, err := p.fetch(p.ctx, &p.params)
+	if err != nil {
+		p.err = err
+		return false
+	}
+	p.started = true
+	p.page = page
+	p.lastPage = !page.HasMore || len(page.Data) == 0
+	if !p.lastPage {
+		// An empty cursor would fetch the first page again and loop forever.
+		if (p.backward() && page.FirstID == "") || (!p.backward() && page.LastID == "") {
+			p.err = errors.New("list page has more objects but no cursor")
+			return false
+		}
+	}
+	return true
+}
+
+// Reports whether the pages are walked backwards, from ListParams.Before.
+func (p *Pager[T]) backward() bool {
+	return p.params.Before != "" && p.params.After == ""
+}
+
+// Returns the page fetched by the last call to Next.
+func (p *Pager[T]) Page() List[T] {
+	return p.page
+}
+
+// Returns the error that stopped Next, if any.
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// Iterates over the objects of the remaining pages. An error is yielded last, with the
+// zero value of T.
+func (p *Pager[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for p.Next() {
+			for _, v := range p.page.Data {
+				if !yield(v, nil) {
+					return
+				}
+			}
+		}
+		if p.err != nil {
+			var zero T
+			yield(zero, p.err)
+		}
+	}
+}
+
+// Returns an iterator over every object of a list endpoint. Every iteration starts a new Pager.
+func paginate[T any](ctx context.Context, params *ListParams, fetch func(context.Context, *ListParams) (List[T], error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		NewPager(ctx, params, fetch).All()(yield)
+	}
+}
+
+// Returned by the delete endpoints.
+type DeletionStatus struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Deleted bool   `json:"deleted"`
+}
@@ -0,0 +1,182 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	. "github.com/EthanCampana/go-openai"
+)
+
+// Serves the files file-1 to file-5 in pages honoring limit, order, after and before.
+func newPagedFilesClient(t *testing.T, requests *[]string) *Client {
+	ids := []string{"file-1", "file-2", "file-3", "file-4", "file-5"}
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.URL.RawQuery)
+		q := r.URL.Query()
+		list := append([]string(nil), ids...)
+		if q.Get("order") == ListOrderDesc {
+			for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+				list[i], list[j] = list[j], list[i]
+			}
+		}
+		start, end := 0, len(list)
+		for i, id := range list {
+			if id == q.Get("after") {
+				start = i + 1
+			}
+			if id == q.Get("before") {
+				end = i
+			}
+		}
+		limit := 2
+		fmt.Sscan(q.Get("limit"), &limit)
+		if q.Get("before") != "" && q.Get("after") == "" && end-start > limit {
+			start = end - limit
+		}
+		page := list[start:end]
+		hasMore := len(page) > limit
+		if hasMore {
+			page = page[:limit]
+		}
+		var data []string
+		for _, id := range page {
+			data = append(data, fmt.Sprintf(`{"id":%q}`, id))
+		}
+		first, last := "", ""
+		if len(page) > 0 {
+			first, last = page[0], page[len(page)-1]
+		}
+		if q.Get("before") != "" && q.Get("after") == "" {
+			hasMore = start > 0
+		}
+		fmt.Fprintf(w, `{"object":"list","data":[%s],"first_id":%q,"last_id":%q,"has_more":%v}`,
+			strings.Join(data, ","), first, last, hasMore)
+	})
+}
+
+func TestClient_Files_Pagination(t *testing.T) {
+	tests := []struct {
+		name         string
+		params       *ListParams
+		want         string
+		wantRequests []string
+	}{
+		{"defaults", nil, "file-1,file-2,file-3,file-4,file-5",
+			[]string{"", "after=file-2", "after=file-4"}},
+		{"limit and order", &ListParams{Limit: 3, Order: ListOrderDesc}, "file-5,file-4,file-3,file-2,file-1",
+			[]string{"limit=3&order=desc", "after=file-3&limit=3&order=desc"}},
+		{"after", &ListParams{After: "file-3"}, "file-4,file-5",
+			[]string{"after=file-3"}},
+		{"before", &ListParams{Before: "file-5"}, "file-3,file-4,file-1,file-2",
+			[]string{"before=file-5", "before=file-3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			client := newPagedFilesClient(t, &requests)
+			var ids []string
+			for file, err := range client.Files(context.Background(), tt.params) {
+				if err != nil {
+					t.Fatal(err)
+				}
+				ids = append(ids, file.ID)
+			}
+			if got := strings.Join(ids, ","); got != tt.want {
+				t.Errorf("files = %s, want %s", got, tt.want)
+			}
+			if strings.Join(requests, " ") != strings.Join(tt.wantRequests, " ") {
+				t.Errorf("requests = %q, want %q", requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestPager(t *testing.T) {
+	var requests []string
+	client := newPagedFilesClient(t, &requests)
+
+	p := NewPager(context.Background(), &ListParams{Limit: 2}, client.ListFiles)
+	var pages []int
+	for p.Next() {
+		pages = append(pages, len(p.Page().Data))
+	}
+	if p.Err() != nil || fmt.Sprint(pages) != "[2 2 1]" || p.Next() {
+		t.Errorf("pages = %v, err = %v", pages, p.Err())
+	}
+
+	requests = nil
+	files := client.Files(context.Background(), nil)
+	for range files {
+		break
+	}
+	for range files {
+		break
+	}
+	if len(requests) != 2 || requests[1] != "" {
+		t.Errorf("requests = %q, want every iteration to start over with one page", requests)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p = NewPager(ctx, nil, client.ListFiles)
+	p.Next()
+	cancel()
+	if p.Next() || !errors.Is(p.Err(), context.Canceled) {
+		t.Errorf("Next() after cancel, err = %v", p.Err())
+	}
+
+	noCursor := func(ctx context.Context, params *ListParams) (List[File], error) {
+		return List[File]{Data: []File{{ID: "file-1"}}, HasMore: true}, nil
+	}
+	var errs []error
+	for _, err := range NewPager(context.Background(), nil, noCursor).All() {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) != 1 {
+		t.Errorf("errors = %v, want a missing cursor error", errs)
+	}
+
+	var fetches int
+	firstIDOnly := func(ctx context.Context, params *ListParams) (List[File], error) {
+		fetches++
+		return List[File]{Data: []File{{ID: "file-1"}}, FirstID: "file-1", HasMore: true}, nil
+	}
+	p = NewPager(context.Background(), nil, firstIDOnly)
+	if p.Next() || p.Err() == nil || fetches != 1 {
+		t.Errorf("forward without last_id: fetches = %d, err = %v, want a missing cursor error", fetches, p.Err())
+	}
+
+	fetches = 0
+	lastIDOnly := func(ctx context.Context, params *ListParams) (List[File], error) {
+		fetches++
+		return List[File]{Data: []File{{ID: "file-9"}}, LastID: "file-9", HasMore: true}, nil
+	}
+	p = NewPager(context.Background(), &ListParams{Before: "file-10"}, lastIDOnly)
+	if p.Next() || p.Err() == nil || fetches != 1 {
+		t.Errorf("backward without first_id: fetches = %d, err = %v, want a missing cursor error", fetches, p.Err())
+	}
+}
+
+func TestClient_Models(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" || r.URL.RawQuery != "" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		fmt.Fprint(w, `{"object":"list","data":[{"id":"gpt-4o"},{"id":"dall-e-3"}]}`)
+	})
+	var ids []string
+	for m, err := range client.Models(context.Background()) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, m.ID)
+	}
+	if strings.Join(ids, ",") != "gpt-4o,dall-e-3" {
+		t.Errorf("Models() = %v", ids)
+	}
+}
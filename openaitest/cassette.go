@@ -0,0 +1,357 @@
+package openaitest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Modes of a Recorder.
+type Mode int
+
+const (
+	// Replays the cassette and fails requests that were not recorded.
+	ModeReplay Mode = iota
+	// Sends every request and records the interactions, replacing the cassette on Save.
+	ModeRecord
+	// Replays the cassette if the file exists, records it otherwise.
+	ModeAuto
+)
+
+// Value that replaces redacted headers and secrets.
+const Redacted = "REDACTED"
+
+// Headers redacted by default. The values of the request headers are also scrubbed from
+// the recorded URLs and bodies, e.g. the organization id.
+var DefaultRedactedHeaders = []string{"Authorization", "OpenAI-Organization", "OpenAI-Project", "Set-Cookie"}
+
+// The recorded interactions, stored as JSON.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body"`
+}
+
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body"`
+}
+
+// Body is stored as text when it is valid UTF-8 and as base64 otherwise.
+type Body []byte
+
+type bodyJSON struct {
+	Text   string `json:"text,omitempty"`
+	Base64 string `json:"base64,omitempty"`
+}
+
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(bodyJSON{Text: string(b)})
+	}
+	return json.Marshal(bodyJSON{Base64: base64.StdEncoding.EncodeToString(b)})
+}
+
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var v bodyJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Base64 == "" {
+		*b = Body(v.Text)
+		return nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(v.Base64)
+	*b = raw
+	return err
+}
+
+// Recorder is an http.RoundTripper that records interactions with the API to a cassette
+// file and replays them. Recorded requests are matched on method, path, query and
+// normalized body: JSON bodies regardless of formatting and key order, multipart bodies
+// regardless of the boundary. Identical requests are replayed in the order they were
+// recorded. Protocol upgrades, e.g. the realtime API, are not supported.
+//
+//	rec, err := openaitest.NewRecorder("testdata/chat.json", openaitest.ModeAuto)
+//	defer rec.Save()
+//	c := openai.GetClient(os.Getenv("OPENAI_API_KEY")).SetHTTPClient(rec.HTTPClient())
+type Recorder struct {
+	path string
+	mode Mode
+	// Transport sends the requests in record mode, http.DefaultTransport when nil.
+	Transport http.RoundTripper
+	// Headers redacted in the cassette, DefaultRedactedHeaders unless changed before use.
+	RedactHeaders []string
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// Creates a Recorder for the cassette at path. In ModeReplay, and ModeAuto when the
+// file exists, the cassette is loaded.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode, RedactHeaders: DefaultRedactedHeaders}
+	if mode == ModeAuto {
+		r.mode = ModeReplay
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			r.mode = ModeRecord
+		}
+	}
+	if r.mode == ModeRecord {
+		return r, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &r.cassette); err != nil {
+		return nil, fmt.Errorf("reading cassette %s: %w", path, err)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// Reports whether the recorder sends requests rather than replaying them.
+func (r *Recorder) Recording() bool {
+	return r.mode == ModeRecord
+}
+
+// Returns an http.Client using the recorder, to be passed to Client.SetHTTPClient.
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Writes the recorded interactions to the cassette file. Does nothing when replaying.
+func (r *Recorder) Save() error {
+	if !r.Recording() {
+		return nil
+	}
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if r.Recording() {
+		return r.record(req, body)
+	}
+	return r.replay(req, body)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+	res, err := transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusSwitchingProtocols {
+		res.Body.Close()
+		return nil, errors.New("cassettes cannot record protocol upgrades")
+	}
+	resBody, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	secrets := r.secrets(req.Header)
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    scrub(req.URL.String(), secrets),
+			Header: r.redact(req.Header),
+			Body:   Body(scrub(string(body), secrets)),
+		},
+		Response: RecordedResponse{
+			StatusCode: res.StatusCode,
+			Header:     r.redact(res.Header),
+			Body:       Body(scrub(string(resBody), secrets)),
+		},
+	}
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+	return res, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	key, err := matchKey(req.Method, req.URL.String(), req.Header.Get("Content-Type"), body, r.secrets(req.Header))
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.cassette.Interactions {
+		if r.used[i] {
+			continue
+		}
+		recorded, err := matchKey(in.Request.Method, in.Request.URL, in.Request.Header.Get("Content-Type"), in.Request.Body, nil)
+		if err != nil || recorded != key {
+			continue
+		}
+		r.used[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("cassette %s has no unused interaction for %s %s", r.path, req.Method, req.URL.Path)
+}
+
+func (r *Recorder) isRedacted(name string) bool {
+	for _, h := range r.RedactHeaders {
+		if strings.EqualFold(h, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Recorder) redact(h http.Header) http.Header {
+	out := h.Clone()
+	for name := range out {
+		if r.isRedacted(name) {
+			out[name] = []string{Redacted}
+		}
+	}
+	return out
+}
+
+// Returns the values of the redacted request headers, without the auth scheme.
+func (r *Recorder) secrets(h http.Header) []string {
+	var secrets []string
+	for name, values := range h {
+		if !r.isRedacted(name) {
+			continue
+		}
+		for _, v := range values {
+			if _, token, ok := strings.Cut(v, " "); ok && strings.EqualFold(name, "Authorization") {
+				v = token
+			}
+			if v != "" {
+				secrets = append(secrets, v)
+			}
+		}
+	}
+	return secrets
+}
+
+func scrub(s string, secrets []string) string {
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, Redacted)
+	}
+	return s
+}
+
+// Returns the key requests are matched on: method, path with sorted query, and the
+// normalized body. Secrets are scrubbed as they were when recording.
+func matchKey(method, rawURL, contentType string, body []byte, secrets []string) (string, error) {
+	req, err := http.NewRequest(method, scrub(rawURL, secrets), nil)
+	if err != nil {
+		return "", err
+	}
+	normalized, err := normalizeBody(contentType, []byte(scrub(string(body), secrets)))
+	if err != nil {
+		return "", err
+	}
+	return method + " " + req.URL.Path + "?" + req.URL.Query().Encode() + "\n" + normalized, nil
+}
+
+func normalizeBody(contentType string, body []byte) (string, error) {
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	switch {
+	case len(body) == 0:
+		return "", nil
+	case mediaType == "multipart/form-data":
+		return normalizeMultipart(body, params["boundary"])
+	case json.Valid(body):
+		var v interface{}
+		json.Unmarshal(body, &v)
+		b, err := json.Marshal(v)
+		return string(b), err
+	}
+	return string(body), nil
+}
+
+// Lists the parts sorted by field name, files by their name and content hash.
+func normalizeMultipart(body []byte, boundary string) (string, error) {
+	mr := multipart.NewReader(bytes.NewReader(body), boundary)
+	var parts []string
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("reading multipart body: %w", err)
+		}
+		data, err := io.ReadAll(p)
+		if err != nil {
+			return "", err
+		}
+		if p.FileName() != "" {
+			sum := sha256.Sum256(data)
+			parts = append(parts, fmt.Sprintf("%s: file %s sha256:%s", p.FormName(), p.FileName(), hex.EncodeToString(sum[:])))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s: %s", p.FormName(), data))
+		}
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "\n"), nil
+}
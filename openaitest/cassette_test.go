@@ -0,0 +1,108 @@
+package openaitest_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	openai "github.com/EthanCampana/go-openai"
+	"github.com/EthanCampana/go-openai/openaitest"
+)
+
+func TestRecorder(t *testing.T) {
+	dir := t.TempDir()
+	cassette := filepath.Join(dir, "testdata", "cassette.json")
+	imagePath, maskPath := filepath.Join(dir, "image.png"), filepath.Join(dir, "mask.png")
+	os.WriteFile(imagePath, openaitest.PNG, 0o644)
+	os.WriteFile(maskPath, openaitest.PNG, 0o644)
+	ctx := context.Background()
+	chatReq := &openai.ChatCompletionRequest{
+		Model:    "gpt-4o",
+		Messages: []openai.ChatMessage{{Role: openai.ChatMessageRoleUser, Content: "Hi"}},
+	}
+	editReq := &openai.ImageEditRequest{
+		Image: "image.png", ImagePath: imagePath, Mask: "mask.png", MaskPath: maskPath,
+		Prompt: "add a hat", ResponseFormat: "b64_json",
+	}
+
+	// Record against the fake server, which stands in for the API.
+	srv := openaitest.NewServer()
+	srv.Handle("GET /v1/models/{id}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id":%q,"owned_by":%q}`, r.PathValue("id"), r.Header.Get("OpenAI-Organization"))
+	})
+	rec, err := openaitest.NewRecorder(cassette, openaitest.ModeAuto)
+	if err != nil || !rec.Recording() {
+		t.Fatalf("NewRecorder() = %v, recording %v", err, rec.Recording())
+	}
+	client := openai.GetOrgClient("sk-secret", "org-secret").SetBaseURL(srv.URL()).SetHTTPClient(rec.HTTPClient())
+	chatRes, err := client.CreateChatCompletion(ctx, chatReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.CreateImageEidt(ctx, editReq); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.GetModel(ctx, "gpt-4o"); err != nil {
+		t.Fatal(err)
+	}
+	if err = rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+
+	data, err := os.ReadFile(cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "sk-secret") || strings.Contains(string(data), "org-secret") {
+		t.Errorf("cassette contains secrets:\n%s", data)
+	}
+
+	// Replay without a server, with another key and a different multipart boundary.
+	rec, err = openaitest.NewRecorder(cassette, openaitest.ModeAuto)
+	if err != nil || rec.Recording() {
+		t.Fatalf("NewRecorder() = %v, recording %v", err, rec.Recording())
+	}
+	client = openai.GetClient("sk-other").SetBaseURL(srv.URL()).SetHTTPClient(rec.HTTPClient())
+
+	// Same request with other formatting and key order.
+	body := `{"messages": [{"role": "user", "content": "Hi"}], "model": "gpt-4o"}`
+	raw, _ := http.NewRequest(http.MethodPost, srv.URL()+"/chat/completions", strings.NewReader(body))
+	raw.Header.Set("Content-Type", "application/json")
+	res, err := rec.HTTPClient().Do(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var replayed openai.ChatCompletionResponse
+	json.NewDecoder(res.Body).Decode(&replayed)
+	res.Body.Close()
+	if replayed.ID != chatRes.ID || replayed.Choices[0].Message.Content != chatRes.Choices[0].Message.Content {
+		t.Errorf("replayed chat = %+v, want %+v", replayed, chatRes)
+	}
+
+	images, err := client.CreateImageEidt(ctx, editReq)
+	if err != nil || len(images.Data) != 1 || images.Data[0].B64JSON == "" {
+		t.Errorf("replayed image edit = %+v, %v", images, err)
+	}
+	model, err := client.GetModel(ctx, "gpt-4o")
+	if err != nil || model.OwnedBy != openaitest.Redacted {
+		t.Errorf("replayed model = %+v, %v", model, err)
+	}
+
+	if _, err = client.GetModel(ctx, "gpt-4o"); err == nil {
+		t.Error("replaying an interaction twice succeeded")
+	}
+	editReq.Prompt = "add a scarf"
+	if _, err = client.CreateImageEidt(ctx, editReq); err == nil {
+		t.Error("replaying an edit with another prompt succeeded")
+	}
+
+	if _, err = openaitest.NewRecorder(filepath.Join(dir, "missing.json"), openaitest.ModeReplay); err == nil {
+		t.Error("NewRecorder() in replay mode without cassette succeeded")
+	}
+}
@@ -0,0 +1,194 @@
+package openaitest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	openai "github.com/EthanCampana/go-openai"
+)
+
+// Returns a chat completion that answers with content.
+func ChatResponse(content string) openai.ChatCompletionResponse {
+	return openai.ChatCompletionResponse{
+		Choices: []openai.ChatCompletionChoice{{
+			Message:      openai.ChatMessage{Role: openai.ChatMessageRoleAssistant, Content: content},
+			FinishReason: openai.FinishReasonStop,
+		}},
+	}
+}
+
+// Returns a chat completion that calls the tools. Calls without ID get call_1, call_2, ...
+func ToolCallResponse(calls ...openai.ToolCall) openai.ChatCompletionResponse {
+	for i := range calls {
+		if calls[i].ID == "" {
+			calls[i].ID = fmt.Sprintf("call_%d", i+1)
+		}
+		if calls[i].Type == "" {
+			calls[i].Type = "function"
+		}
+	}
+	return openai.ChatCompletionResponse{
+		Choices: []openai.ChatCompletionChoice{{
+			Message:      openai.ChatMessage{Role: openai.ChatMessageRoleAssistant, ToolCalls: calls},
+			FinishReason: openai.FinishReasonToolCalls,
+		}},
+	}
+}
+
+func (s *Server) createChatCompletion(w http.ResponseWriter, r *http.Request) {
+	var req openai.ChatCompletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		invalidRequest(w, "", "We could not parse the JSON body of your request.")
+		return
+	}
+	if req.Model == "" {
+		invalidRequest(w, "model", "you must provide a model parameter")
+		return
+	}
+	if !s.hasModel(req.Model) {
+		writeError(w, &openai.APIError{StatusCode: http.StatusNotFound, Type: "invalid_request_error", Code: "model_not_found",
+			Message: fmt.Sprintf("The model `%s` does not exist or you do not have access to it.", req.Model)})
+		return
+	}
+	if len(req.Messages) == 0 {
+		invalidRequest(w, "messages", "[] is too short - 'messages'")
+		return
+	}
+
+	s.mu.Lock()
+	var res openai.ChatCompletionResponse
+	var err error
+	handler := s.chatHandler
+	queued := len(s.chat) > 0
+	if queued {
+		res, s.chat = s.chat[0], s.chat[1:]
+	}
+	s.mu.Unlock()
+	switch {
+	case queued:
+	case handler != nil:
+		res, err = handler(req)
+	default:
+		res = ChatResponse(echo(req.Messages))
+	}
+	if err != nil {
+		var apiErr *openai.APIError
+		if !errors.As(err, &apiErr) {
+			apiErr = &openai.APIError{StatusCode: http.StatusInternalServerError, Type: "server_error", Message: err.Error()}
+		}
+		writeError(w, apiErr)
+		return
+	}
+	s.completeChatResponse(&req, &res)
+	if req.Stream {
+		writeChatStream(w, &req, res)
+		return
+	}
+	writeJSON(w, res)
+}
+
+// Answers with the content of the last user message.
+func echo(messages []openai.ChatMessage) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role != openai.ChatMessageRoleUser {
+			continue
+		}
+		content := messages[i].Content
+		for _, part := range messages[i].MultiContent {
+			if part.Type == openai.ChatMessagePartTypeText {
+				content += part.Text
+			}
+		}
+		return "You said: " + content
+	}
+	return "Hello!"
+}
+
+// Fills in the fields scripted responses usually leave empty.
+func (s *Server) completeChatResponse(req *openai.ChatCompletionRequest, res *openai.ChatCompletionResponse) {
+	if res.ID == "" {
+		res.ID = s.nextID("chatcmpl")
+	}
+	res.Object = "chat.completion"
+	if res.Created == 0 {
+		res.Created = Created
+	}
+	if res.Model == "" {
+		res.Model = req.Model
+	}
+	for i := range res.Choices {
+		res.Choices[i].Index = i
+	}
+	if res.Usage.TotalTokens == 0 {
+		for _, m := range req.Messages {
+			res.Usage.PromptTokens += countTokens(m.Content)
+		}
+		for _, c := range res.Choices {
+			res.Usage.CompletionTokens += countTokens(c.Message.Content)
+			for _, call := range c.Message.ToolCalls {
+				res.Usage.CompletionTokens += countTokens(call.Function.Name + " " + call.Function.Arguments)
+			}
+		}
+		res.Usage.TotalTokens = res.Usage.PromptTokens + res.Usage.CompletionTokens
+	}
+}
+
+// A deterministic stand-in for tokenization: one token per word.
+func countTokens(s string) int {
+	return len(strings.Fields(s))
+}
+
+// Streams the response as chunks: the role, the content word by word, each tool call,
+// the finish reason and, when requested, the usage.
+func writeChatStream(w http.ResponseWriter, req *openai.ChatCompletionRequest, res openai.ChatCompletionResponse) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	chunk := func(choices []openai.ChatCompletionStreamChoice, usage *openai.Usage) {
+		b, _ := json.Marshal(openai.ChatCompletionStreamResponse{
+			ID:      res.ID,
+			Object:  "chat.completion.chunk",
+			Created: res.Created,
+			Model:   res.Model,
+			Choices: choices,
+			Usage:   usage,
+		})
+		fmt.Fprintf(w, "data: %s\n\n", b)
+	}
+	for _, c := range res.Choices {
+		delta := func(d openai.ChatCompletionStreamChoiceDelta) {
+			chunk([]openai.ChatCompletionStreamChoice{{Index: c.Index, Delta: d}}, nil)
+		}
+		delta(openai.ChatCompletionStreamChoiceDelta{Role: c.Message.Role})
+		for _, piece := range splitWords(c.Message.Content) {
+			delta(openai.ChatCompletionStreamChoiceDelta{Content: piece})
+		}
+		for i, call := range c.Message.ToolCalls {
+			index := i
+			call.Index = &index
+			delta(openai.ChatCompletionStreamChoiceDelta{ToolCalls: []openai.ToolCall{call}})
+		}
+		chunk([]openai.ChatCompletionStreamChoice{{Index: c.Index, FinishReason: c.FinishReason}}, nil)
+	}
+	if req.StreamOptions != nil && req.StreamOptions.IncludeUsage {
+		usage := res.Usage
+		chunk([]openai.ChatCompletionStreamChoice{}, &usage)
+	}
+	fmt.Fprint(w, "data: [DONE]\n\n")
+}
+
+// Splits s after every space, so that the pieces join back into s.
+func splitWords(s string) []string {
+	var pieces []string
+	for s != "" {
+		i := strings.IndexByte(s, ' ')
+		if i < 0 {
+			pieces = append(pieces, s)
+			break
+		}
+		pieces = append(pieces, s[:i+1])
+		s = s[i+1:]
+	}
+	return pieces
+}
@@ -0,0 +1,151 @@
+package openaitest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"strconv"
+	"strings"
+
+	openai "github.com/EthanCampana/go-openai"
+)
+
+// The image every image endpoint returns: a 1x1 gray PNG.
+var PNG = func() []byte {
+	img := image.NewGray(image.Rect(0, 0, 1, 1))
+	img.SetGray(0, 0, color.Gray{Y: 0x80})
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return buf.Bytes()
+}()
+
+// Parameters shared by the image endpoints.
+type imageParams struct {
+	model          string
+	prompt         string
+	n              int
+	size           string
+	responseFormat string
+}
+
+func (s *Server) createImage(w http.ResponseWriter, r *http.Request) {
+	var req openai.ImageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		invalidRequest(w, "", "We could not parse the JSON body of your request.")
+		return
+	}
+	if req.Prompt == "" {
+		invalidRequest(w, "prompt", "Missing required parameter: 'prompt'.")
+		return
+	}
+	s.writeImages(w, imageParams{
+		model:          req.Model,
+		prompt:         req.Prompt,
+		n:              int(req.Num),
+		size:           req.Size,
+		responseFormat: req.ResponseFormat,
+	}, nil)
+}
+
+func (s *Server) createImageEdit(w http.ResponseWriter, r *http.Request) {
+	params, ok := imageFormParams(w, r)
+	if !ok {
+		return
+	}
+	if params.prompt == "" {
+		invalidRequest(w, "prompt", "Missing required parameter: 'prompt'.")
+		return
+	}
+	s.writeImages(w, params, func(spec openai.ImageModelSpec) bool { return spec.SupportsEdit })
+}
+
+func (s *Server) createImageVariation(w http.ResponseWriter, r *http.Request) {
+	params, ok := imageFormParams(w, r)
+	if !ok {
+		return
+	}
+	s.writeImages(w, params, func(spec openai.ImageModelSpec) bool { return spec.SupportsVary })
+}
+
+// Reads the parameters of a multipart image request, which requires an image file.
+func imageFormParams(w http.ResponseWriter, r *http.Request) (imageParams, bool) {
+	if _, _, err := r.FormFile("image"); err != nil {
+		invalidRequest(w, "image", "Missing required parameter: 'image'.")
+		return imageParams{}, false
+	}
+	params := imageParams{
+		model:          r.FormValue("model"),
+		prompt:         r.FormValue("prompt"),
+		size:           r.FormValue("size"),
+		responseFormat: r.FormValue("response_format"),
+	}
+	if n := r.FormValue("n"); n != "" {
+		var err error
+		if params.n, err = strconv.Atoi(n); err != nil {
+			invalidRequest(w, "n", fmt.Sprintf("Invalid value for 'n': %q is not an integer.", n))
+			return params, false
+		}
+	}
+	return params, true
+}
+
+// Validates the parameters against the model spec and writes n images. supports tells
+// whether the model supports the endpoint, nil for generations.
+func (s *Server) writeImages(w http.ResponseWriter, p imageParams, supports func(openai.ImageModelSpec) bool) {
+	if p.model == "" {
+		p.model = openai.DefaultImageModel
+	}
+	spec, err := openai.GetImageModelSpec(p.model)
+	if err != nil || !s.hasModel(p.model) {
+		invalidRequest(w, "model", fmt.Sprintf("Invalid value: '%s'. Value must be one of the image models.", p.model))
+		return
+	}
+	if supports != nil && !supports(spec) {
+		invalidRequest(w, "model", fmt.Sprintf("The model '%s' is not supported by this endpoint.", p.model))
+		return
+	}
+	if p.n == 0 {
+		p.n = 1
+	}
+	if p.n < 1 || p.n > int(spec.MaxNum) {
+		invalidRequest(w, "n", fmt.Sprintf("Invalid 'n': must be between 1 and %d for %s.", spec.MaxNum, p.model))
+		return
+	}
+	if p.size != "" && !contains(spec.Sizes, p.size) {
+		invalidRequest(w, "size", fmt.Sprintf("'%s' is not one of %s - 'size'", p.size, strings.Join(spec.Sizes, ", ")))
+		return
+	}
+	if p.responseFormat != "" && !contains(spec.ResponseFormats, p.responseFormat) {
+		invalidRequest(w, "response_format", fmt.Sprintf("Unknown parameter: 'response_format' for %s.", p.model))
+		return
+	}
+	res := openai.ImageResponse{Created: Created}
+	for i := 0; i < p.n; i++ {
+		var d openai.ImageData
+		if p.model == openai.ImageModelGPTImage1 || p.responseFormat == "b64_json" {
+			d.B64JSON = base64.StdEncoding.EncodeToString(PNG)
+		} else {
+			d.URL = fmt.Sprintf("%s/images/%s.png", s.srv.URL, s.nextID("img"))
+		}
+		if p.model == openai.ImageModelDallE3 {
+			d.RevisedPrompt = p.prompt
+		}
+		res.Data = append(res.Data, d)
+	}
+	writeJSON(w, res)
+}
+
+// Serves the images returned as URL.
+func (s *Server) getImageFile(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.PathValue("name"), "img-") {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Write(PNG)
+}
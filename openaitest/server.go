@@ -0,0 +1,358 @@
+// Package openaitest provides a fake OpenAI API server and a record/replay transport for tests.
+// The server implements the models, images and chat completions endpoints with deterministic
+// responses, which can be scripted, delayed or replaced by injected errors, and records every
+// request for assertions. See Recorder for cassettes of real interactions.
+//
+//	srv := openaitest.NewServer()
+//	defer srv.Close()
+//	srv.AddChatResponses(openaitest.ChatResponse("Hello!"))
+//	res, err := srv.Client().CreateChatCompletion(ctx, req)
+package openaitest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	openai "github.com/EthanCampana/go-openai"
+)
+
+// Token of the clients returned by Server.Client. Requests without a bearer token are rejected.
+const Token = "test-token"
+
+// Created timestamp of every object returned by the server.
+const Created = 1700000000
+
+// A request received by the server. Form and Files hold the fields and files of multipart requests.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+	Form   map[string]string
+	Files  map[string]File
+}
+
+type File struct {
+	Filename string
+	Data     []byte
+}
+
+// Decodes the JSON body of the request into v.
+func (r Request) DecodeJSON(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// Server is a fake OpenAI API. Its methods may be called concurrently with requests.
+type Server struct {
+	srv *httptest.Server
+	mux *http.ServeMux
+
+	mu          sync.Mutex
+	custom      *http.ServeMux
+	models      []openai.Model
+	chat        []openai.ChatCompletionResponse
+	chatHandler func(openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error)
+	faults      map[string][]*openai.APIError
+	latency     time.Duration
+	requests    []Request
+	ids         int
+}
+
+// Starts a fake server. Close must be called once done with it.
+func NewServer() *Server {
+	s := &Server{
+		mux: http.NewServeMux(),
+		models: []openai.Model{
+			{ID: "gpt-4o", Object: "model", OwnedBy: "system"},
+			{ID: "gpt-4o-mini", Object: "model", OwnedBy: "system"},
+			{ID: openai.ImageModelDallE2, Object: "model", OwnedBy: "system"},
+			{ID: openai.ImageModelDallE3, Object: "model", OwnedBy: "system"},
+			{ID: openai.ImageModelGPTImage1, Object: "model", OwnedBy: "system"},
+		},
+		faults: map[string][]*openai.APIError{},
+		custom: http.NewServeMux(),
+	}
+	s.mux.HandleFunc("GET /v1/models", s.listModels)
+	s.mux.HandleFunc("GET /v1/models/{id}", s.getModel)
+	s.mux.HandleFunc("POST /v1/chat/completions", s.createChatCompletion)
+	s.mux.HandleFunc("POST /v1/images/generations", s.createImage)
+	s.mux.HandleFunc("POST /v1/images/edits", s.createImageEdit)
+	s.mux.HandleFunc("POST /v1/images/variations", s.createImageVariation)
+	s.mux.HandleFunc("GET /images/{name}", s.getImageFile)
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Shuts the server down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Returns the base URL of the API, to be passed to Client.SetBaseURL.
+func (s *Server) URL() string {
+	return s.srv.URL + "/v1"
+}
+
+// Returns a client that sends its requests to the server.
+func (s *Server) Client() *openai.Client {
+	return openai.GetClient(Token).SetBaseURL(s.URL()).SetHTTPClient(s.srv.Client())
+}
+
+// Replaces the models returned by the models endpoints.
+func (s *Server) SetModels(models ...openai.Model) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.models = models
+}
+
+// Queues responses for the next chat completion requests, they are returned in order.
+// Without queued responses the chat handler answers, see OnChat.
+func (s *Server) AddChatResponses(res ...openai.ChatCompletionResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chat = append(s.chat, res...)
+}
+
+// Sets the function that answers chat completion requests once the queued responses are
+// used up. A returned *openai.APIError is sent with its status code, other errors as 500.
+// By default the last user message is echoed back.
+func (s *Server) OnChat(fn func(openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chatHandler = fn
+}
+
+// Makes the next request to path, e.g. /v1/chat/completions, fail with the status code and
+// message. Calling FailNext repeatedly fails as many requests.
+func (s *Server) FailNext(path string, statusCode int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults[path] = append(s.faults[path], &openai.APIError{StatusCode: statusCode, Message: message, Type: errorType(statusCode)})
+}
+
+// Delays every response by d, or until the request is cancelled.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// Adds the handler of an endpoint, e.g. "POST /v1/embeddings", in the pattern syntax of
+// http.ServeMux. It takes precedence over the built-in endpoints. Requests to it are
+// recorded and subject to latency and injected errors like any other.
+func (s *Server) Handle(pattern string, h http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.custom.HandleFunc(pattern, h)
+}
+
+// Returns the requests received so far, optionally only those to the given paths.
+func (s *Server) Requests(paths ...string) []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var res []Request
+	for _, r := range s.requests {
+		if len(paths) == 0 || contains(paths, r.Path) {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+// Returns the last request to path.
+func (s *Server) LastRequest(path string) (Request, bool) {
+	reqs := s.Requests(path)
+	if len(reqs) == 0 {
+		return Request{}, false
+	}
+	return reqs[len(reqs)-1], true
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	rec, err := record(r)
+	if err != nil {
+		writeError(w, &openai.APIError{StatusCode: http.StatusBadRequest, Type: "invalid_request_error", Message: err.Error()})
+		return
+	}
+	s.mu.Lock()
+	s.requests = append(s.requests, rec)
+	latency := s.latency
+	var fault *openai.APIError
+	if faults := s.faults[r.URL.Path]; len(faults) > 0 {
+		fault, s.faults[r.URL.Path] = faults[0], faults[1:]
+	}
+	mux := s.mux
+	if _, pattern := s.custom.Handler(r); pattern != "" {
+		mux = s.custom
+	}
+	s.mu.Unlock()
+
+	if latency > 0 && !sleep(r.Context(), latency) {
+		return
+	}
+	if fault != nil {
+		writeError(w, fault)
+		return
+	}
+	if strings.HasPrefix(r.URL.Path, "/v1/") && !hasBearerToken(r) {
+		writeError(w, &openai.APIError{StatusCode: http.StatusUnauthorized, Type: "invalid_request_error", Code: "invalid_api_key",
+			Message: "You didn't provide an API key."})
+		return
+	}
+	if _, pattern := mux.Handler(r); pattern == "" {
+		writeError(w, &openai.APIError{StatusCode: http.StatusNotFound, Type: "invalid_request_error",
+			Message: fmt.Sprintf("Invalid URL (%s %s)", r.Method, r.URL.Path)})
+		return
+	}
+	mux.ServeHTTP(w, r)
+}
+
+func hasBearerToken(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && token != ""
+}
+
+// Reads the request body, and the form of multipart requests, and restores the body for the handler.
+func record(r *http.Request) (Request, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return Request{}, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	rec := Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query(), Header: r.Header.Clone(), Body: body}
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		return rec, nil
+	}
+	if err = r.ParseMultipartForm(32 << 20); err != nil {
+		return rec, err
+	}
+	rec.Form = map[string]string{}
+	for k, v := range r.MultipartForm.Value {
+		rec.Form[k] = v[0]
+	}
+	rec.Files = map[string]File{}
+	for k, headers := range r.MultipartForm.File {
+		f, err := headers[0].Open()
+		if err != nil {
+			return rec, err
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return rec, err
+		}
+		rec.Files[k] = File{Filename: headers[0].Filename, Data: data}
+	}
+	return rec, nil
+}
+
+func (s *Server) nextID(prefix string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ids++
+	return fmt.Sprintf("%s-%d", prefix, s.ids)
+}
+
+func (s *Server) listModels(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	models := openai.Models{Data: append([]openai.Model(nil), s.models...)}
+	s.mu.Unlock()
+	writeJSON(w, struct {
+		Object string `json:"object"`
+		openai.Models
+	}{Object: "list", Models: models})
+}
+
+func (s *Server) getModel(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, m := range s.models {
+		if m.ID == id {
+			writeJSON(w, m)
+			return
+		}
+	}
+	writeError(w, &openai.APIError{StatusCode: http.StatusNotFound, Type: "invalid_request_error", Code: "model_not_found",
+		Message: fmt.Sprintf("The model '%s' does not exist", id)})
+}
+
+func (s *Server) hasModel(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, m := range s.models {
+		if m.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, apiErr *openai.APIError) {
+	body := map[string]interface{}{
+		"message": apiErr.Message,
+		"type":    apiErr.Type,
+		"param":   nil,
+		"code":    nil,
+	}
+	if apiErr.Param != "" {
+		body["param"] = apiErr.Param
+	}
+	if apiErr.Code != "" {
+		body["code"] = apiErr.Code
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(apiErr.StatusCode)
+	json.NewEncoder(w).Encode(map[string]interface{}{"error": body})
+}
+
+func invalidRequest(w http.ResponseWriter, param, message string) {
+	writeError(w, &openai.APIError{StatusCode: http.StatusBadRequest, Type: "invalid_request_error", Param: param, Message: message})
+}
+
+func errorType(statusCode int) string {
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return "rate_limit_exceeded"
+	case statusCode >= http.StatusInternalServerError:
+		return "server_error"
+	default:
+		return "invalid_request_error"
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Waits for d and reports whether ctx was not done before.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
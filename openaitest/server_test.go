@@ -0,0 +1,213 @@
+package openaitest_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	openai "github.com/EthanCampana/go-openai"
+	"github.com/EthanCampana/go-openai/openaitest"
+)
+
+func newServer(t *testing.T) (*openaitest.Server, *openai.Client) {
+	srv := openaitest.NewServer()
+	t.Cleanup(srv.Close)
+	return srv, srv.Client()
+}
+
+func apiError(t *testing.T, err error) *openai.APIError {
+	t.Helper()
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error = %v, want an *openai.APIError", err)
+	}
+	return apiErr
+}
+
+func TestServer_Models(t *testing.T) {
+	srv, client := newServer(t)
+	ctx := context.Background()
+	models, err := client.ListModels(ctx)
+	if err != nil || len(models.Data) != 5 {
+		t.Fatalf("ListModels() = %+v, %v", models, err)
+	}
+	srv.SetModels(openai.Model{ID: "custom", Object: "model"})
+	if m, err := client.GetModel(ctx, "custom"); err != nil || m.ID != "custom" {
+		t.Errorf("GetModel() = %+v, %v", m, err)
+	}
+	_, err = client.GetModel(ctx, "gpt-4o")
+	if apiErr := apiError(t, err); apiErr.StatusCode != http.StatusNotFound || apiErr.Code != "model_not_found" {
+		t.Errorf("GetModel() error = %+v", apiErr)
+	}
+	_, err = openai.GetClient("").SetBaseURL(srv.URL()).ListModels(ctx)
+	if apiErr := apiError(t, err); apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("ListModels() without token error = %+v", apiErr)
+	}
+}
+
+func TestServer_Chat(t *testing.T) {
+	srv, client := newServer(t)
+	ctx := context.Background()
+	req := &openai.ChatCompletionRequest{
+		Model:    "gpt-4o-mini",
+		Messages: []openai.ChatMessage{{Role: openai.ChatMessageRoleUser, Content: "What is the weather?"}},
+	}
+
+	res, err := client.CreateChatCompletion(ctx, req)
+	if err != nil || res.Choices[0].Message.Content != "You said: What is the weather?" ||
+		res.ID != "chatcmpl-1" || res.Model != "gpt-4o-mini" || res.Usage.TotalTokens != 10 {
+		t.Fatalf("CreateChatCompletion() = %+v, %v", res, err)
+	}
+
+	srv.AddChatResponses(
+		openaitest.ToolCallResponse(openai.ToolCall{Function: openai.FunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`}}),
+		openaitest.ChatResponse("It is sunny in Paris."),
+	)
+	res, err = client.CreateChatCompletion(ctx, req)
+	if err != nil || res.Choices[0].FinishReason != openai.FinishReasonToolCalls || res.Choices[0].Message.ToolCalls[0].ID != "call_1" {
+		t.Fatalf("CreateChatCompletion() = %+v, %v", res, err)
+	}
+	stream, err := client.CreateChatCompletionStream(ctx, &openai.ChatCompletionRequest{
+		Model:         req.Model,
+		Messages:      req.Messages,
+		StreamOptions: &openai.StreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+	res, err = stream.Collect()
+	if err != nil || res.Choices[0].Message.Content != "It is sunny in Paris." || res.Usage.CompletionTokens != 5 {
+		t.Fatalf("stream.Collect() = %+v, %v", res, err)
+	}
+
+	srv.OnChat(func(r openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+		return openai.ChatCompletionResponse{}, &openai.APIError{StatusCode: http.StatusTooManyRequests, Message: "slow down"}
+	})
+	_, err = client.CreateChatCompletion(ctx, req)
+	if apiErr := apiError(t, err); apiErr.StatusCode != http.StatusTooManyRequests || !apiErr.Temporary() {
+		t.Errorf("CreateChatCompletion() error = %+v", apiErr)
+	}
+
+	_, err = client.CreateChatCompletion(ctx, &openai.ChatCompletionRequest{Model: "gpt-4o"})
+	if apiErr := apiError(t, err); apiErr.Param != "messages" {
+		t.Errorf("CreateChatCompletion() without messages error = %+v", apiErr)
+	}
+
+	var sent openai.ChatCompletionRequest
+	last, ok := srv.LastRequest("/v1/chat/completions")
+	if !ok || last.DecodeJSON(&sent) != nil || sent.Model != "gpt-4o" || last.Header.Get("Authorization") != "Bearer "+openaitest.Token {
+		t.Errorf("LastRequest() = %+v", last)
+	}
+	if n := len(srv.Requests("/v1/chat/completions")); n != 5 {
+		t.Errorf("recorded %d chat requests, want 5", n)
+	}
+}
+
+func TestServer_Images(t *testing.T) {
+	srv, client := newServer(t)
+	ctx := context.Background()
+
+	res, err := client.CreateImage(ctx, &openai.ImageRequest{Model: openai.ImageModelDallE3, Prompt: "a cat", Size: openai.LARGE})
+	if err != nil || len(res.Data) != 1 || res.Data[0].RevisedPrompt != "a cat" {
+		t.Fatalf("CreateImage() = %+v, %v", res, err)
+	}
+	images, err := client.DownloadImages(ctx, res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := io.ReadAll(images[0])
+	images[0].Close()
+	if !bytes.Equal(b, openaitest.PNG) {
+		t.Errorf("downloaded %d bytes, want the fake PNG", len(b))
+	}
+
+	// The client validates generations itself, the server rejects them the same way.
+	_, err = client.CreateImage(ctx, &openai.ImageRequest{Model: openai.ImageModelDallE3, Prompt: "a cat", Num: 2})
+	var apiErr *openai.APIError
+	if err == nil || errors.As(err, &apiErr) || len(srv.Requests("/v1/images/generations")) != 1 {
+		t.Errorf("CreateImage() with n=2 error = %v, want a local validation error", err)
+	}
+	raw, _ := http.NewRequest(http.MethodPost, srv.URL()+"/images/generations",
+		strings.NewReader(`{"model":"dall-e-3","prompt":"a cat","n":2}`))
+	raw.Header.Set("Authorization", "Bearer "+openaitest.Token)
+	rawRes, err := http.DefaultClient.Do(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(rawRes.Body)
+	rawRes.Body.Close()
+	if rawRes.StatusCode != http.StatusBadRequest || !strings.Contains(string(body), `"param":"n"`) {
+		t.Errorf("POST /v1/images/generations with n=2 = %d %s", rawRes.StatusCode, body)
+	}
+
+	dir := t.TempDir()
+	imagePath, maskPath := filepath.Join(dir, "image.png"), filepath.Join(dir, "mask.png")
+	os.WriteFile(imagePath, openaitest.PNG, 0o644)
+	os.WriteFile(maskPath, openaitest.PNG, 0o644)
+	res, err = client.CreateImageEidt(ctx, &openai.ImageEditRequest{
+		Image: "image.png", ImagePath: imagePath, Mask: "mask.png", MaskPath: maskPath,
+		Prompt: "add a hat", Num: 2, ResponseFormat: "b64_json",
+	})
+	if err != nil || len(res.Data) != 2 || res.Data[1].B64JSON == "" {
+		t.Fatalf("CreateImageEdit() = %+v, %v", res, err)
+	}
+	edit, _ := srv.LastRequest("/v1/images/edits")
+	if edit.Form["prompt"] != "add a hat" || edit.Form["n"] != "2" || edit.Files["image"].Filename != "image.png" ||
+		!bytes.Equal(edit.Files["mask"].Data, openaitest.PNG) {
+		t.Errorf("recorded edit request = %+v", edit)
+	}
+
+	_, err = client.CreateImageVariation(ctx, &openai.ImageVariationRequest{Model: openai.ImageModelGPTImage1, Image: "image.png", ImagePath: imagePath})
+	if err == nil || errors.As(err, new(*openai.APIError)) {
+		t.Errorf("CreateImageVariation() with gpt-image-1 error = %v, want a validation error", err)
+	}
+	_, err = client.CreateImageVariation(ctx, &openai.ImageVariationRequest{Model: "dall-e-9", Image: "image.png", ImagePath: imagePath})
+	if apiErr := apiError(t, err); apiErr.Param != "model" {
+		t.Errorf("CreateImageVariation() with an unknown model error = %+v", apiErr)
+	}
+}
+
+func TestServer_FaultsAndLatency(t *testing.T) {
+	srv, client := newServer(t)
+	ctx := context.Background()
+
+	srv.FailNext("/v1/models", http.StatusServiceUnavailable, "overloaded")
+	srv.FailNext("/v1/models", http.StatusBadRequest, "bad")
+	for _, want := range []int{http.StatusServiceUnavailable, http.StatusBadRequest} {
+		_, err := client.ListModels(ctx)
+		if apiErr := apiError(t, err); apiErr.StatusCode != want {
+			t.Errorf("ListModels() error = %+v, want status %d", apiErr, want)
+		}
+	}
+	if _, err := client.ListModels(ctx); err != nil {
+		t.Errorf("ListModels() after the injected errors = %v", err)
+	}
+
+	srv.SetLatency(time.Second)
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.ListModels(timeout); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ListModels() with latency error = %v, want a timeout", err)
+	}
+	srv.SetLatency(0)
+
+	srv.Handle("GET /v1/models/{id}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id":%q,"owned_by":"handler"}`, r.PathValue("id"))
+	})
+	if m, err := client.GetModel(ctx, "anything"); err != nil || m.OwnedBy != "handler" {
+		t.Errorf("GetModel() with custom handler = %+v, %v", m, err)
+	}
+	_, err := client.CreateVectorStore(ctx, &openai.VectorStoreRequest{Name: "docs"})
+	if apiErr := apiError(t, err); apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("unsupported endpoint error = %+v", apiErr)
+	}
+}
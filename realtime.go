@@ -0,0 +1,444 @@
+package openai
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/EthanCampana/go-openai/internal/websocket"
+)
+
+// Server events of a realtime session.
+const (
+	RealtimeEventError                        = "error"
+	RealtimeEventSessionCreated               = "session.created"
+	RealtimeEventSessionUpdated               = "session.updated"
+	RealtimeEventConversationItemCreated      = "conversation.item.created"
+	RealtimeEventInputAudioCommitted          = "input_audio_buffer.committed"
+	RealtimeEventInputAudioCleared            = "input_audio_buffer.cleared"
+	RealtimeEventInputAudioSpeechStarted      = "input_audio_buffer.speech_started"
+	RealtimeEventInputAudioSpeechStopped      = "input_audio_buffer.speech_stopped"
+	RealtimeEventInputAudioTranscription      = "conversation.item.input_audio_transcription.completed"
+	RealtimeEventResponseCreated              = "response.created"
+	RealtimeEventResponseDone                 = "response.done"
+	RealtimeEventResponseOutputItemAdded      = "response.output_item.added"
+	RealtimeEventResponseOutputItemDone       = "response.output_item.done"
+	RealtimeEventResponseTextDelta            = "response.text.delta"
+	RealtimeEventResponseTextDone             = "response.text.done"
+	RealtimeEventResponseAudioDelta           = "response.audio.delta"
+	RealtimeEventResponseAudioDone            = "response.audio.done"
+	RealtimeEventResponseAudioTranscriptDelta = "response.audio_transcript.delta"
+	RealtimeEventResponseAudioTranscriptDone  = "response.audio_transcript.done"
+	RealtimeEventResponseFunctionArgsDelta    = "response.function_call_arguments.delta"
+	RealtimeEventResponseFunctionArgsDone     = "response.function_call_arguments.done"
+	RealtimeEventRateLimitsUpdated            = "rate_limits.updated"
+)
+
+const (
+	RealtimeAudioFormatPCM16    = "pcm16"
+	RealtimeAudioFormatG711ULaw = "g711_ulaw"
+	RealtimeAudioFormatG711ALaw = "g711_alaw"
+)
+
+// Time Close waits for the server to acknowledge the close before dropping the connection.
+const realtimeCloseTimeout = 5 * time.Second
+
+type RealtimeTranscription struct {
+	Model    string `json:"model,omitempty"`
+	Language string `json:"language,omitempty"`
+}
+
+// Voice activity detection, Type "server_vad" or "semantic_vad".
+type RealtimeTurnDetection struct {
+	Type              string   `json:"type"`
+	Threshold         *float64 `json:"threshold,omitempty"`
+	PrefixPaddingMs   int      `json:"prefix_padding_ms,omitempty"`
+	SilenceDurationMs int      `json:"silence_duration_ms,omitempty"`
+	CreateResponse    *bool    `json:"create_response,omitempty"`
+	InterruptResponse *bool    `json:"interrupt_response,omitempty"`
+}
+
+// Configuration of a realtime session, sent with session.update and returned by session.created.
+type RealtimeSession struct {
+	ID                      string                 `json:"id,omitempty"`
+	Object                  string                 `json:"object,omitempty"`
+	Model                   string                 `json:"model,omitempty"`
+	Modalities              []string               `json:"modalities,omitempty"`
+	Instructions            string                 `json:"instructions,omitempty"`
+	Voice                   string                 `json:"voice,omitempty"`
+	InputAudioFormat        string                 `json:"input_audio_format,omitempty"`
+	OutputAudioFormat       string                 `json:"output_audio_format,omitempty"`
+	InputAudioTranscription *RealtimeTranscription `json:"input_audio_transcription,omitempty"`
+	TurnDetection           *RealtimeTurnDetection `json:"turn_detection,omitempty"`
+	Tools                   []ResponseTool         `json:"tools,omitempty"`
+	ToolChoice              interface{}            `json:"tool_choice,omitempty"`
+	Temperature             *float32               `json:"temperature,omitempty"`
+	// A number of tokens or "inf".
+	MaxResponseOutputTokens interface{} `json:"max_response_output_tokens,omitempty"`
+}
+
+// A content part of a realtime conversation item: input_text, input_audio, text or audio.
+type RealtimeContent struct {
+	Type       string `json:"type"`
+	Text       string `json:"text,omitempty"`
+	Audio      string `json:"audio,omitempty"`
+	Transcript string `json:"transcript,omitempty"`
+}
+
+// An item of a realtime conversation: a message, function_call or function_call_output.
+type RealtimeItem struct {
+	ID        string            `json:"id,omitempty"`
+	Object    string            `json:"object,omitempty"`
+	Type      string            `json:"type"`
+	Status    string            `json:"status,omitempty"`
+	Role      string            `json:"role,omitempty"`
+	Content   []RealtimeContent `json:"content,omitempty"`
+	CallID    string            `json:"call_id,omitempty"`
+	Name      string            `json:"name,omitempty"`
+	Arguments string            `json:"arguments,omitempty"`
+	Output    string            `json:"output,omitempty"`
+}
+
+// Overrides of the session configuration for a single response.
+type RealtimeResponseConfig struct {
+	Modalities        []string       `json:"modalities,omitempty"`
+	Instructions      string         `json:"instructions,omitempty"`
+	Voice             string         `json:"voice,omitempty"`
+	OutputAudioFormat string         `json:"output_audio_format,omitempty"`
+	Tools             []ResponseTool `json:"tools,omitempty"`
+	ToolChoice        interface{}    `json:"tool_choice,omitempty"`
+	Temperature       *float32       `json:"temperature,omitempty"`
+	MaxOutputTokens   interface{}    `json:"max_output_tokens,omitempty"`
+	// "auto" or "none" to create the response outside of the default conversation.
+	Conversation string            `json:"conversation,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	Input        []RealtimeItem    `json:"input,omitempty"`
+}
+
+type RealtimeUsage struct {
+	TotalTokens  int `json:"total_tokens"`
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// Converts the usage into the chat completion Usage, e.g. for a UsageTracker.
+func (u RealtimeUsage) ChatUsage() Usage {
+	return Usage{PromptTokens: u.InputTokens, CompletionTokens: u.OutputTokens, TotalTokens: u.TotalTokens}
+}
+
+type RealtimeResponse struct {
+	ID            string          `json:"id"`
+	Object        string          `json:"object"`
+	Status        string          `json:"status"`
+	StatusDetails json.RawMessage `json:"status_details,omitempty"`
+	Output        []RealtimeItem  `json:"output"`
+	Usage         *RealtimeUsage  `json:"usage,omitempty"`
+}
+
+type RealtimeError struct {
+	Type    string `json:"type"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+	Param   string `json:"param,omitempty"`
+	EventID string `json:"event_id,omitempty"`
+}
+
+func (e *RealtimeError) Error() string {
+	return fmt.Sprintf("realtime %s: %s", e.Type, e.Message)
+}
+
+type RealtimeRateLimit struct {
+	Name         string  `json:"name"`
+	Limit        int     `json:"limit"`
+	Remaining    int     `json:"remaining"`
+	ResetSeconds float64 `json:"reset_seconds"`
+}
+
+// An event sent by the server. Type tells which of the other fields are set, see the
+// RealtimeEvent constants. Data holds the raw event for fields not decoded here.
+type RealtimeServerEvent struct {
+	Type           string              `json:"type"`
+	EventID        string              `json:"event_id"`
+	Session        *RealtimeSession    `json:"session,omitempty"`
+	Error          *RealtimeError      `json:"error,omitempty"`
+	Item           *RealtimeItem       `json:"item,omitempty"`
+	PreviousItemID string              `json:"previous_item_id,omitempty"`
+	ItemID         string              `json:"item_id,omitempty"`
+	ResponseID     string              `json:"response_id,omitempty"`
+	Response       *RealtimeResponse   `json:"response,omitempty"`
+	OutputIndex    int                 `json:"output_index"`
+	ContentIndex   int                 `json:"content_index"`
+	Part           *RealtimeContent    `json:"part,omitempty"`
+	Delta          string              `json:"delta,omitempty"`
+	Text           string              `json:"text,omitempty"`
+	Transcript     string              `json:"transcript,omitempty"`
+	CallID         string              `json:"call_id,omitempty"`
+	Name           string              `json:"name,omitempty"`
+	Arguments      string              `json:"arguments,omitempty"`
+	AudioStartMs   int                 `json:"audio_start_ms,omitempty"`
+	AudioEndMs     int                 `json:"audio_end_ms,omitempty"`
+	RateLimits     []RealtimeRateLimit `json:"rate_limits,omitempty"`
+	Data           json.RawMessage     `json:"-"`
+}
+
+// Decodes the base64 audio of a response.audio.delta event.
+func (e RealtimeServerEvent) AudioDelta() ([]byte, error) {
+	if e.Type != RealtimeEventResponseAudioDelta {
+		return nil, fmt.Errorf("event %s carries no audio", e.Type)
+	}
+	return base64.StdEncoding.DecodeString(e.Delta)
+}
+
+// An event sent by the client, see the RealtimeClient Send helpers.
+type RealtimeClientEvent interface {
+	RealtimeEventType() string
+}
+
+// session.update
+type RealtimeSessionUpdate struct {
+	EventID string          `json:"event_id,omitempty"`
+	Session RealtimeSession `json:"session"`
+}
+
+// input_audio_buffer.append; Audio is base64 encoded in the input audio format of the session.
+type RealtimeInputAudioAppend struct {
+	EventID string `json:"event_id,omitempty"`
+	Audio   string `json:"audio"`
+}
+
+// input_audio_buffer.commit
+type RealtimeInputAudioCommit struct {
+	EventID string `json:"event_id,omitempty"`
+}
+
+// input_audio_buffer.clear
+type RealtimeInputAudioClear struct {
+	EventID string `json:"event_id,omitempty"`
+}
+
+// conversation.item.create
+type RealtimeConversationItemCreate struct {
+	EventID        string       `json:"event_id,omitempty"`
+	PreviousItemID string       `json:"previous_item_id,omitempty"`
+	Item           RealtimeItem `json:"item"`
+}
+
+// response.create
+type RealtimeResponseCreate struct {
+	EventID  string                  `json:"event_id,omitempty"`
+	Response *RealtimeResponseConfig `json:"response,omitempty"`
+}
+
+// response.cancel
+type RealtimeResponseCancel struct {
+	EventID    string `json:"event_id,omitempty"`
+	ResponseID string `json:"response_id,omitempty"`
+}
+
+func (RealtimeSessionUpdate) RealtimeEventType() string          { return "session.update" }
+func (RealtimeInputAudioAppend) RealtimeEventType() string       { return "input_audio_buffer.append" }
+func (RealtimeInputAudioCommit) RealtimeEventType() string       { return "input_audio_buffer.commit" }
+func (RealtimeInputAudioClear) RealtimeEventType() string        { return "input_audio_buffer.clear" }
+func (RealtimeConversationItemCreate) RealtimeEventType() string { return "conversation.item.create" }
+func (RealtimeResponseCreate) RealtimeEventType() string         { return "response.create" }
+func (RealtimeResponseCancel) RealtimeEventType() string         { return "response.cancel" }
+
+// Marshals a client event together with its type.
+func marshalRealtimeEvent(ev RealtimeClientEvent) ([]byte, error) {
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err = json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	fields["type"], _ = json.Marshal(ev.RealtimeEventType())
+	return json.Marshal(fields)
+}
+
+// A realtime session over a WebSocket connection.
+//
+// Server events are delivered on Events until the session ends, Err then tells why.
+// Events must be drained, the connection is not read while the channel is full.
+type RealtimeClient struct {
+	conn   *websocket.Conn
+	events chan RealtimeServerEvent
+	done   chan struct{}
+	// Records the usage of response.done events under model and the tags of ctx.
+	usage *UsageTracker
+	ctx   context.Context
+	model string
+
+	closeOnce sync.Once
+	mu        sync.Mutex
+	err       error
+	closing   bool
+}
+
+// Opens a realtime session for model. The session is closed gracefully once ctx is done.
+//
+// @Returns openai.RealtimeClient, Close must be called once done with the session.
+func (c *Client) ConnectRealtime(ctx context.Context, model string) (*RealtimeClient, error) {
+	if err := c.usage.checkBudget(); err != nil {
+		return nil, err
+	}
+	req, err := newJSONRequest(ctx, http.MethodGet, "realtime?model="+url.QueryEscape(model), nil)
+	if err != nil {
+		return nil, err
+	}
+	key, err := websocket.SetHandshakeHeaders(req.Header)
+	if err != nil {
+		return nil, err
+	}
+	// The client timeout would cut the connection after the handshake.
+	hc := *c.httpClient
+	hc.Timeout = 0
+	if err = c.resolveURL(c.setHeaders(req)); err != nil {
+		return nil, err
+	}
+	res, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusSwitchingProtocols {
+		defer res.Body.Close()
+		if err = checkResponse(res); err != nil {
+			return nil, err
+		}
+	}
+	if err = websocket.CheckHandshake(res, key); err != nil {
+		return nil, err
+	}
+	rwc, ok := res.Body.(io.ReadWriteCloser)
+	if !ok {
+		res.Body.Close()
+		return nil, errors.New("http transport does not support websocket connections")
+	}
+	rc := &RealtimeClient{
+		conn:   websocket.NewConn(rwc, true),
+		events: make(chan RealtimeServerEvent, 64),
+		done:   make(chan struct{}),
+		usage:  c.usage,
+		ctx:    ctx,
+		model:  model,
+	}
+	go rc.readLoop()
+	go func() {
+		select {
+		case <-ctx.Done():
+			rc.Close()
+		case <-rc.done:
+		}
+	}()
+	return rc, nil
+}
+
+func (rc *RealtimeClient) readLoop() {
+	defer close(rc.done)
+	defer close(rc.events)
+	for {
+		_, data, err := rc.conn.ReadMessage()
+		if err != nil {
+			rc.finish(err)
+			return
+		}
+		var ev RealtimeServerEvent
+		if err = json.Unmarshal(data, &ev); err != nil {
+			rc.finish(fmt.Errorf("decoding realtime event: %w", err))
+			return
+		}
+		ev.Data = data
+		if ev.Type == RealtimeEventResponseDone && ev.Response != nil && ev.Response.Usage != nil && rc.usage != nil {
+			rc.usage.RecordChat(rc.ctx, rc.model, "", ev.Response.Usage.ChatUsage())
+		}
+		rc.events <- ev
+	}
+}
+
+// Records why the session ended. Errors caused by Close are not reported.
+func (rc *RealtimeClient) finish(err error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	var closeErr *websocket.CloseError
+	if rc.closing || errors.As(err, &closeErr) && closeErr.Code == websocket.CloseNormal {
+		err = nil
+	}
+	rc.err = err
+	rc.conn.Close()
+}
+
+// Returns the channel of server events. It is closed when the session ends.
+func (rc *RealtimeClient) Events() <-chan RealtimeServerEvent {
+	return rc.events
+}
+
+// Returns the error that ended the session, nil while it is running or after Close.
+func (rc *RealtimeClient) Err() error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.err
+}
+
+// Sends a client event.
+func (rc *RealtimeClient) Send(ev RealtimeClientEvent) error {
+	b, err := marshalRealtimeEvent(ev)
+	if err != nil {
+		return err
+	}
+	return rc.conn.WriteMessage(websocket.OpText, b)
+}
+
+// Sends a session.update event.
+func (rc *RealtimeClient) UpdateSession(session RealtimeSession) error {
+	return rc.Send(RealtimeSessionUpdate{Session: session})
+}
+
+// Sends audio in the input audio format of the session.
+func (rc *RealtimeClient) AppendInputAudio(audio []byte) error {
+	return rc.Send(RealtimeInputAudioAppend{Audio: base64.StdEncoding.EncodeToString(audio)})
+}
+
+// Commits the input audio buffer, needed when turn detection is disabled.
+func (rc *RealtimeClient) CommitInputAudio() error {
+	return rc.Send(RealtimeInputAudioCommit{})
+}
+
+// Adds an item to the conversation.
+func (rc *RealtimeClient) CreateConversationItem(item RealtimeItem) error {
+	return rc.Send(RealtimeConversationItemCreate{Item: item})
+}
+
+// Asks the model for a response. cfg may be nil to use the session configuration.
+func (rc *RealtimeClient) CreateResponse(cfg *RealtimeResponseConfig) error {
+	return rc.Send(RealtimeResponseCreate{Response: cfg})
+}
+
+// Closes the session. The server is asked to close the connection and given a few seconds
+// to do so; events still arriving meanwhile are discarded.
+func (rc *RealtimeClient) Close() error {
+	rc.closeOnce.Do(func() {
+		rc.mu.Lock()
+		rc.closing = true
+		rc.mu.Unlock()
+		rc.conn.CloseHandshake(websocket.CloseNormal, "")
+		go func() {
+			for range rc.events {
+			}
+		}()
+		select {
+		case <-rc.done:
+		case <-time.After(realtimeCloseTimeout):
+		}
+		rc.conn.Close()
+		<-rc.done
+	})
+	return nil
+}
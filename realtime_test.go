@@ -0,0 +1,154 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/EthanCampana/go-openai"
+	"github.com/EthanCampana/go-openai/internal/websocket"
+)
+
+// Serves a realtime stand-in that answers every client event through respond.
+func newRealtimeTestClient(t *testing.T, respond func(ev map[string]interface{}) []string) *Client {
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/realtime" || r.URL.Query().Get("model") != "gpt-4o-realtime-preview" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		if beta := r.Header.Get("OpenAI-Beta"); beta != "realtime=v1" {
+			t.Errorf("OpenAI-Beta = %q", beta)
+		}
+		conn, err := websocket.Accept(w, r)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		conn.WriteMessage(websocket.OpText, []byte(`{"type":"session.created","event_id":"ev_0","session":{"id":"sess_1","model":"gpt-4o-realtime-preview"}}`))
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var ev map[string]interface{}
+			if err = json.Unmarshal(data, &ev); err != nil {
+				t.Error(err)
+				return
+			}
+			for _, msg := range respond(ev) {
+				conn.WriteMessage(websocket.OpText, []byte(msg))
+			}
+		}
+	})
+}
+
+func TestRealtimeClient(t *testing.T) {
+	audio := []byte{1, 2, 3, 4}
+	var received []string
+	client := newRealtimeTestClient(t, func(ev map[string]interface{}) []string {
+		received = append(received, ev["type"].(string))
+		switch ev["type"] {
+		case "session.update":
+			session := ev["session"].(map[string]interface{})
+			return []string{`{"type":"session.updated","session":{"voice":"` + session["voice"].(string) + `"}}`}
+		case "input_audio_buffer.append":
+			if ev["audio"] != base64.StdEncoding.EncodeToString(audio) {
+				t.Errorf("appended audio = %v", ev["audio"])
+			}
+		case "response.create":
+			return []string{
+				`{"type":"response.audio.delta","response_id":"resp_1","delta":"` + base64.StdEncoding.EncodeToString(audio) + `"}`,
+				`{"type":"response.text.delta","response_id":"resp_1","delta":"Hi"}`,
+				`{"type":"error","error":{"type":"invalid_request_error","message":"unknown voice"}}`,
+				`{"type":"response.done","response":{"id":"resp_1","status":"completed","usage":{"total_tokens":7,"input_tokens":5,"output_tokens":2}}}`,
+			}
+		}
+		return nil
+	})
+
+	tracker := NewUsageTracker()
+	client.SetUsageTracker(tracker)
+	rc, err := client.ConnectRealtime(context.Background(), "gpt-4o-realtime-preview")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = rc.UpdateSession(RealtimeSession{Voice: "alloy", InputAudioFormat: RealtimeAudioFormatPCM16}); err != nil {
+		t.Fatal(err)
+	}
+	if err = rc.AppendInputAudio(audio); err != nil {
+		t.Fatal(err)
+	}
+	if err = rc.CreateResponse(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var types []string
+	for ev := range rc.Events() {
+		types = append(types, ev.Type)
+		switch ev.Type {
+		case RealtimeEventSessionUpdated:
+			if ev.Session.Voice != "alloy" {
+				t.Errorf("session.updated voice = %q", ev.Session.Voice)
+			}
+		case RealtimeEventResponseAudioDelta:
+			if b, err := ev.AudioDelta(); err != nil || string(b) != string(audio) {
+				t.Errorf("AudioDelta() = %v, %v", b, err)
+			}
+		case RealtimeEventError:
+			if ev.Error.Message != "unknown voice" {
+				t.Errorf("error event = %+v", ev.Error)
+			}
+		case RealtimeEventResponseDone:
+			if ev.Response.Usage.TotalTokens != 7 {
+				t.Errorf("response.done = %+v", ev.Response)
+			}
+			rc.Close()
+		}
+	}
+	if err = rc.Err(); err != nil {
+		t.Errorf("Err() = %v after Close", err)
+	}
+	want := []string{"session.created", "session.updated", "response.audio.delta", "response.text.delta", "error", "response.done"}
+	if len(types) != len(want) {
+		t.Fatalf("events = %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("events = %v, want %v", types, want)
+			break
+		}
+	}
+	if len(received) != 3 || received[2] != "response.create" {
+		t.Errorf("server received %v", received)
+	}
+	if got := tracker.ByModel()["gpt-4o-realtime-preview"]; got.Requests != 1 || got.PromptTokens != 5 || got.CompletionTokens != 2 {
+		t.Errorf("recorded realtime usage = %+v", got)
+	}
+}
+
+func TestRealtimeClient_ContextCancel(t *testing.T) {
+	client := newRealtimeTestClient(t, func(ev map[string]interface{}) []string { return nil })
+	ctx, cancel := context.WithCancel(context.Background())
+	rc, err := client.ConnectRealtime(ctx, "gpt-4o-realtime-preview")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev := <-rc.Events(); ev.Type != RealtimeEventSessionCreated || ev.Session.ID != "sess_1" {
+		t.Errorf("first event = %+v", ev)
+	}
+	cancel()
+	select {
+	case _, ok := <-rc.Events():
+		if ok {
+			t.Error("received an event after the context was cancelled")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("session was not closed after the context was cancelled")
+	}
+	if err = rc.Send(RealtimeInputAudioCommit{}); err == nil {
+		t.Error("Send() succeeded on a closed session")
+	}
+}
@@ -1,7 +1,7 @@
 package openai
 
 import (
-	"fmt"
+	"errors"
 	"log"
 	"strings"
 )
@@ -10,9 +10,58 @@ type RequestBuilder interface {
 	ReturnRequest() Request
 }
 
+// Builder is a RequestBuilder that knows the concrete type of the Request it builds.
+//
+// Unlike ReturnRequest, Build reports an incomplete or invalid Request as an error.
+type Builder[T Request] interface {
+	RequestBuilder
+	Build() (T, error)
+}
+
+var (
+	_ Builder[*ImageRequest]          = ImageRequestBuilder{}
+	_ Builder[*ImageVariationRequest] = ImageVariationRequestBuilder{}
+	_ Builder[*ImageEditRequest]      = ImageEditRequestBuilder{}
+)
+
+// Creates an ImageRequestBuilder with the default values of an ImageRequest.
+func NewImageRequestBuilder() *ImageRequestBuilder {
+	return &ImageRequestBuilder{Req: &ImageRequest{
+		Num:            1,
+		Prompt:         "",
+		Size:           SMALL,
+		ResponseFormat: "url",
+		User:           "",
+	}}
+}
+
+// Creates an ImageVariationRequestBuilder with the default values of an ImageVariationRequest.
+func NewImageVariationRequestBuilder() *ImageVariationRequestBuilder {
+	return &ImageVariationRequestBuilder{
+		Irb:       *NewImageRequestBuilder(),
+		Image:     "",
+		ImagePath: "",
+	}
+}
+
+// Creates an ImageEditRequestBuilder with the default values of an ImageEditRequest.
+func NewImageEditRequestBuilder() *ImageEditRequestBuilder {
+	return &ImageEditRequestBuilder{
+		Irb:       *NewImageRequestBuilder(),
+		Image:     "",
+		ImagePath: "",
+		Mask:      "",
+		MaskPath:  "",
+	}
+}
+
 /*
 Creates A RequestBuilder and Returns it.
 
+Prefer the typed constructors (NewImageRequestBuilder, ...) when the builder is known at compile time,
+this function is meant for builders selected through configuration. Unknown names are logged and
+return nil, use LookupRequestBuilder to get an error instead.
+
 Params: builder -> Name of the builder you would like to Return
 
 RequestBuilders:
@@ -22,39 +71,15 @@ RequestBuilders:
 - image-variation
 
 - image-edit
+
+- any builder added with RegisterRequestBuilder
 .
 */
 func (c *Client) GetRequestBuilder(builder string) RequestBuilder {
-	var b RequestBuilder
-	switch {
-	case builder == "image":
-		imgreq := &ImageRequest{
-			Num:            1,
-			Prompt:         "",
-			Size:           SMALL,
-			ResponseFormat: "url",
-			User:           "",
-		}
-		b = ImageRequestBuilder{Req: imgreq}
-	case builder == "image-variation":
-		irb, _ := c.GetRequestBuilder("image").(ImageRequestBuilder)
-		b = ImageVariationRequestBuilder{
-			Irb:       irb,
-			Image:     "",
-			ImagePath: "",
-		}
-	case builder == "image-edit":
-		irb, _ := c.GetRequestBuilder("image").(ImageRequestBuilder)
-		b = ImageEditRequestBuilder{
-			Irb:       irb,
-			Image:     "",
-			ImagePath: "",
-			Mask:      "",
-			MaskPath:  "",
-		}
-	default:
-		err := fmt.Errorf("%s is not an accepted builder option", builder)
-		log.Printf("Function Call GetRequestBuilder failed with builder: %s %v", builder, err)
+	b, err := c.LookupRequestBuilder(builder)
+	if err != nil {
+		log.Printf("Function Call GetRequestBuilder failed: %v", err)
+		return nil
 	}
 	return b
 }
@@ -62,6 +87,7 @@ func (c *Client) GetRequestBuilder(builder string) RequestBuilder {
 func imageRequestToImageVariationRequest(ir *ImageRequest) *ImageVariationRequest {
 	return &ImageVariationRequest{
 		Num:            ir.Num,
+		Model:          ir.Model,
 		Prompt:         ir.Prompt,
 		Size:           ir.Size,
 		User:           ir.User,
@@ -74,6 +100,7 @@ func imageRequestToImageVariationRequest(ir *ImageRequest) *ImageVariationReques
 func imageRequestToImageEditRequest(ivr *ImageRequest) *ImageEditRequest {
 	return &ImageEditRequest{
 		Num:            ivr.Num,
+		Model:          ivr.Model,
 		Prompt:         ivr.Prompt,
 		Size:           ivr.Size,
 		User:           ivr.User,
@@ -105,27 +132,51 @@ type ImageEditRequestBuilder struct {
 	MaskPath  string
 }
 
+// Returns the Underlying Request of the Given RequestBuilder, or nil if the image is missing.
+// Use Build to get the reason as an error.
 func (ierb ImageEditRequestBuilder) ReturnRequest() Request {
+	ier, err := ierb.Build()
+	if err != nil {
+		log.Printf("[WARN] %v. Returning nil", err)
+		return nil
+	}
+	return ier
+}
+
+// Returns the Underlying Request of the Given RequestBuilder or an error if the image is missing.
+// The mask is optional.
+func (ierb ImageEditRequestBuilder) Build() (*ImageEditRequest, error) {
 	ier := imageRequestToImageEditRequest(ierb.Irb.Req)
 	ier.Mask = ierb.Mask
 	ier.MaskPath = ierb.MaskPath
 	ier.Image = ierb.Image
 	ier.ImagePath = ierb.ImagePath
-	if ier.Image == "" || ier.Mask == "" {
-		log.Fatal("Cannot Generate Request if ImagePath or MaskPath is empty!")
+	if ier.Image == "" {
+		return nil, errors.New("cannot generate request if ImagePath is empty")
 	}
-	return ier
+	return ier, nil
 }
 
-// Returns the Underlying Request of the Given RequestBuilder.
+// Returns the Underlying Request of the Given RequestBuilder, or nil if the image is missing.
+// Use Build to get the reason as an error.
 func (ivrb ImageVariationRequestBuilder) ReturnRequest() Request {
+	ivr, err := ivrb.Build()
+	if err != nil {
+		log.Printf("[WARN] %v. Returning nil", err)
+		return nil
+	}
+	return ivr
+}
+
+// Returns the Underlying Request of the Given RequestBuilder or an error if the image is missing.
+func (ivrb ImageVariationRequestBuilder) Build() (*ImageVariationRequest, error) {
 	ivr := imageRequestToImageVariationRequest(ivrb.Irb.Req)
 	ivr.Image = ivrb.Image
 	ivr.ImagePath = ivrb.ImagePath
 	if ivr.Image == "" {
-		log.Fatal("Cannot Generate Request if ImagePath is empty!")
+		return nil, errors.New("cannot generate request if ImagePath is empty")
 	}
-	return ivr
+	return ivr, nil
 }
 
 // Sets the image to upload to upload of the underlying Request.
@@ -140,6 +191,14 @@ func (irb ImageRequestBuilder) ReturnRequest() Request {
 	return irb.Req
 }
 
+// Returns the Underlying Request of the Given RequestBuilder or an error if it is invalid for its model.
+func (irb ImageRequestBuilder) Build() (*ImageRequest, error) {
+	if err := irb.Req.Validate(); err != nil {
+		return nil, err
+	}
+	return irb.Req, nil
+}
+
 // Sets the prompt of the underlying Request.
 func (irb *ImageRequestBuilder) SetPrompt(prompt string) *ImageRequestBuilder {
 	irb.Req.Prompt = prompt
@@ -147,10 +206,16 @@ func (irb *ImageRequestBuilder) SetPrompt(prompt string) *ImageRequestBuilder {
 }
 
 // Sets the ResponseFormat of the underlying Request.
+//
+// gpt-image-1 always returns b64_json and does not accept a response format.
 func (irb *ImageRequestBuilder) SetResponseFormat(rf string) *ImageRequestBuilder {
+	formats := irb.spec().ResponseFormats
 	switch {
-	case rf == "url" || rf == "b64_json":
+	case contains(formats, rf):
 		irb.Req.ResponseFormat = rf
+	case len(formats) == 0:
+		log.Println("[WARN] model does not accept a response format. Leaving response format unset")
+		irb.Req.ResponseFormat = ""
 	default:
 		log.Println("[WARN] response format you provided is invalid. Setting response format to url")
 		irb.Req.ResponseFormat = "url"
@@ -166,9 +231,9 @@ func (irb *ImageRequestBuilder) SetUser(user string) *ImageRequestBuilder {
 
 // Sets the number of images to generate of the underlying Request.
 //
-// min=1, max=10, default=1.
+// min=1, max=10 (1 for dall-e-3), default=1.
 func (irb *ImageRequestBuilder) SetNumberOfPictures(num uint8) *ImageRequestBuilder {
-	if num > MaxImageRequest {
+	if !irb.spec().acceptsNum(num) {
 		log.Println("[WARN] Num you provided is not accepted. Setting Num to 1")
 		num = uint8(1)
 	}
@@ -176,27 +241,135 @@ func (irb *ImageRequestBuilder) SetNumberOfPictures(num uint8) *ImageRequestBuil
 	return irb
 }
 
-// Sets the size of the underlying Request
+// Sets the size of the underlying Request. The size must be supported by the current model,
+// so call SetModel first when not using dall-e-2.
+//
+// dall-e-2: SMALL = 256x256  MEDIUM = 512x512  LARGE = 1024x1024  Default = 256x256.
+//
+// dall-e-3: LARGE = 1024x1024  LANDSCAPE = 1792x1024  PORTRAIT = 1024x1792  Default = 1024x1024.
 //
-// SMALL = 256x256  MEDIUM = 512x512  LARGE = 1024x1024  Default = 256x265.
+// gpt-image-1: AUTO  LARGE = 1024x1024  WIDE = 1536x1024  TALL = 1024x1536  Default = auto.
 func (irb *ImageRequestBuilder) SetSize(size string) *ImageRequestBuilder {
-	switch {
-	case size == SMALL:
-		irb.Req.Size = SMALL
-	case size == MEDIUM:
-		irb.Req.Size = MEDIUM
-	case size == LARGE:
-		irb.Req.Size = LARGE
-	default:
-		log.Println("[WARN] Size you provided is not accepted. Setting Size to 256x256")
-		irb.Req.Size = SMALL
+	spec := irb.spec()
+	if !contains(spec.Sizes, size) {
+		log.Printf("[WARN] Size you provided is not accepted. Setting Size to %s", spec.defaultSize())
+		size = spec.defaultSize()
+	}
+	irb.Req.Size = size
+	return irb
+}
+
+// Sets the model of the underlying Request.
+//
+// Parameters that the new model does not accept are reset to the model defaults.
+func (irb *ImageRequestBuilder) SetModel(model string) *ImageRequestBuilder {
+	if _, err := GetImageModelSpec(model); err != nil {
+		log.Printf("[WARN] %v. Setting Model to %s", err, DefaultImageModel)
+		model = DefaultImageModel
+	}
+	irb.Req.Model = model
+	spec := irb.spec()
+	if irb.Req.Size != "" && !contains(spec.Sizes, irb.Req.Size) {
+		irb.Req.Size = spec.defaultSize()
 	}
+	if !spec.acceptsNum(irb.Req.Num) {
+		irb.Req.Num = 1
+	}
+	if !contains(spec.Qualities, irb.Req.Quality) {
+		irb.Req.Quality = ""
+	}
+	if !contains(spec.Styles, irb.Req.Style) {
+		irb.Req.Style = ""
+	}
+	if !contains(spec.Backgrounds, irb.Req.Background) {
+		irb.Req.Background = ""
+	}
+	if !contains(spec.OutputFormats, irb.Req.OutputFormat) {
+		irb.Req.OutputFormat = ""
+		irb.Req.OutputCompression = nil
+	}
+	if !contains(spec.ResponseFormats, irb.Req.ResponseFormat) {
+		irb.Req.ResponseFormat = ""
+	}
+	return irb
+}
+
+// Sets the quality of the underlying Request.
+//
+// dall-e-3: standard, hd  gpt-image-1: auto, low, medium, high.
+func (irb *ImageRequestBuilder) SetQuality(quality string) *ImageRequestBuilder {
+	irb.Req.Quality = acceptedOption("quality", quality, irb.spec().Qualities)
+	return irb
+}
+
+// Sets the style of the underlying Request.
+//
+// dall-e-3 only: vivid, natural.
+func (irb *ImageRequestBuilder) SetStyle(style string) *ImageRequestBuilder {
+	irb.Req.Style = acceptedOption("style", style, irb.spec().Styles)
+	return irb
+}
+
+// Sets the background of the underlying Request.
+//
+// gpt-image-1 only: auto, transparent, opaque.
+func (irb *ImageRequestBuilder) SetBackground(background string) *ImageRequestBuilder {
+	irb.Req.Background = acceptedOption("background", background, irb.spec().Backgrounds)
+	return irb
+}
+
+// Sets the output format of the underlying Request.
+//
+// gpt-image-1 only: png, jpeg, webp.
+func (irb *ImageRequestBuilder) SetOutputFormat(format string) *ImageRequestBuilder {
+	irb.Req.OutputFormat = acceptedOption("output_format", format, irb.spec().OutputFormats)
 	return irb
 }
 
+// Sets the output compression of the underlying Request.
+//
+// gpt-image-1 only, min=0, max=100.
+func (irb *ImageRequestBuilder) SetOutputCompression(compression uint8) *ImageRequestBuilder {
+	if len(irb.spec().OutputFormats) == 0 || compression > MaxOutputCompression {
+		log.Println("[WARN] Output compression you provided is not accepted. Leaving it unset")
+		irb.Req.OutputCompression = nil
+		return irb
+	}
+	irb.Req.OutputCompression = &compression
+	return irb
+}
+
+func (irb *ImageRequestBuilder) spec() ImageModelSpec {
+	spec, err := GetImageModelSpec(irb.Req.Model)
+	if err != nil {
+		spec = ImageModels[DefaultImageModel]
+	}
+	return spec
+}
+
+func acceptedOption(param, value string, allowed []string) string {
+	if contains(allowed, value) {
+		return value
+	}
+	log.Printf("[WARN] %s you provided is not accepted. Leaving %s unset", param, param)
+	return ""
+}
+
+// Sets the model of the underlying Request.
+//
+// Only dall-e-2 supports variations.
+func (ivrb *ImageVariationRequestBuilder) SetModel(model string) *ImageVariationRequestBuilder {
+	if spec, err := GetImageModelSpec(model); err != nil || !spec.SupportsVary {
+		log.Printf("[WARN] %s does not support image variations. Setting Model to %s", model, DefaultImageModel)
+		model = DefaultImageModel
+	}
+	ivrb.Irb.SetModel(model)
+	return ivrb
+}
+
 // Sets the size of the underlying Request
 //
-// SMALL = 256x256  MEDIUM = 512x512  LARGE = 1024x1024  Default = 256x265.
+// SMALL = 256x256  MEDIUM = 512x512  LARGE = 1024x1024  Default = 256x256.
 func (ivrb *ImageVariationRequestBuilder) SetSize(size string) *ImageVariationRequestBuilder {
 	ivrb.Irb.SetSize(size)
 	return ivrb
@@ -228,9 +401,21 @@ func (ivrb *ImageVariationRequestBuilder) SetPrompt(prompt string) *ImageVariati
 	return ivrb
 }
 
-// Sets the size of the underlying Request
+// Sets the model of the underlying Request.
+//
+// dall-e-2 and gpt-image-1 support image edits.
+func (ierb *ImageEditRequestBuilder) SetModel(model string) *ImageEditRequestBuilder {
+	if spec, err := GetImageModelSpec(model); err != nil || !spec.SupportsEdit {
+		log.Printf("[WARN] %s does not support image edits. Setting Model to %s", model, DefaultImageModel)
+		model = DefaultImageModel
+	}
+	ierb.Irb.SetModel(model)
+	return ierb
+}
+
+// Sets the size of the underlying Request. Call SetModel first when not using dall-e-2.
 //
-// SMALL = 256x256  MEDIUM = 512x512  LARGE = 1024x1024  Default = 256x265.
+// See ImageRequestBuilder.SetSize for the sizes of each model.
 func (ierb *ImageEditRequestBuilder) SetSize(size string) *ImageEditRequestBuilder {
 	ierb.Irb.SetSize(size)
 	return ierb
@@ -1,6 +1,10 @@
 package openai_test
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -375,3 +379,213 @@ func TestClient_GetRequestBuilder(t *testing.T) {
 		})
 	}
 }
+
+func TestImageRequestBuilder_SetModel(t *testing.T) {
+	compression := uint8(80)
+	tests := []struct {
+		name  string
+		build func(irb *ImageRequestBuilder)
+		want  *ImageRequest
+	}{
+		{
+			name: "dall-e-3 landscape hd",
+			build: func(irb *ImageRequestBuilder) {
+				irb.SetModel(ImageModelDallE3).SetSize(LANDSCAPE).SetQuality("hd").SetStyle("natural").SetNumberOfPictures(3)
+			},
+			want: &ImageRequest{
+				Num:            1,
+				Model:          ImageModelDallE3,
+				Size:           LANDSCAPE,
+				Quality:        "hd",
+				Style:          "natural",
+				ResponseFormat: "url",
+			},
+		},
+		{
+			name: "gpt-image-1 drops response format",
+			build: func(irb *ImageRequestBuilder) {
+				irb.SetModel(ImageModelGPTImage1).SetSize(WIDE).SetBackground("transparent").
+					SetOutputFormat("webp").SetOutputCompression(compression).SetStyle("vivid")
+			},
+			want: &ImageRequest{
+				Num:               1,
+				Model:             ImageModelGPTImage1,
+				Size:              WIDE,
+				Background:        "transparent",
+				OutputFormat:      "webp",
+				OutputCompression: &compression,
+			},
+		},
+		{
+			name: "Switching model resets unsupported size",
+			build: func(irb *ImageRequestBuilder) {
+				irb.SetSize(MEDIUM).SetModel(ImageModelDallE3)
+			},
+			want: &ImageRequest{
+				Num:            1,
+				Model:          ImageModelDallE3,
+				Size:           LARGE,
+				ResponseFormat: "url",
+			},
+		},
+		{
+			name: "Unknown model falls back to dall-e-2",
+			build: func(irb *ImageRequestBuilder) {
+				irb.SetModel("dall-e-9").SetSize(LANDSCAPE)
+			},
+			want: &ImageRequest{
+				Num:            1,
+				Model:          ImageModelDallE2,
+				Size:           SMALL,
+				ResponseFormat: "url",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := GetClient("SOME TOKEN")
+			irb, _ := c.GetRequestBuilder("image").(ImageRequestBuilder)
+			tt.build(&irb)
+			got := irb.ReturnRequest()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ImageRequestBuilder.ReturnRequest() = %+v, want %+v", got, tt.want)
+			}
+			if err := got.(*ImageRequest).Validate(); err != nil {
+				t.Errorf("ImageRequest.Validate() = %v", err)
+			}
+		})
+	}
+}
+
+func TestImageEditAndVariationRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     interface{ Validate() error }
+		wantErr bool
+	}{
+		{name: "Edit", req: &ImageEditRequest{Image: "a.png", Model: ImageModelGPTImage1, Size: WIDE}},
+		{name: "Edit without image", req: &ImageEditRequest{Prompt: "hat"}, wantErr: true},
+		{name: "dall-e-3 cannot edit", req: &ImageEditRequest{Image: "a.png", Model: ImageModelDallE3}, wantErr: true},
+		{name: "Edit size of another model", req: &ImageEditRequest{Image: "a.png", Size: WIDE}, wantErr: true},
+		{name: "Variation", req: &ImageVariationRequest{Image: "a.png", Num: 10}},
+		{name: "gpt-image-1 cannot vary", req: &ImageVariationRequest{Image: "a.png", Model: ImageModelGPTImage1}, wantErr: true},
+		{name: "Variation of unknown model", req: &ImageVariationRequest{Image: "a.png", Model: "dall-e-9", Num: 50}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImageRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     ImageRequest
+		wantErr bool
+	}{
+		{name: "Default model", req: ImageRequest{Num: 10, Size: MEDIUM}},
+		{name: "dall-e-2 rejects rectangles", req: ImageRequest{Size: LANDSCAPE}, wantErr: true},
+		{name: "dall-e-3 single image", req: ImageRequest{Model: ImageModelDallE3, Num: 2}, wantErr: true},
+		{name: "gpt-image-1 rejects url", req: ImageRequest{Model: ImageModelGPTImage1, ResponseFormat: "url"}, wantErr: true},
+		{name: "Unknown model is left to the API", req: ImageRequest{Model: "dall-e-9", Size: "2048x2048", Num: 20}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("ImageRequest.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTypedBuilders_Build(t *testing.T) {
+	req, err := NewImageRequestBuilder().SetPrompt("Hello World").SetNumberOfPictures(2).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Prompt != "Hello World" || req.Num != 2 || req.Size != SMALL {
+		t.Errorf("ImageRequestBuilder.Build() = %+v", req)
+	}
+
+	if _, err = NewImageVariationRequestBuilder().Build(); err == nil {
+		t.Error("ImageVariationRequestBuilder.Build() expected error without image")
+	}
+	ivr, err := NewImageVariationRequestBuilder().SetImage("home/test.png").Build()
+	if err != nil || ivr.Image != "test.png" {
+		t.Errorf("ImageVariationRequestBuilder.Build() = %+v, %v", ivr, err)
+	}
+
+	if _, err = NewImageEditRequestBuilder().SetMask("mask.png").Build(); err == nil {
+		t.Error("ImageEditRequestBuilder.Build() expected error without image")
+	}
+	if got := NewImageEditRequestBuilder().ReturnRequest(); got != nil {
+		t.Errorf("ImageEditRequestBuilder.ReturnRequest() = %v, want nil without image", got)
+	}
+	if ier, err := NewImageEditRequestBuilder().SetImage("home/test.png").Build(); err != nil || ier.Mask != "" {
+		t.Errorf("ImageEditRequestBuilder.Build() without mask = %+v, %v", ier, err)
+	}
+	ier, err := NewImageEditRequestBuilder().SetImage("home/test.png").SetMask("mask.png").Build()
+	if err != nil || ier.Mask != "mask.png" {
+		t.Errorf("ImageEditRequestBuilder.Build() = %+v, %v", ier, err)
+	}
+}
+
+func TestImageEditRequest_WithoutMask(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.png")
+	if err := os.WriteFile(path, testPNG(t), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ier, err := NewImageEditRequestBuilder().SetImage(path).SetPrompt("add a hat").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := ier.GenerateHTTPRequest(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = req.ParseMultipartForm(1 << 20); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := req.MultipartForm.File["image"]; !ok {
+		t.Error("edit request has no image")
+	}
+	if _, ok := req.MultipartForm.File["mask"]; ok {
+		t.Error("edit request without mask has a mask part")
+	}
+}
+
+func TestClient_RegisterRequestBuilder(t *testing.T) {
+	c := GetClient("SOME TOKEN")
+	if _, err := c.LookupRequestBuilder("image-hd"); !errors.Is(err, ErrUnknownBuilder) || err.Error() != "unknown request builder: image-hd" {
+		t.Fatalf("Client.LookupRequestBuilder() error = %v, want ErrUnknownBuilder", err)
+	}
+	if got := c.GetRequestBuilder("image-hd"); got != nil {
+		t.Errorf("Client.GetRequestBuilder() = %v, want nil", got)
+	}
+	err := c.RegisterRequestBuilder("image-hd", func() RequestBuilder {
+		return *NewImageRequestBuilder().SetModel(ImageModelDallE3).SetQuality("hd")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = c.RegisterRequestBuilder("broken", nil); err == nil {
+		t.Error("Client.RegisterRequestBuilder() expected error for nil factory")
+	}
+	want := []string{"image", "image-edit", "image-hd", "image-variation"}
+	if got := c.RequestBuilders(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Client.RequestBuilders() = %v, want %v", got, want)
+	}
+	b, err := c.LookupRequestBuilder("image-hd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req := b.ReturnRequest().(*ImageRequest); req.Quality != "hd" {
+		t.Errorf("registered builder returned %+v", req)
+	}
+	if names := GetClient("OTHER").RequestBuilders(); len(names) != 3 {
+		t.Errorf("registrations leaked between clients: %v", names)
+	}
+}
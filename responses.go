@@ -0,0 +1,402 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"strings"
+)
+
+const (
+	ResponseStatusCompleted  = "completed"
+	ResponseStatusFailed     = "failed"
+	ResponseStatusInProgress = "in_progress"
+	ResponseStatusCancelled  = "cancelled"
+	ResponseStatusQueued     = "queued"
+	ResponseStatusIncomplete = "incomplete"
+)
+
+// Types of the items in the input and output of a response.
+const (
+	ResponseItemTypeMessage            = "message"
+	ResponseItemTypeFunctionCall       = "function_call"
+	ResponseItemTypeFunctionCallOutput = "function_call_output"
+	ResponseItemTypeReasoning          = "reasoning"
+	ResponseItemTypeWebSearchCall      = "web_search_call"
+	ResponseItemTypeFileSearchCall     = "file_search_call"
+	ResponseItemTypeCodeInterpreter    = "code_interpreter_call"
+	ResponseItemTypeImageGeneration    = "image_generation_call"
+	ResponseItemTypeItemReference      = "item_reference"
+)
+
+// Types of the content parts of response items.
+const (
+	ResponseContentTypeInputText   = "input_text"
+	ResponseContentTypeInputImage  = "input_image"
+	ResponseContentTypeInputFile   = "input_file"
+	ResponseContentTypeOutputText  = "output_text"
+	ResponseContentTypeRefusal     = "refusal"
+	ResponseContentTypeSummaryText = "summary_text"
+)
+
+// Built-in tools of the Responses API.
+const (
+	ResponseToolTypeFunction        = ToolTypeFunction
+	ResponseToolTypeWebSearch       = "web_search_preview"
+	ResponseToolTypeFileSearch      = "file_search"
+	ResponseToolTypeCodeInterpreter = "code_interpreter"
+	ResponseToolTypeImageGeneration = "image_generation"
+	ResponseToolTypeMCP             = "mcp"
+)
+
+const (
+	ReasoningEffortMinimal = "minimal"
+	ReasoningEffortLow     = "low"
+	ReasoningEffortMedium  = "medium"
+	ReasoningEffortHigh    = "high"
+)
+
+type ResponseAnnotation struct {
+	Type       string `json:"type"`
+	Index      int    `json:"index,omitempty"`
+	StartIndex int    `json:"start_index,omitempty"`
+	EndIndex   int    `json:"end_index,omitempty"`
+	FileID     string `json:"file_id,omitempty"`
+	Filename   string `json:"filename,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Title      string `json:"title,omitempty"`
+}
+
+// A content part of a response item, see the ResponseContentType constants.
+type ResponseContent struct {
+	Type        string               `json:"type"`
+	Text        string               `json:"text,omitempty"`
+	ImageURL    string               `json:"image_url,omitempty"`
+	FileID      string               `json:"file_id,omitempty"`
+	FileData    string               `json:"file_data,omitempty"`
+	Filename    string               `json:"filename,omitempty"`
+	Detail      string               `json:"detail,omitempty"`
+	Annotations []ResponseAnnotation `json:"annotations,omitempty"`
+	Refusal     string               `json:"refusal,omitempty"`
+}
+
+// An item of the input or output of a response, see the ResponseItemType constants.
+// Only the fields of its type are set.
+type ResponseItem struct {
+	Type    string            `json:"type"`
+	ID      string            `json:"id,omitempty"`
+	Status  string            `json:"status,omitempty"`
+	Role    string            `json:"role,omitempty"`
+	Content []ResponseContent `json:"content,omitempty"`
+	// Function calls and their outputs.
+	CallID    string `json:"call_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+	Output    string `json:"output,omitempty"`
+	// Reasoning summaries.
+	Summary []ResponseContent `json:"summary,omitempty"`
+	// Queries and results of file searches.
+	Queries []string        `json:"queries,omitempty"`
+	Results json.RawMessage `json:"results,omitempty"`
+	// Base64 encoded image of an image generation call.
+	Result string `json:"result,omitempty"`
+	// Action of a web search call.
+	Action json.RawMessage `json:"action,omitempty"`
+}
+
+// Returns the concatenated text parts of the item.
+func (i ResponseItem) Text() string {
+	var sb strings.Builder
+	for _, c := range i.Content {
+		if c.Type == ResponseContentTypeOutputText || c.Type == ResponseContentTypeInputText {
+			sb.WriteString(c.Text)
+		}
+	}
+	return sb.String()
+}
+
+// Converts a function call item into a ToolCall, e.g. to execute it with a ToolHandler.
+func (i ResponseItem) ToolCall() ToolCall {
+	return ToolCall{
+		ID:       i.CallID,
+		Type:     ToolTypeFunction,
+		Function: FunctionCall{Name: i.Name, Arguments: i.Arguments},
+	}
+}
+
+// Decodes the arguments of a function call item into v.
+func (i ResponseItem) DecodeArguments(v interface{}) error {
+	return i.ToolCall().DecodeArguments(v)
+}
+
+// Creates an input message. Role is user, system, developer or assistant.
+func NewResponseMessage(role, text string) ResponseItem {
+	partType := ResponseContentTypeInputText
+	if role == ChatMessageRoleAssistant {
+		partType = ResponseContentTypeOutputText
+	}
+	return ResponseItem{
+		Type:    ResponseItemTypeMessage,
+		Role:    role,
+		Content: []ResponseContent{{Type: partType, Text: text}},
+	}
+}
+
+// Creates the input item answering a function call of a previous response.
+func NewFunctionCallOutput(callID, output string) ResponseItem {
+	return ResponseItem{Type: ResponseItemTypeFunctionCallOutput, CallID: callID, Output: output}
+}
+
+// A tool of a response, either a function or one of the built-in tools.
+type ResponseTool struct {
+	Type string `json:"type"`
+	// Function tools.
+	Name        string      `json:"name,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Parameters  interface{} `json:"parameters,omitempty"`
+	Strict      *bool       `json:"strict,omitempty"`
+	// File search.
+	VectorStoreIDs []string    `json:"vector_store_ids,omitempty"`
+	MaxNumResults  int         `json:"max_num_results,omitempty"`
+	Filters        interface{} `json:"filters,omitempty"`
+	// Web search: "low", "medium" or "high".
+	SearchContextSize string `json:"search_context_size,omitempty"`
+	// Code interpreter: a container id or {"type": "auto"}.
+	Container interface{} `json:"container,omitempty"`
+	// Remote MCP servers.
+	ServerLabel     string      `json:"server_label,omitempty"`
+	ServerURL       string      `json:"server_url,omitempty"`
+	RequireApproval interface{} `json:"require_approval,omitempty"`
+	AllowedTools    []string    `json:"allowed_tools,omitempty"`
+}
+
+// Converts a chat completion function tool into a response tool.
+func NewResponseFunctionTool(tool Tool) ResponseTool {
+	fn := tool.Function
+	t := ResponseTool{
+		Type:        ResponseToolTypeFunction,
+		Name:        fn.Name,
+		Description: fn.Description,
+		Parameters:  fn.Parameters,
+	}
+	if fn.Strict {
+		t.Strict = &fn.Strict
+	}
+	return t
+}
+
+// Creates a web search tool.
+func NewWebSearchTool() ResponseTool {
+	return ResponseTool{Type: ResponseToolTypeWebSearch}
+}
+
+// Creates a file search tool over the given vector stores.
+func NewFileSearchTool(vectorStoreIDs ...string) ResponseTool {
+	return ResponseTool{Type: ResponseToolTypeFileSearch, VectorStoreIDs: vectorStoreIDs}
+}
+
+// Creates a code interpreter tool running in an automatically created container.
+func NewCodeInterpreterTool() ResponseTool {
+	return ResponseTool{Type: ResponseToolTypeCodeInterpreter, Container: map[string]string{"type": "auto"}}
+}
+
+// Reasoning settings of o-series and gpt-5 models.
+type ResponseReasoning struct {
+	// One of the ReasoningEffort constants.
+	Effort string `json:"effort,omitempty"`
+	// "auto", "concise" or "detailed" to include a summary of the reasoning.
+	Summary string `json:"summary,omitempty"`
+}
+
+// Format of the text output: "text", "json_object" or "json_schema".
+type ResponseTextFormat struct {
+	Type        string      `json:"type"`
+	Name        string      `json:"name,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Schema      interface{} `json:"schema,omitempty"`
+	Strict      *bool       `json:"strict,omitempty"`
+}
+
+type ResponseTextConfig struct {
+	Format *ResponseTextFormat `json:"format,omitempty"`
+}
+
+type CreateResponseRequest struct {
+	Model string `json:"model"`
+	// A string or a []ResponseItem.
+	Input        interface{} `json:"input,omitempty"`
+	Instructions string      `json:"instructions,omitempty"`
+	// Continues the conversation of a stored response without resending it.
+	PreviousResponseID string         `json:"previous_response_id,omitempty"`
+	Tools              []ResponseTool `json:"tools,omitempty"`
+	// "none", "auto", "required" or an object selecting a tool.
+	ToolChoice        interface{}         `json:"tool_choice,omitempty"`
+	ParallelToolCalls *bool               `json:"parallel_tool_calls,omitempty"`
+	Reasoning         *ResponseReasoning  `json:"reasoning,omitempty"`
+	Text              *ResponseTextConfig `json:"text,omitempty"`
+	MaxOutputTokens   int                 `json:"max_output_tokens,omitempty"`
+	Temperature       *float32            `json:"temperature,omitempty"`
+	TopP              *float32            `json:"top_p,omitempty"`
+	// Responses are stored unless Store is false.
+	Store      *bool `json:"store,omitempty"`
+	Background bool  `json:"background,omitempty"`
+	// "auto" or "disabled".
+	Truncation string            `json:"truncation,omitempty"`
+	Include    []string          `json:"include,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	User       string            `json:"user,omitempty"`
+	// Set by CreateResponseStream.
+	Stream bool `json:"stream,omitempty"`
+}
+
+// Generates the correct http.Request object for the given API Request Struct.
+func (crr *CreateResponseRequest) GenerateHTTPRequest(ctx context.Context) (*http.Request, error) {
+	return newJSONRequest(ctx, http.MethodPost, "responses", crr)
+}
+
+type ResponseError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type ResponseIncompleteDetails struct {
+	Reason string `json:"reason"`
+}
+
+type ResponseInputTokensDetails struct {
+	CachedTokens int `json:"cached_tokens"`
+}
+
+type ResponseOutputTokensDetails struct {
+	ReasoningTokens int `json:"reasoning_tokens"`
+}
+
+type ResponseUsage struct {
+	InputTokens         int                         `json:"input_tokens"`
+	InputTokensDetails  ResponseInputTokensDetails  `json:"input_tokens_details"`
+	OutputTokens        int                         `json:"output_tokens"`
+	OutputTokensDetails ResponseOutputTokensDetails `json:"output_tokens_details"`
+	TotalTokens         int                         `json:"total_tokens"`
+}
+
+// Converts the usage into the chat completion Usage, e.g. for a UsageTracker.
+func (u ResponseUsage) ChatUsage() Usage {
+	return Usage{
+		PromptTokens:            u.InputTokens,
+		CompletionTokens:        u.OutputTokens,
+		TotalTokens:             u.TotalTokens,
+		PromptTokensDetails:     PromptTokensDetails{CachedTokens: u.InputTokensDetails.CachedTokens},
+		CompletionTokensDetails: CompletionTokensDetails{ReasoningTokens: u.OutputTokensDetails.ReasoningTokens},
+	}
+}
+
+type Response struct {
+	ID                 string                     `json:"id"`
+	Object             string                     `json:"object"`
+	CreatedAt          int64                      `json:"created_at"`
+	Status             string                     `json:"status"`
+	Error              *ResponseError             `json:"error,omitempty"`
+	IncompleteDetails  *ResponseIncompleteDetails `json:"incomplete_details,omitempty"`
+	Model              string                     `json:"model"`
+	Output             []ResponseItem             `json:"output"`
+	PreviousResponseID string                     `json:"previous_response_id,omitempty"`
+	Instructions       interface{}                `json:"instructions,omitempty"`
+	Tools              []ResponseTool             `json:"tools,omitempty"`
+	Reasoning          *ResponseReasoning         `json:"reasoning,omitempty"`
+	Text               *ResponseTextConfig        `json:"text,omitempty"`
+	Usage              *ResponseUsage             `json:"usage,omitempty"`
+	Metadata           map[string]string          `json:"metadata,omitempty"`
+	User               string                     `json:"user,omitempty"`
+}
+
+// Returns the concatenated text of the output messages.
+func (r Response) OutputText() string {
+	var sb strings.Builder
+	for _, item := range r.Output {
+		if item.Type == ResponseItemTypeMessage {
+			sb.WriteString(item.Text())
+		}
+	}
+	return sb.String()
+}
+
+// Returns the function calls of the output.
+func (r Response) FunctionCalls() []ResponseItem {
+	var calls []ResponseItem
+	for _, item := range r.Output {
+		if item.Type == ResponseItemTypeFunctionCall {
+			calls = append(calls, item)
+		}
+	}
+	return calls
+}
+
+func (c *Client) recordResponseUsage(ctx context.Context, res Response) {
+	if c.usage == nil || res.Usage == nil {
+		return
+	}
+	c.usage.RecordChat(ctx, res.Model, res.User, res.Usage.ChatUsage())
+}
+
+// Utilizes the CreateResponse OpenAI API.
+//
+// @Returns openai.Response.
+func (c *Client) CreateResponse(ctx context.Context, respReq *CreateResponseRequest) (Response, error) {
+	var res Response
+	if respReq.Stream {
+		return res, errStreamNotSupported
+	}
+	req, err := respReq.GenerateHTTPRequest(ctx)
+	if err != nil {
+		return res, err
+	}
+	if err = c.SendRequest(req, &res); err != nil {
+		return res, err
+	}
+	c.recordResponseUsage(ctx, res)
+	return res, nil
+}
+
+// Utilizes the GetResponse OpenAI API to retrieve a stored response.
+//
+// @Returns openai.Response.
+func (c *Client) GetResponse(ctx context.Context, responseID string) (Response, error) {
+	var res Response
+	err := c.sendJSON(ctx, http.MethodGet, "responses/"+responseID, nil, &res)
+	return res, err
+}
+
+// Utilizes the DeleteResponse OpenAI API.
+//
+// @Returns openai.DeletionStatus.
+func (c *Client) DeleteResponse(ctx context.Context, responseID string) (DeletionStatus, error) {
+	var res DeletionStatus
+	err := c.sendJSON(ctx, http.MethodDelete, "responses/"+responseID, nil, &res)
+	return res, err
+}
+
+// Utilizes the CancelResponse OpenAI API. Only responses created with Background can be cancelled.
+//
+// @Returns the cancelled openai.Response.
+func (c *Client) CancelResponse(ctx context.Context, responseID string) (Response, error) {
+	var res Response
+	err := c.sendJSON(ctx, http.MethodPost, "responses/"+responseID+"/cancel", nil, &res)
+	return res, err
+}
+
+// Utilizes the ListInputItems OpenAI API. params may be nil.
+//
+// @Returns a page of the input items of a response.
+func (c *Client) ListResponseInputItems(ctx context.Context, responseID string, params *ListParams) (List[ResponseItem], error) {
+	var res List[ResponseItem]
+	err := c.sendJSON(ctx, http.MethodGet, params.encode("responses/"+responseID+"/input_items"), nil, &res)
+	return res, err
+}
+
+// Iterates over every input item of the response, fetching the pages as needed. params may be nil.
+func (c *Client) ResponseInputItems(ctx context.Context, responseID string, params *ListParams) iter.Seq2[ResponseItem, error] {
+	return paginate(ctx, params, func(ctx context.Context, params *ListParams) (List[ResponseItem], error) {
+		return c.ListResponseInputItems(ctx, responseID, params)
+	})
+}
@@ -0,0 +1,178 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	. "github.com/EthanCampana/go-openai"
+)
+
+func TestClient_CreateResponse(t *testing.T) {
+	var body map[string]interface{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/responses" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		b, _ := io.ReadAll(r.Body)
+		json.Unmarshal(b, &body)
+		fmt.Fprint(w, `{"id":"resp_2","object":"response","status":"completed","model":"o4-mini",`+
+			`"output":[{"type":"reasoning","id":"rs_1","summary":[]},`+
+			`{"type":"function_call","id":"fc_1","call_id":"call_1","name":"lookup","arguments":"{\"sku\":\"A1\"}"},`+
+			`{"type":"message","id":"msg_1","role":"assistant","content":[{"type":"output_text","text":"Checking.","annotations":[]}]}],`+
+			`"usage":{"input_tokens":10,"output_tokens":20,"output_tokens_details":{"reasoning_tokens":5},"total_tokens":30}}`)
+	})
+	tracker := NewUsageTracker()
+	client.SetUsageTracker(tracker)
+
+	tool, err := NewStrictFunctionTool("lookup", "Looks up a product", struct {
+		SKU string `json:"sku"`
+	}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := client.CreateResponse(context.Background(), &CreateResponseRequest{
+		Model:              "o4-mini",
+		PreviousResponseID: "resp_1",
+		Input:              []ResponseItem{NewFunctionCallOutput("call_0", "42"), NewResponseMessage(ChatMessageRoleUser, "and A1?")},
+		Tools:              []ResponseTool{NewResponseFunctionTool(tool), NewWebSearchTool()},
+		Reasoning:          &ResponseReasoning{Effort: ReasoningEffortLow},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if body["previous_response_id"] != "resp_1" {
+		t.Errorf("previous_response_id = %v", body["previous_response_id"])
+	}
+	if reasoning, _ := body["reasoning"].(map[string]interface{}); reasoning["effort"] != "low" {
+		t.Errorf("reasoning = %v", body["reasoning"])
+	}
+	tools, _ := json.Marshal(body["tools"])
+	if !strings.Contains(string(tools), `"name":"lookup"`) || !strings.Contains(string(tools), `{"type":"web_search_preview"}`) {
+		t.Errorf("tools = %s", tools)
+	}
+	input, _ := json.Marshal(body["input"])
+	wantInput := `[{"call_id":"call_0","output":"42","type":"function_call_output"},` +
+		`{"content":[{"text":"and A1?","type":"input_text"}],"role":"user","type":"message"}]`
+	if string(input) != wantInput {
+		t.Errorf("input = %s, want %s", input, wantInput)
+	}
+
+	if res.OutputText() != "Checking." {
+		t.Errorf("OutputText() = %q", res.OutputText())
+	}
+	calls := res.FunctionCalls()
+	var args struct {
+		SKU string `json:"sku"`
+	}
+	if len(calls) != 1 || calls[0].DecodeArguments(&args) != nil || args.SKU != "A1" {
+		t.Errorf("FunctionCalls() = %+v, args %+v", calls, args)
+	}
+	if total := tracker.Total(); total.PromptTokens != 10 || total.ReasoningTokens != 5 {
+		t.Errorf("tracked usage = %+v", total)
+	}
+}
+
+func TestClient_CreateResponseStream(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req CreateResponseRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if !req.Stream {
+			t.Error("request was sent without stream")
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		events := []string{
+			`{"type":"response.created","sequence_number":0,"response":{"id":"resp_1","status":"in_progress","output":[]}}`,
+			`{"type":"response.output_item.added","sequence_number":1,"output_index":0,"item":{"type":"message","id":"msg_1","role":"assistant","content":[]}}`,
+			`{"type":"response.output_text.delta","sequence_number":2,"item_id":"msg_1","output_index":0,"content_index":0,"delta":"Hel"}`,
+			`{"type":"response.output_text.delta","sequence_number":3,"item_id":"msg_1","output_index":0,"content_index":0,"delta":"lo"}`,
+			`{"type":"response.completed","sequence_number":4,"response":{"id":"resp_1","status":"completed","output":[` +
+				`{"type":"message","id":"msg_1","role":"assistant","content":[{"type":"output_text","text":"Hello"}]}]}}`,
+		}
+		for _, ev := range events {
+			var typed struct{ Type string }
+			json.Unmarshal([]byte(ev), &typed)
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", typed.Type, ev)
+		}
+	})
+	stream, err := client.CreateResponseStream(context.Background(), &CreateResponseRequest{Model: "gpt-4.1", Input: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	var text strings.Builder
+	var types []string
+	for {
+		ev, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		types = append(types, ev.Type)
+		switch d := ev.Details.(type) {
+		case *ResponseDeltaEvent:
+			if d.ItemID != "msg_1" {
+				t.Errorf("delta item id = %q", d.ItemID)
+			}
+			text.WriteString(d.Delta)
+		case *ResponseOutputItemEvent:
+			if d.Item.ID != "msg_1" {
+				t.Errorf("output_item.added item = %+v", d.Item)
+			}
+		case *ResponseLifecycleEvent:
+			if ev.Type == ResponseEventCompleted && d.Response.OutputText() != "Hello" {
+				t.Errorf("completed response text = %q", d.Response.OutputText())
+			}
+		default:
+			t.Errorf("event %s decoded as %T", ev.Type, ev.Details)
+		}
+	}
+	if text.String() != "Hello" || len(types) != 5 {
+		t.Errorf("streamed %q from events %v", text.String(), types)
+	}
+}
+
+func TestResponseStream_Error(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "event: error\ndata: {\"type\":\"error\",\"code\":\"server_error\",\"message\":\"overloaded\",\"param\":\"input\"}\n\n")
+	})
+	stream, err := client.CreateResponseStream(context.Background(), &CreateResponseRequest{Model: "gpt-4.1", Input: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+	_, err = stream.Collect()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 0 || apiErr.Code != "server_error" || apiErr.Message != "overloaded" || apiErr.Param != "input" {
+		t.Errorf("Collect() error = %v, want the error event as *APIError", err)
+	}
+}
+
+func TestResponseStream_Failed(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "event: response.failed\ndata: {\"type\":\"response.failed\",\"response\":{\"id\":\"resp_1\",\"status\":\"failed\","+
+			"\"error\":{\"code\":\"rate_limit_exceeded\",\"message\":\"slow down\"}}}\n\n")
+	})
+	stream, err := client.CreateResponseStream(context.Background(), &CreateResponseRequest{Model: "gpt-4.1", Input: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+	res, err := stream.Collect()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != ResponseEventFailed || apiErr.Code != "rate_limit_exceeded" || apiErr.Message != "slow down" {
+		t.Errorf("Collect() error = %#v, want the response error as *APIError", err)
+	}
+	if res.ID != "resp_1" || res.Status != ResponseStatusFailed {
+		t.Errorf("Collect() response = %+v, want the failed response", res)
+	}
+}
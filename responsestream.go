@@ -0,0 +1,238 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Types of the events of a streamed response.
+const (
+	ResponseEventCreated                   = "response.created"
+	ResponseEventInProgress                = "response.in_progress"
+	ResponseEventCompleted                 = "response.completed"
+	ResponseEventFailed                    = "response.failed"
+	ResponseEventIncomplete                = "response.incomplete"
+	ResponseEventQueued                    = "response.queued"
+	ResponseEventOutputItemAdded           = "response.output_item.added"
+	ResponseEventOutputItemDone            = "response.output_item.done"
+	ResponseEventContentPartAdded          = "response.content_part.added"
+	ResponseEventContentPartDone           = "response.content_part.done"
+	ResponseEventOutputTextDelta           = "response.output_text.delta"
+	ResponseEventOutputTextDone            = "response.output_text.done"
+	ResponseEventRefusalDelta              = "response.refusal.delta"
+	ResponseEventRefusalDone               = "response.refusal.done"
+	ResponseEventFunctionCallArgsDelta     = "response.function_call_arguments.delta"
+	ResponseEventFunctionCallArgsDone      = "response.function_call_arguments.done"
+	ResponseEventReasoningSummaryTextDelta = "response.reasoning_summary_text.delta"
+	ResponseEventReasoningSummaryTextDone  = "response.reasoning_summary_text.done"
+	ResponseEventError                     = "error"
+)
+
+// An event of a streamed response. Details holds the event decoded by its Type:
+//
+//   - response.*: *ResponseLifecycleEvent, the final response on completed, failed and incomplete.
+//   - response.output_item.*: *ResponseOutputItemEvent.
+//   - response.content_part.*: *ResponseContentPartEvent.
+//   - *.delta: *ResponseDeltaEvent.
+//   - response.output_text.done and response.reasoning_summary_text.done: *ResponseTextDoneEvent.
+//   - response.refusal.done: *ResponseRefusalDoneEvent.
+//   - response.function_call_arguments.done: *ResponseFunctionCallArgumentsDoneEvent.
+//
+// Details is nil for other types, Payload holds the raw event of every type.
+type ResponseStreamEvent struct {
+	Type           string          `json:"type"`
+	SequenceNumber int             `json:"sequence_number"`
+	Payload        json.RawMessage `json:"-"`
+	Details        interface{}     `json:"-"`
+}
+
+type ResponseLifecycleEvent struct {
+	Response Response `json:"response"`
+}
+
+type ResponseOutputItemEvent struct {
+	OutputIndex int          `json:"output_index"`
+	Item        ResponseItem `json:"item"`
+}
+
+type ResponseContentPartEvent struct {
+	ItemID       string          `json:"item_id"`
+	OutputIndex  int             `json:"output_index"`
+	ContentIndex int             `json:"content_index"`
+	Part         ResponseContent `json:"part"`
+}
+
+// A delta of text, a refusal, function call arguments or a reasoning summary.
+type ResponseDeltaEvent struct {
+	ItemID       string `json:"item_id"`
+	OutputIndex  int    `json:"output_index"`
+	ContentIndex int    `json:"content_index"`
+	SummaryIndex int    `json:"summary_index"`
+	Delta        string `json:"delta"`
+}
+
+type ResponseTextDoneEvent struct {
+	ItemID       string `json:"item_id"`
+	OutputIndex  int    `json:"output_index"`
+	ContentIndex int    `json:"content_index"`
+	SummaryIndex int    `json:"summary_index"`
+	Text         string `json:"text"`
+}
+
+type ResponseRefusalDoneEvent struct {
+	ItemID       string `json:"item_id"`
+	OutputIndex  int    `json:"output_index"`
+	ContentIndex int    `json:"content_index"`
+	Refusal      string `json:"refusal"`
+}
+
+type ResponseFunctionCallArgumentsDoneEvent struct {
+	ItemID      string `json:"item_id"`
+	OutputIndex int    `json:"output_index"`
+	Arguments   string `json:"arguments"`
+}
+
+func (e *ResponseStreamEvent) UnmarshalJSON(data []byte) error {
+	type responseStreamEvent ResponseStreamEvent
+	if err := json.Unmarshal(data, (*responseStreamEvent)(e)); err != nil {
+		return err
+	}
+	e.Payload = append(json.RawMessage(nil), data...)
+	e.Details = nil
+	var details interface{}
+	switch {
+	case strings.HasSuffix(e.Type, ".delta"):
+		details = &ResponseDeltaEvent{}
+	case e.Type == ResponseEventOutputTextDone || e.Type == ResponseEventReasoningSummaryTextDone:
+		details = &ResponseTextDoneEvent{}
+	case e.Type == ResponseEventRefusalDone:
+		details = &ResponseRefusalDoneEvent{}
+	case e.Type == ResponseEventFunctionCallArgsDone:
+		details = &ResponseFunctionCallArgumentsDoneEvent{}
+	case strings.HasPrefix(e.Type, "response.output_item."):
+		details = &ResponseOutputItemEvent{}
+	case strings.HasPrefix(e.Type, "response.content_part."):
+		details = &ResponseContentPartEvent{}
+	case isLifecycleResponseEvent(e.Type):
+		details = &ResponseLifecycleEvent{}
+	default:
+		return nil
+	}
+	if err := json.Unmarshal(data, details); err != nil {
+		return err
+	}
+	e.Details = details
+	return nil
+}
+
+// Returns the response of the response.* lifecycle events, nil for other events.
+func (e ResponseStreamEvent) Response() *Response {
+	if lc, ok := e.Details.(*ResponseLifecycleEvent); ok {
+		return &lc.Response
+	}
+	return nil
+}
+
+func isLifecycleResponseEvent(t string) bool {
+	switch t {
+	case ResponseEventCreated, ResponseEventInProgress, ResponseEventQueued,
+		ResponseEventCompleted, ResponseEventFailed, ResponseEventIncomplete:
+		return true
+	}
+	return false
+}
+
+// A stream of response events. Close must be called once done with the stream.
+type ResponseStream struct {
+	body    io.ReadCloser
+	sse     *sseReader
+	onUsage func(res Response)
+}
+
+// Utilizes the CreateResponse OpenAI API with stream enabled.
+//
+// @Returns openai.ResponseStream to read the events from.
+func (c *Client) CreateResponseStream(ctx context.Context, respReq *CreateResponseRequest) (*ResponseStream, error) {
+	streamReq := *respReq
+	streamReq.Stream = true
+	req, err := streamReq.GenerateHTTPRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	return &ResponseStream{
+		body: res.Body,
+		sse:  newSSEReader(res.Body),
+		onUsage: func(res Response) {
+			c.recordResponseUsage(ctx, res)
+		},
+	}, nil
+}
+
+// Returns the next event of the stream or io.EOF once the stream is finished.
+// error events are returned as *APIError with a StatusCode of 0.
+func (s *ResponseStream) Recv() (ResponseStreamEvent, error) {
+	var ev ResponseStreamEvent
+	_, data, err := s.sse.Next()
+	if err != nil {
+		return ev, err
+	}
+	if err = json.Unmarshal(data, &ev); err != nil {
+		return ev, err
+	}
+	if ev.Type == ResponseEventError {
+		var errEvent struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+			Param   string `json:"param"`
+		}
+		json.Unmarshal(data, &errEvent)
+		return ev, &APIError{Code: errEvent.Code, Message: errEvent.Message, Param: errEvent.Param}
+	}
+	if res := ev.Response(); res != nil && s.onUsage != nil && isFinalResponseEvent(ev.Type) {
+		s.onUsage(*res)
+	}
+	return ev, nil
+}
+
+func isFinalResponseEvent(t string) bool {
+	return t == ResponseEventCompleted || t == ResponseEventFailed || t == ResponseEventIncomplete
+}
+
+// Reads the remaining events and returns the final response.
+// A failed response is returned together with an *APIError carrying the code and message
+// of Response.Error, its Type is ResponseEventFailed and its StatusCode 0.
+func (s *ResponseStream) Collect() (Response, error) {
+	var res Response
+	for {
+		ev, err := s.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return res, err
+		}
+		if r := ev.Response(); r != nil {
+			res = *r
+		}
+	}
+	if res.ID == "" {
+		return res, errors.New("stream ended without a response")
+	}
+	if res.Status == ResponseStatusFailed && res.Error != nil {
+		return res, &APIError{Type: ResponseEventFailed, Code: res.Error.Code, Message: res.Error.Message}
+	}
+	return res, nil
+}
+
+// Closes the underlying response body.
+func (s *ResponseStream) Close() error {
+	return s.body.Close()
+}
@@ -0,0 +1,316 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"time"
+)
+
+const (
+	RunStatusQueued         = "queued"
+	RunStatusInProgress     = "in_progress"
+	RunStatusRequiresAction = "requires_action"
+	RunStatusCancelling     = "cancelling"
+	RunStatusCancelled      = "cancelled"
+	RunStatusFailed         = "failed"
+	RunStatusCompleted      = "completed"
+	RunStatusIncomplete     = "incomplete"
+	RunStatusExpired        = "expired"
+)
+
+const (
+	RunStepTypeMessageCreation = "message_creation"
+	RunStepTypeToolCalls       = "tool_calls"
+)
+
+type RunError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type RunIncompleteDetails struct {
+	Reason string `json:"reason"`
+}
+
+type RunSubmitToolOutputs struct {
+	ToolCalls []ToolCall `json:"tool_calls"`
+}
+
+// The action a run waits for when its status is RunStatusRequiresAction.
+type RunRequiredAction struct {
+	Type              string                `json:"type"`
+	SubmitToolOutputs *RunSubmitToolOutputs `json:"submit_tool_outputs,omitempty"`
+}
+
+// How the thread is truncated before a run, "auto" or "last_messages".
+type RunTruncationStrategy struct {
+	Type         string `json:"type"`
+	LastMessages int    `json:"last_messages,omitempty"`
+}
+
+type Run struct {
+	ID                  string                 `json:"id"`
+	Object              string                 `json:"object"`
+	CreatedAt           int64                  `json:"created_at"`
+	ThreadID            string                 `json:"thread_id"`
+	AssistantID         string                 `json:"assistant_id"`
+	Status              string                 `json:"status"`
+	RequiredAction      *RunRequiredAction     `json:"required_action,omitempty"`
+	LastError           *RunError              `json:"last_error,omitempty"`
+	ExpiresAt           int64                  `json:"expires_at,omitempty"`
+	StartedAt           int64                  `json:"started_at,omitempty"`
+	CancelledAt         int64                  `json:"cancelled_at,omitempty"`
+	FailedAt            int64                  `json:"failed_at,omitempty"`
+	CompletedAt         int64                  `json:"completed_at,omitempty"`
+	IncompleteDetails   *RunIncompleteDetails  `json:"incomplete_details,omitempty"`
+	Model               string                 `json:"model"`
+	Instructions        string                 `json:"instructions,omitempty"`
+	Tools               []AssistantTool        `json:"tools,omitempty"`
+	Metadata            map[string]string      `json:"metadata,omitempty"`
+	Usage               *Usage                 `json:"usage,omitempty"`
+	Temperature         *float32               `json:"temperature,omitempty"`
+	TopP                *float32               `json:"top_p,omitempty"`
+	MaxPromptTokens     int                    `json:"max_prompt_tokens,omitempty"`
+	MaxCompletionTokens int                    `json:"max_completion_tokens,omitempty"`
+	TruncationStrategy  *RunTruncationStrategy `json:"truncation_strategy,omitempty"`
+	ToolChoice          *ToolChoice            `json:"tool_choice,omitempty"`
+	ParallelToolCalls   *bool                  `json:"parallel_tool_calls,omitempty"`
+	ResponseFormat      interface{}            `json:"response_format,omitempty"`
+}
+
+// Reports whether the run reached a final status and will not change anymore.
+func (r Run) Done() bool {
+	switch r.Status {
+	case RunStatusCancelled, RunStatusFailed, RunStatusCompleted, RunStatusIncomplete, RunStatusExpired:
+		return true
+	}
+	return false
+}
+
+// Returns the tool calls the run waits for, nil if it does not require an action.
+func (r Run) ToolCalls() []ToolCall {
+	if r.Status != RunStatusRequiresAction || r.RequiredAction == nil || r.RequiredAction.SubmitToolOutputs == nil {
+		return nil
+	}
+	return r.RequiredAction.SubmitToolOutputs.ToolCalls
+}
+
+// Creates a run of an assistant on a thread. Fields left empty use the settings of the assistant.
+type RunRequest struct {
+	AssistantID            string                 `json:"assistant_id"`
+	Model                  string                 `json:"model,omitempty"`
+	Instructions           string                 `json:"instructions,omitempty"`
+	AdditionalInstructions string                 `json:"additional_instructions,omitempty"`
+	AdditionalMessages     []MessageRequest       `json:"additional_messages,omitempty"`
+	Tools                  []AssistantTool        `json:"tools,omitempty"`
+	Metadata               map[string]string      `json:"metadata,omitempty"`
+	Temperature            *float32               `json:"temperature,omitempty"`
+	TopP                   *float32               `json:"top_p,omitempty"`
+	MaxPromptTokens        int                    `json:"max_prompt_tokens,omitempty"`
+	MaxCompletionTokens    int                    `json:"max_completion_tokens,omitempty"`
+	TruncationStrategy     *RunTruncationStrategy `json:"truncation_strategy,omitempty"`
+	ToolChoice             *ToolChoice            `json:"tool_choice,omitempty"`
+	ParallelToolCalls      *bool                  `json:"parallel_tool_calls,omitempty"`
+	ResponseFormat         interface{}            `json:"response_format,omitempty"`
+	// Set by the Stream methods.
+	Stream bool `json:"stream,omitempty"`
+}
+
+// Creates a thread and runs it in one request.
+type ThreadAndRunRequest struct {
+	RunRequest
+	Thread *ThreadRequest `json:"thread,omitempty"`
+}
+
+// The output of a tool call, submitted with SubmitToolOutputs.
+type ToolOutput struct {
+	ToolCallID string `json:"tool_call_id"`
+	Output     string `json:"output"`
+}
+
+type submitToolOutputsRequest struct {
+	ToolOutputs []ToolOutput `json:"tool_outputs"`
+	Stream      bool         `json:"stream,omitempty"`
+}
+
+type RunStepMessageCreation struct {
+	MessageID string `json:"message_id"`
+}
+
+type RunStepFunction struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+	Output    string `json:"output,omitempty"`
+}
+
+type CodeInterpreterOutput struct {
+	Type  string                `json:"type"`
+	Logs  string                `json:"logs,omitempty"`
+	Image *MessageFileReference `json:"image,omitempty"`
+}
+
+type CodeInterpreterCall struct {
+	Input   string                  `json:"input"`
+	Outputs []CodeInterpreterOutput `json:"outputs,omitempty"`
+}
+
+// A tool call made during a run step. Index is only set on stream deltas.
+type RunStepToolCall struct {
+	Index           *int                 `json:"index,omitempty"`
+	ID              string               `json:"id,omitempty"`
+	Type            string               `json:"type"`
+	Function        *RunStepFunction     `json:"function,omitempty"`
+	CodeInterpreter *CodeInterpreterCall `json:"code_interpreter,omitempty"`
+	FileSearch      json.RawMessage      `json:"file_search,omitempty"`
+}
+
+type RunStepDetails struct {
+	Type            string                  `json:"type"`
+	MessageCreation *RunStepMessageCreation `json:"message_creation,omitempty"`
+	ToolCalls       []RunStepToolCall       `json:"tool_calls,omitempty"`
+}
+
+// A step of an assistant run, either creating a message or calling tools.
+type ThreadRunStep struct {
+	ID          string         `json:"id"`
+	Object      string         `json:"object"`
+	CreatedAt   int64          `json:"created_at"`
+	AssistantID string         `json:"assistant_id"`
+	ThreadID    string         `json:"thread_id"`
+	RunID       string         `json:"run_id"`
+	Type        string         `json:"type"`
+	Status      string         `json:"status"`
+	StepDetails RunStepDetails `json:"step_details"`
+	LastError   *RunError      `json:"last_error,omitempty"`
+	ExpiredAt   int64          `json:"expired_at,omitempty"`
+	CancelledAt int64          `json:"cancelled_at,omitempty"`
+	FailedAt    int64          `json:"failed_at,omitempty"`
+	CompletedAt int64          `json:"completed_at,omitempty"`
+	Usage       *Usage         `json:"usage,omitempty"`
+}
+
+func runPath(threadID, runID string) string {
+	return "threads/" + threadID + "/runs/" + runID
+}
+
+// Utilizes the CreateRun OpenAI API to start a run of an assistant on a thread.
+//
+// @Returns the created openai.Run, use WaitForRun to wait for its completion.
+func (c *Client) CreateRun(ctx context.Context, threadID string, runReq *RunRequest) (Run, error) {
+	var res Run
+	if runReq.Stream {
+		return res, errStreamNotSupported
+	}
+	err := c.sendJSON(ctx, http.MethodPost, "threads/"+threadID+"/runs", runReq, &res)
+	return res, err
+}
+
+// Utilizes the CreateThreadAndRun OpenAI API.
+//
+// @Returns the created openai.Run, its ThreadID identifies the new thread.
+func (c *Client) CreateThreadAndRun(ctx context.Context, runReq *ThreadAndRunRequest) (Run, error) {
+	var res Run
+	if runReq.Stream {
+		return res, errStreamNotSupported
+	}
+	err := c.sendJSON(ctx, http.MethodPost, "threads/runs", runReq, &res)
+	return res, err
+}
+
+// Utilizes the RetrieveRun OpenAI API.
+//
+// @Returns openai.Run.
+func (c *Client) GetRun(ctx context.Context, threadID, runID string) (Run, error) {
+	var res Run
+	err := c.sendJSON(ctx, http.MethodGet, runPath(threadID, runID), nil, &res)
+	return res, err
+}
+
+// Utilizes the ModifyRun OpenAI API to replace the metadata of a run.
+//
+// @Returns the modified openai.Run.
+func (c *Client) ModifyRun(ctx context.Context, threadID, runID string, metadata map[string]string) (Run, error) {
+	var res Run
+	err := c.sendJSON(ctx, http.MethodPost, runPath(threadID, runID), metadataRequest{metadata}, &res)
+	return res, err
+}
+
+// Utilizes the ListRuns OpenAI API. params may be nil.
+//
+// @Returns a page of openai.Run.
+func (c *Client) ListRuns(ctx context.Context, threadID string, params *ListParams) (List[Run], error) {
+	var res List[Run]
+	err := c.sendJSON(ctx, http.MethodGet, params.encode("threads/"+threadID+"/runs"), nil, &res)
+	return res, err
+}
+
+// Iterates over every openai.Run of the thread, fetching the pages as needed. params may be nil.
+func (c *Client) Runs(ctx context.Context, threadID string, params *ListParams) iter.Seq2[Run, error] {
+	return paginate(ctx, params, func(ctx context.Context, params *ListParams) (List[Run], error) {
+		return c.ListRuns(ctx, threadID, params)
+	})
+}
+
+// Utilizes the CancelRun OpenAI API.
+//
+// @Returns the openai.Run, usually with status cancelling.
+func (c *Client) CancelRun(ctx context.Context, threadID, runID string) (Run, error) {
+	var res Run
+	err := c.sendJSON(ctx, http.MethodPost, runPath(threadID, runID)+"/cancel", nil, &res)
+	return res, err
+}
+
+// Utilizes the SubmitToolOutputs OpenAI API to answer the tool calls of a run with
+// status RunStatusRequiresAction. All calls must be answered in one request.
+//
+// @Returns the resumed openai.Run.
+func (c *Client) SubmitToolOutputs(ctx context.Context, threadID, runID string, outputs []ToolOutput) (Run, error) {
+	var res Run
+	err := c.sendJSON(ctx, http.MethodPost, runPath(threadID, runID)+"/submit_tool_outputs",
+		submitToolOutputsRequest{ToolOutputs: outputs}, &res)
+	return res, err
+}
+
+// Utilizes the ListRunSteps OpenAI API. params may be nil.
+//
+// @Returns a page of openai.ThreadRunStep.
+func (c *Client) ListRunSteps(ctx context.Context, threadID, runID string, params *ListParams) (List[ThreadRunStep], error) {
+	var res List[ThreadRunStep]
+	err := c.sendJSON(ctx, http.MethodGet, params.encode(runPath(threadID, runID)+"/steps"), nil, &res)
+	return res, err
+}
+
+// Iterates over every openai.ThreadRunStep of the run, fetching the pages as needed. params may be nil.
+func (c *Client) RunSteps(ctx context.Context, threadID, runID string, params *ListParams) iter.Seq2[ThreadRunStep, error] {
+	return paginate(ctx, params, func(ctx context.Context, params *ListParams) (List[ThreadRunStep], error) {
+		return c.ListRunSteps(ctx, threadID, runID, params)
+	})
+}
+
+// Utilizes the RetrieveRunStep OpenAI API.
+//
+// @Returns openai.ThreadRunStep.
+func (c *Client) GetRunStep(ctx context.Context, threadID, runID, stepID string) (ThreadRunStep, error) {
+	var res ThreadRunStep
+	err := c.sendJSON(ctx, http.MethodGet, runPath(threadID, runID)+"/steps/"+stepID, nil, &res)
+	return res, err
+}
+
+// Polls the run every interval until it is done or requires an action. An interval of 0
+// uses DefaultPollInterval. Cancelling ctx stops the polling, not the run. The usage of a
+// finished run is recorded by the UsageTracker of the client.
+//
+// @Returns the last retrieved openai.Run, check its Status and ToolCalls.
+func (c *Client) WaitForRun(ctx context.Context, threadID, runID string, interval time.Duration) (Run, error) {
+	var run Run
+	err := poll(ctx, interval, func() (done bool, err error) {
+		run, err = c.GetRun(ctx, threadID, runID)
+		return run.Done() || run.Status == RunStatusRequiresAction, err
+	})
+	if err == nil {
+		c.recordRunUsage(ctx, run)
+	}
+	return run, err
+}
@@ -0,0 +1,323 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Server sent events of a streamed assistant run.
+const (
+	RunEventThreadCreated     = "thread.created"
+	RunEventRunCreated        = "thread.run.created"
+	RunEventRunQueued         = "thread.run.queued"
+	RunEventRunInProgress     = "thread.run.in_progress"
+	RunEventRunRequiresAction = "thread.run.requires_action"
+	RunEventRunCompleted      = "thread.run.completed"
+	RunEventRunIncomplete     = "thread.run.incomplete"
+	RunEventRunFailed         = "thread.run.failed"
+	RunEventRunCancelling     = "thread.run.cancelling"
+	RunEventRunCancelled      = "thread.run.cancelled"
+	RunEventRunExpired        = "thread.run.expired"
+	RunEventRunStepCreated    = "thread.run.step.created"
+	RunEventRunStepInProgress = "thread.run.step.in_progress"
+	RunEventRunStepDelta      = "thread.run.step.delta"
+	RunEventRunStepCompleted  = "thread.run.step.completed"
+	RunEventRunStepFailed     = "thread.run.step.failed"
+	RunEventRunStepCancelled  = "thread.run.step.cancelled"
+	RunEventRunStepExpired    = "thread.run.step.expired"
+	RunEventMessageCreated    = "thread.message.created"
+	RunEventMessageInProgress = "thread.message.in_progress"
+	RunEventMessageDelta      = "thread.message.delta"
+	RunEventMessageCompleted  = "thread.message.completed"
+	RunEventMessageIncomplete = "thread.message.incomplete"
+	RunEventError             = "error"
+	runEventPrefixRun         = "thread.run."
+	runEventPrefixRunStep     = "thread.run.step."
+	runEventPrefixMessage     = "thread.message."
+)
+
+// An incremental change of a message, Index identifies the content part it belongs to.
+type MessageDeltaContent struct {
+	Index int `json:"index"`
+	MessageContent
+}
+
+type MessageDelta struct {
+	ID     string `json:"id"`
+	Object string `json:"object"`
+	Delta  struct {
+		Role    string                `json:"role,omitempty"`
+		Content []MessageDeltaContent `json:"content,omitempty"`
+	} `json:"delta"`
+}
+
+// Returns the text added by the delta.
+func (d MessageDelta) Text() string {
+	var text string
+	for _, c := range d.Delta.Content {
+		if c.Text != nil {
+			text += c.Text.Value
+		}
+	}
+	return text
+}
+
+type ThreadRunStepDelta struct {
+	ID     string `json:"id"`
+	Object string `json:"object"`
+	Delta  struct {
+		StepDetails RunStepDetails `json:"step_details"`
+	} `json:"delta"`
+}
+
+// A single event of a streamed run. Data holds the JSON object named by Event.
+type RunStreamEvent struct {
+	Event string
+	Data  json.RawMessage
+}
+
+// Decodes the run of a thread.run.* event.
+func (e RunStreamEvent) Run() (Run, error) {
+	var run Run
+	return run, e.decode(runEventPrefixRun, &run)
+}
+
+// Decodes the step of a thread.run.step.* event other than the delta.
+func (e RunStreamEvent) RunStep() (ThreadRunStep, error) {
+	var step ThreadRunStep
+	return step, e.decode(runEventPrefixRunStep, &step)
+}
+
+// Decodes a thread.run.step.delta event.
+func (e RunStreamEvent) RunStepDelta() (ThreadRunStepDelta, error) {
+	var delta ThreadRunStepDelta
+	return delta, e.decode(RunEventRunStepDelta, &delta)
+}
+
+// Decodes the message of a thread.message.* event other than the delta.
+func (e RunStreamEvent) Message() (Message, error) {
+	var msg Message
+	return msg, e.decode(runEventPrefixMessage, &msg)
+}
+
+// Decodes a thread.message.delta event.
+func (e RunStreamEvent) MessageDelta() (MessageDelta, error) {
+	var delta MessageDelta
+	return delta, e.decode(RunEventMessageDelta, &delta)
+}
+
+func (e RunStreamEvent) decode(prefix string, v interface{}) error {
+	if !strings.HasPrefix(e.Event, prefix) {
+		return fmt.Errorf("event %s does not match %s", e.Event, prefix)
+	}
+	return json.Unmarshal(e.Data, v)
+}
+
+func (e RunStreamEvent) isRun() bool {
+	return strings.HasPrefix(e.Event, runEventPrefixRun) && !strings.HasPrefix(e.Event, runEventPrefixRunStep)
+}
+
+// A stream of run events. Close must be called once done with the stream.
+type RunStream struct {
+	client *Client
+	ctx    context.Context
+	body   io.ReadCloser
+	sse    *sseReader
+}
+
+// Utilizes the CreateRun OpenAI API with stream enabled.
+//
+// @Returns openai.RunStream to read the events from.
+func (c *Client) CreateRunStream(ctx context.Context, threadID string, runReq *RunRequest) (*RunStream, error) {
+	streamReq := *runReq
+	streamReq.Stream = true
+	return c.openRunStream(ctx, "threads/"+threadID+"/runs", &streamReq)
+}
+
+// Utilizes the CreateThreadAndRun OpenAI API with stream enabled.
+//
+// @Returns openai.RunStream to read the events from, the thread.created event holds the new thread.
+func (c *Client) CreateThreadAndRunStream(ctx context.Context, runReq *ThreadAndRunRequest) (*RunStream, error) {
+	streamReq := *runReq
+	streamReq.Stream = true
+	return c.openRunStream(ctx, "threads/runs", &streamReq)
+}
+
+// Utilizes the SubmitToolOutputs OpenAI API with stream enabled.
+//
+// @Returns openai.RunStream continuing the run.
+func (c *Client) SubmitToolOutputsStream(ctx context.Context, threadID, runID string, outputs []ToolOutput) (*RunStream, error) {
+	body := submitToolOutputsRequest{ToolOutputs: outputs, Stream: true}
+	return c.openRunStream(ctx, runPath(threadID, runID)+"/submit_tool_outputs", body)
+}
+
+func (c *Client) openRunStream(ctx context.Context, path string, body interface{}) (*RunStream, error) {
+	req, err := newJSONRequest(ctx, http.MethodPost, path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	return &RunStream{client: c, ctx: ctx, body: res.Body, sse: newSSEReader(res.Body)}, nil
+}
+
+// Returns the next event of the stream or io.EOF once the stream is finished.
+// error events are returned as *APIError with a StatusCode of 0. The usage of the run is
+// recorded by the UsageTracker of the client once the run finishes.
+func (s *RunStream) Recv() (RunStreamEvent, error) {
+	event, data, err := s.sse.Next()
+	if err != nil {
+		return RunStreamEvent{}, err
+	}
+	if event == RunEventError {
+		return RunStreamEvent{}, newRunStreamError(data)
+	}
+	ev := RunStreamEvent{Event: event, Data: data}
+	if ev.isRun() && s.client.usage != nil {
+		if run, err := ev.Run(); err == nil {
+			s.client.recordRunUsage(s.ctx, run)
+		}
+	}
+	return ev, nil
+}
+
+// Parses the data of an error event, which is either an error object or wrapped in an
+// "error" field like the error bodies of the API.
+func newRunStreamError(data []byte) *APIError {
+	apiErr := newAPIError(0, data)
+	if apiErr.Message != "" {
+		return apiErr
+	}
+	var errObj struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Param   string `json:"param"`
+		Type    string `json:"type"`
+	}
+	if json.Unmarshal(data, &errObj) == nil && errObj.Message != "" {
+		return &APIError{Type: errObj.Type, Code: errObj.Code, Message: errObj.Message, Param: errObj.Param}
+	}
+	return &APIError{Message: string(data)}
+}
+
+// Closes the underlying response body.
+func (s *RunStream) Close() error {
+	return s.body.Close()
+}
+
+// Callbacks of RunStream.Consume. Every field is optional.
+type RunEventHandler struct {
+	// Called for every event before the typed callbacks.
+	OnEvent func(event RunStreamEvent) error
+	// Called with the text of every message delta.
+	OnTextDelta        func(messageID, text string) error
+	OnMessageCompleted func(msg Message) error
+	OnRunStepCompleted func(step ThreadRunStep) error
+	// Answers the tool calls of a run requiring an action. When it is nil the calls are
+	// executed by Tools; if both are nil Consume returns the run waiting for the action.
+	OnRequiresAction func(ctx context.Context, run Run) ([]ToolOutput, error)
+	Tools            *ToolRunner
+}
+
+// Reads the stream until the run finishes. Runs requiring an action are answered through
+// the handler and continued on a new stream, so a single call drives the whole run.
+// The stream is closed when Consume returns.
+//
+// @Returns the last openai.Run received.
+func (s *RunStream) Consume(h RunEventHandler) (Run, error) {
+	var run Run
+	stream := s
+	defer func() { stream.Close() }()
+	for {
+		ev, err := stream.Recv()
+		if err == io.EOF {
+			if run.Status != RunStatusRequiresAction {
+				return run, nil
+			}
+			if h.OnRequiresAction == nil && h.Tools == nil {
+				return run, nil
+			}
+			outputs, err := h.answer(stream.ctx, run)
+			if err != nil {
+				return run, err
+			}
+			next, err := stream.client.SubmitToolOutputsStream(stream.ctx, run.ThreadID, run.ID, outputs)
+			if err != nil {
+				return run, err
+			}
+			stream.Close()
+			stream = next
+			continue
+		}
+		if err != nil {
+			return run, err
+		}
+		if err = h.handle(ev, &run); err != nil {
+			return run, err
+		}
+	}
+}
+
+func (h RunEventHandler) handle(ev RunStreamEvent, run *Run) error {
+	if h.OnEvent != nil {
+		if err := h.OnEvent(ev); err != nil {
+			return err
+		}
+	}
+	switch {
+	case ev.isRun():
+		r, err := ev.Run()
+		if err != nil {
+			return err
+		}
+		*run = r
+	case ev.Event == RunEventMessageDelta && h.OnTextDelta != nil:
+		delta, err := ev.MessageDelta()
+		if err != nil {
+			return err
+		}
+		if text := delta.Text(); text != "" {
+			return h.OnTextDelta(delta.ID, text)
+		}
+	case ev.Event == RunEventMessageCompleted && h.OnMessageCompleted != nil:
+		msg, err := ev.Message()
+		if err != nil {
+			return err
+		}
+		return h.OnMessageCompleted(msg)
+	case ev.Event == RunEventRunStepCompleted && h.OnRunStepCompleted != nil:
+		step, err := ev.RunStep()
+		if err != nil {
+			return err
+		}
+		return h.OnRunStepCompleted(step)
+	}
+	return nil
+}
+
+func (h RunEventHandler) answer(ctx context.Context, run Run) ([]ToolOutput, error) {
+	if h.OnRequiresAction != nil {
+		return h.OnRequiresAction(ctx, run)
+	}
+	calls := run.ToolCalls()
+	if len(calls) == 0 {
+		return nil, errors.New("run requires an action without tool calls")
+	}
+	results, err := h.Tools.dispatch(ctx, calls)
+	if err != nil {
+		return nil, err
+	}
+	outputs := make([]ToolOutput, len(results))
+	for i, res := range results {
+		outputs[i] = ToolOutput{ToolCallID: res.ToolCallID, Output: res.Content}
+	}
+	return outputs, nil
+}
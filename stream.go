@@ -0,0 +1,88 @@
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+var errStreamNotSupported = errors.New("stream requests must be sent with the matching Stream method")
+
+var sseDone = []byte("[DONE]")
+
+// Reads server sent events from an API response body.
+type sseReader struct {
+	r *bufio.Reader
+}
+
+func newSSEReader(r io.Reader) *sseReader {
+	return &sseReader{r: bufio.NewReader(r)}
+}
+
+// Returns the next event. Returns io.EOF once the stream ends or the [DONE] sentinel is received.
+func (s *sseReader) Next() (event string, data []byte, err error) {
+	var buf bytes.Buffer
+	hasData := false
+	for {
+		line, err := s.r.ReadBytes('\n')
+		if err != nil && !(err == io.EOF && len(line) > 0) {
+			if err == io.EOF && hasData {
+				break
+			}
+			return "", nil, err
+		}
+		line = bytes.TrimRight(line, "\r\n")
+		switch {
+		case len(line) == 0:
+			if hasData {
+				return s.dispatch(event, buf.Bytes())
+			}
+			continue
+		case line[0] == ':':
+			continue
+		}
+		field, value, _ := bytes.Cut(line, []byte(":"))
+		value = bytes.TrimPrefix(value, []byte(" "))
+		switch string(field) {
+		case "event":
+			event = string(value)
+		case "data":
+			if hasData {
+				buf.WriteByte('\n')
+			}
+			buf.Write(value)
+			hasData = true
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	if !hasData {
+		return "", nil, io.EOF
+	}
+	return s.dispatch(event, buf.Bytes())
+}
+
+func (s *sseReader) dispatch(event string, data []byte) (string, []byte, error) {
+	if bytes.Equal(data, sseDone) {
+		return "", nil, io.EOF
+	}
+	if isErrorEvent(data) {
+		// The error arrives in band, there is no status code of its own.
+		return event, nil, newAPIError(0, data)
+	}
+	return event, data, nil
+}
+
+// Reports whether data is an API error object, {"error": {...}}.
+func isErrorEvent(data []byte) bool {
+	if !bytes.HasPrefix(data, []byte(`{"error"`)) {
+		return false
+	}
+	var errResp struct {
+		Error json.RawMessage `json:"error"`
+	}
+	return json.Unmarshal(data, &errResp) == nil && len(errResp.Error) > 0 && !bytes.Equal(errResp.Error, []byte("null"))
+}
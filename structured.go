@@ -0,0 +1,115 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+// RefusalError is returned when the model refused to answer a structured output request.
+type RefusalError struct {
+	Refusal string
+}
+
+func (e *RefusalError) Error() string {
+	return fmt.Sprintf("model refused the request: %s", e.Refusal)
+}
+
+// TruncatedOutputError is returned when the structured output was cut off, e.g. by max_tokens
+// (finish_reason length) or the content filter.
+type TruncatedOutputError struct {
+	FinishReason string
+	Content      string
+}
+
+func (e *TruncatedOutputError) Error() string {
+	return fmt.Sprintf("structured output is incomplete, finish reason: %s", e.FinishReason)
+}
+
+// SchemaViolationError is returned when the content does not match the schema of the target type.
+type SchemaViolationError struct {
+	Content string
+	Err     error
+}
+
+func (e *SchemaViolationError) Error() string {
+	return fmt.Sprintf("structured output does not match the schema: %v", e.Err)
+}
+
+func (e *SchemaViolationError) Unwrap() error {
+	return e.Err
+}
+
+var schemaNameReplacer = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// Sends the chat request with a strict json_schema response format generated from T and
+// decodes the assistant message into T. The passed request is not modified.
+//
+// @Returns a *RefusalError, *TruncatedOutputError or *SchemaViolationError when the model
+// did not produce a valid T.
+func CreateChatCompletionAs[T any](ctx context.Context, c *Client, chatReq *ChatCompletionRequest) (T, error) {
+	var out T
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	schema, err := SchemaReflector{Strict: true}.ReflectType(t)
+	if err != nil {
+		return out, err
+	}
+	req := *chatReq
+	req.ResponseFormat = &ChatResponseFormat{
+		Type: ResponseFormatJSONSchema,
+		JSONSchema: &ChatResponseFormatJSONSchema{
+			Name:   schemaName(t),
+			Schema: schema,
+			Strict: true,
+		},
+	}
+	res, err := c.CreateChatCompletion(ctx, &req)
+	if err != nil {
+		return out, err
+	}
+	if len(res.Choices) == 0 {
+		return out, errors.New("response contains no choices")
+	}
+	err = DecodeStructuredOutput(res.Choices[0], schema, &out)
+	return out, err
+}
+
+// Decodes the message of a structured output choice into v and validates it against schema.
+func DecodeStructuredOutput(choice ChatCompletionChoice, schema *JSONSchema, v interface{}) error {
+	msg := choice.Message
+	if msg.Refusal != "" {
+		return &RefusalError{Refusal: msg.Refusal}
+	}
+	if choice.FinishReason == FinishReasonLength || choice.FinishReason == FinishReasonContentFilter {
+		return &TruncatedOutputError{FinishReason: choice.FinishReason, Content: msg.Content}
+	}
+	var raw interface{}
+	if err := json.Unmarshal([]byte(msg.Content), &raw); err != nil {
+		return &SchemaViolationError{Content: msg.Content, Err: err}
+	}
+	if schema != nil {
+		if err := schema.Validate(raw); err != nil {
+			return &SchemaViolationError{Content: msg.Content, Err: err}
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader([]byte(msg.Content)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return &SchemaViolationError{Content: msg.Content, Err: err}
+	}
+	return nil
+}
+
+func schemaName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Name() == "" {
+		return "response"
+	}
+	return schemaNameReplacer.ReplaceAllString(t.Name(), "_")
+}
@@ -0,0 +1,66 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	. "github.com/EthanCampana/go-openai"
+)
+
+type weatherReport struct {
+	City        string  `json:"city"`
+	Temperature float64 `json:"temperature"`
+	Unit        string  `json:"unit" jsonschema:"enum=celsius|fahrenheit"`
+}
+
+func TestCreateChatCompletionAs(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		finish  string
+		wantErr interface{}
+	}{
+		{name: "Valid", message: `{"content":"{\"city\":\"Paris\",\"temperature\":21.5,\"unit\":\"celsius\"}"}`, finish: "stop"},
+		{name: "Refusal", message: `{"refusal":"I can't help with that"}`, finish: "stop", wantErr: new(*RefusalError)},
+		{name: "Truncated", message: `{"content":"{\"city\":\"Par"}`, finish: "length", wantErr: new(*TruncatedOutputError)},
+		{name: "Enum violation", message: `{"content":"{\"city\":\"Paris\",\"temperature\":21.5,\"unit\":\"kelvin\"}"}`, finish: "stop", wantErr: new(*SchemaViolationError)},
+		{name: "Missing field", message: `{"content":"{\"city\":\"Paris\",\"unit\":\"celsius\"}"}`, finish: "stop", wantErr: new(*SchemaViolationError)},
+		{name: "Invalid JSON", message: `{"content":"Paris is sunny"}`, finish: "stop", wantErr: new(*SchemaViolationError)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				var req ChatCompletionRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatal(err)
+				}
+				rf := req.ResponseFormat
+				if rf == nil || rf.Type != ResponseFormatJSONSchema || rf.JSONSchema.Name != "weatherReport" || !rf.JSONSchema.Strict {
+					t.Errorf("response format was not set: %+v", rf)
+				}
+				fmt.Fprintf(w, `{"choices":[{"index":0,"finish_reason":%q,"message":%s}]}`, tt.finish, tt.message)
+			})
+			req := &ChatCompletionRequest{Model: "gpt-4o", Messages: []ChatMessage{{Role: ChatMessageRoleUser, Content: "Weather in Paris?"}}}
+			got, err := CreateChatCompletionAs[weatherReport](context.Background(), c, req)
+			if req.ResponseFormat != nil {
+				t.Error("CreateChatCompletionAs() modified the request")
+			}
+			if tt.wantErr != nil {
+				if !errors.As(err, tt.wantErr) {
+					t.Errorf("CreateChatCompletionAs() error = %v, want %T", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := (weatherReport{City: "Paris", Temperature: 21.5, Unit: "celsius"}); got != want {
+				t.Errorf("CreateChatCompletionAs() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
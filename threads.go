@@ -0,0 +1,202 @@
+package openai
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"strings"
+)
+
+const (
+	MessageContentTypeText      = "text"
+	MessageContentTypeImageFile = "image_file"
+	MessageContentTypeImageURL  = "image_url"
+	MessageContentTypeRefusal   = "refusal"
+)
+
+type Thread struct {
+	ID            string            `json:"id"`
+	Object        string            `json:"object"`
+	CreatedAt     int64             `json:"created_at"`
+	ToolResources *ToolResources    `json:"tool_resources,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+// Creates a thread, optionally seeded with messages, or modifies one. Messages are ignored by ModifyThread.
+type ThreadRequest struct {
+	Messages      []MessageRequest  `json:"messages,omitempty"`
+	ToolResources *ToolResources    `json:"tool_resources,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+// A file attached to a message and the tools it is made available to.
+type MessageAttachment struct {
+	FileID string          `json:"file_id"`
+	Tools  []AssistantTool `json:"tools,omitempty"`
+}
+
+// Creates a message. Role is ChatMessageRoleUser or ChatMessageRoleAssistant.
+type MessageRequest struct {
+	Role        string              `json:"role"`
+	Content     string              `json:"content"`
+	Attachments []MessageAttachment `json:"attachments,omitempty"`
+	Metadata    map[string]string   `json:"metadata,omitempty"`
+}
+
+type MessageFileReference struct {
+	FileID string `json:"file_id"`
+}
+
+// A citation or file path inside the text of a message.
+type MessageAnnotation struct {
+	Type         string                `json:"type"`
+	Text         string                `json:"text"`
+	StartIndex   int                   `json:"start_index"`
+	EndIndex     int                   `json:"end_index"`
+	FileCitation *MessageFileReference `json:"file_citation,omitempty"`
+	FilePath     *MessageFileReference `json:"file_path,omitempty"`
+}
+
+type MessageText struct {
+	Value       string              `json:"value"`
+	Annotations []MessageAnnotation `json:"annotations,omitempty"`
+}
+
+type MessageImageFile struct {
+	FileID string `json:"file_id"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// A single content part of a message, see the MessageContentType constants.
+type MessageContent struct {
+	Type      string               `json:"type"`
+	Text      *MessageText         `json:"text,omitempty"`
+	ImageFile *MessageImageFile    `json:"image_file,omitempty"`
+	ImageURL  *ChatMessageImageURL `json:"image_url,omitempty"`
+	Refusal   string               `json:"refusal,omitempty"`
+}
+
+type Message struct {
+	ID          string              `json:"id"`
+	Object      string              `json:"object"`
+	CreatedAt   int64               `json:"created_at"`
+	ThreadID    string              `json:"thread_id"`
+	Status      string              `json:"status,omitempty"`
+	Role        string              `json:"role"`
+	Content     []MessageContent    `json:"content"`
+	AssistantID string              `json:"assistant_id,omitempty"`
+	RunID       string              `json:"run_id,omitempty"`
+	Attachments []MessageAttachment `json:"attachments,omitempty"`
+	Metadata    map[string]string   `json:"metadata,omitempty"`
+}
+
+// Returns the concatenated text parts of the message.
+func (m Message) Text() string {
+	var sb strings.Builder
+	for _, c := range m.Content {
+		if c.Text != nil {
+			sb.WriteString(c.Text.Value)
+		}
+	}
+	return sb.String()
+}
+
+type metadataRequest struct {
+	Metadata map[string]string `json:"metadata"`
+}
+
+// Utilizes the CreateThread OpenAI API. threadReq may be nil to create an empty thread.
+//
+// @Returns the created openai.Thread.
+func (c *Client) CreateThread(ctx context.Context, threadReq *ThreadRequest) (Thread, error) {
+	var res Thread
+	if threadReq == nil {
+		threadReq = &ThreadRequest{}
+	}
+	err := c.sendJSON(ctx, http.MethodPost, "threads", threadReq, &res)
+	return res, err
+}
+
+// Utilizes the RetrieveThread OpenAI API.
+//
+// @Returns openai.Thread.
+func (c *Client) GetThread(ctx context.Context, threadID string) (Thread, error) {
+	var res Thread
+	err := c.sendJSON(ctx, http.MethodGet, "threads/"+threadID, nil, &res)
+	return res, err
+}
+
+// Utilizes the ModifyThread OpenAI API to update the tool resources and metadata of a thread.
+// threadReq may be nil, which leaves the thread unchanged.
+//
+// @Returns the modified openai.Thread.
+func (c *Client) ModifyThread(ctx context.Context, threadID string, threadReq *ThreadRequest) (Thread, error) {
+	var res Thread
+	if threadReq == nil {
+		threadReq = &ThreadRequest{}
+	}
+	body := ThreadRequest{ToolResources: threadReq.ToolResources, Metadata: threadReq.Metadata}
+	err := c.sendJSON(ctx, http.MethodPost, "threads/"+threadID, body, &res)
+	return res, err
+}
+
+// Utilizes the DeleteThread OpenAI API.
+//
+// @Returns openai.DeletionStatus.
+func (c *Client) DeleteThread(ctx context.Context, threadID string) (DeletionStatus, error) {
+	var res DeletionStatus
+	err := c.sendJSON(ctx, http.MethodDelete, "threads/"+threadID, nil, &res)
+	return res, err
+}
+
+// Utilizes the CreateMessage OpenAI API to add a message to a thread.
+//
+// @Returns the created openai.Message.
+func (c *Client) CreateMessage(ctx context.Context, threadID string, msgReq *MessageRequest) (Message, error) {
+	var res Message
+	err := c.sendJSON(ctx, http.MethodPost, "threads/"+threadID+"/messages", msgReq, &res)
+	return res, err
+}
+
+// Utilizes the RetrieveMessage OpenAI API.
+//
+// @Returns openai.Message.
+func (c *Client) GetMessage(ctx context.Context, threadID, messageID string) (Message, error) {
+	var res Message
+	err := c.sendJSON(ctx, http.MethodGet, "threads/"+threadID+"/messages/"+messageID, nil, &res)
+	return res, err
+}
+
+// Utilizes the ModifyMessage OpenAI API to replace the metadata of a message.
+//
+// @Returns the modified openai.Message.
+func (c *Client) ModifyMessage(ctx context.Context, threadID, messageID string, metadata map[string]string) (Message, error) {
+	var res Message
+	err := c.sendJSON(ctx, http.MethodPost, "threads/"+threadID+"/messages/"+messageID, metadataRequest{metadata}, &res)
+	return res, err
+}
+
+// Utilizes the DeleteMessage OpenAI API.
+//
+// @Returns openai.DeletionStatus.
+func (c *Client) DeleteMessage(ctx context.Context, threadID, messageID string) (DeletionStatus, error) {
+	var res DeletionStatus
+	err := c.sendJSON(ctx, http.MethodDelete, "threads/"+threadID+"/messages/"+messageID, nil, &res)
+	return res, err
+}
+
+// Utilizes the ListMessages OpenAI API. params may be nil.
+//
+// @Returns a page of openai.Message, newest first unless params set another order.
+func (c *Client) ListMessages(ctx context.Context, threadID string, params *ListParams) (List[Message], error) {
+	var res List[Message]
+	err := c.sendJSON(ctx, http.MethodGet, params.encode("threads/"+threadID+"/messages"), nil, &res)
+	return res, err
+}
+
+// Iterates over every openai.Message of the thread, fetching the pages as needed. params may be nil.
+func (c *Client) Messages(ctx context.Context, threadID string, params *ListParams) iter.Seq2[Message, error] {
+	return paginate(ctx, params, func(ctx context.Context, params *ListParams) (List[Message], error) {
+		return c.ListMessages(ctx, threadID, params)
+	})
+}
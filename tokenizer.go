@@ -0,0 +1,339 @@
+package openai
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	EncodingCL100kBase = "cl100k_base"
+	EncodingO200kBase  = "o200k_base"
+)
+
+// TokenizerDir is searched for <encoding>.tiktoken vocabulary files by GetEncoding.
+// It defaults to the OPENAI_TOKENIZER_DIR environment variable. The files use the
+// tiktoken format: one base64 encoded token and its rank per line.
+var TokenizerDir = os.Getenv("OPENAI_TOKENIZER_DIR")
+
+// Unicode White_Space, Go's \s only covers ASCII.
+const wsClass = `\t\n\x0b\f\r \x{85}\x{a0}\x{1680}\x{2000}-\x{200a}\x{2028}\x{2029}\x{202f}\x{205f}\x{3000}`
+
+// The pre-tokenization patterns of tiktoken. Go regexp has no lookahead, so the trailing
+// `\s+(?!\S)` alternative is emulated by Encoding.Split.
+var encodingPatterns = map[string]string{
+	EncodingCL100kBase: `(?i:'s|'t|'re|'ve|'m|'ll|'d)` +
+		`|[^\r\n\pL\pN]?\pL+` +
+		`|\pN{1,3}` +
+		`| ?[^WS\pL\pN]+[\r\n]*` +
+		`|[WS]*[\r\n]+` +
+		`|[WS]+`,
+	EncodingO200kBase: `[^\r\n\pL\pN]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\pM]*[\p{Ll}\p{Lm}\p{Lo}\pM]+(?i:'s|'t|'re|'ve|'m|'ll|'d)?` +
+		`|[^\r\n\pL\pN]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\pM]+[\p{Ll}\p{Lm}\p{Lo}\pM]*(?i:'s|'t|'re|'ve|'m|'ll|'d)?` +
+		`|\pN{1,3}` +
+		`| ?[^WS\pL\pN]+[\r\n/]*` +
+		`|[WS]*[\r\n]+` +
+		`|[WS]+`,
+}
+
+var encodingSpecialTokens = map[string]map[string]int{
+	EncodingCL100kBase: {
+		"<|endoftext|>":   100257,
+		"<|fim_prefix|>":  100258,
+		"<|fim_middle|>":  100259,
+		"<|fim_suffix|>":  100260,
+		"<|endofprompt|>": 100276,
+	},
+	EncodingO200kBase: {
+		"<|endoftext|>":   199999,
+		"<|endofprompt|>": 200018,
+	},
+}
+
+// An Encoding is a byte pair encoding tokenizer such as cl100k_base or o200k_base.
+type Encoding struct {
+	Name    string
+	pattern *regexp.Regexp
+	ranks   map[string]int
+	tokens  map[int]string
+	special map[string]int
+}
+
+var (
+	encodingsMu sync.Mutex
+	encodings   = map[string]*Encoding{}
+)
+
+// Parses a vocabulary in the tiktoken format for the named encoding.
+// name must be cl100k_base or o200k_base, which determines the pre-tokenization and special tokens.
+func LoadEncoding(name string, r io.Reader) (*Encoding, error) {
+	pattern, ok := encodingPatterns[name]
+	if !ok {
+		return nil, fmt.Errorf("%s is not a supported encoding", name)
+	}
+	pattern = strings.ReplaceAll(pattern, "WS", wsClass)
+	enc := &Encoding{
+		Name:    name,
+		pattern: regexp.MustCompile(`\A(?:` + pattern + `)`),
+		ranks:   map[string]int{},
+		tokens:  map[int]string{},
+		special: encodingSpecialTokens[name],
+	}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		token, rank, ok := strings.Cut(text, " ")
+		if !ok {
+			return nil, fmt.Errorf("%s line %d: expected token and rank", name, line)
+		}
+		b, err := base64.StdEncoding.DecodeString(token)
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", name, line, err)
+		}
+		id, err := strconv.Atoi(rank)
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", name, line, err)
+		}
+		enc.ranks[string(b)] = id
+		enc.tokens[id] = string(b)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	for i := 0; i < 256; i++ {
+		if _, ok := enc.ranks[string([]byte{byte(i)})]; !ok {
+			return nil, fmt.Errorf("%s vocabulary is missing the single byte token %#x", name, i)
+		}
+	}
+	return enc, nil
+}
+
+// Loads the named encoding from a tiktoken vocabulary file.
+func LoadEncodingFile(name, path string) (*Encoding, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadEncoding(name, f)
+}
+
+// Makes enc available through GetEncoding, replacing any encoding with the same name.
+func RegisterEncoding(enc *Encoding) {
+	encodingsMu.Lock()
+	defer encodingsMu.Unlock()
+	encodings[enc.Name] = enc
+}
+
+// Returns a registered encoding, loading <TokenizerDir>/<name>.tiktoken on first use.
+func GetEncoding(name string) (*Encoding, error) {
+	encodingsMu.Lock()
+	defer encodingsMu.Unlock()
+	if enc, ok := encodings[name]; ok {
+		return enc, nil
+	}
+	if TokenizerDir == "" {
+		return nil, fmt.Errorf("encoding %s is not registered and TokenizerDir is not set", name)
+	}
+	enc, err := LoadEncodingFile(name, filepath.Join(TokenizerDir, name+".tiktoken"))
+	if err != nil {
+		return nil, err
+	}
+	encodings[name] = enc
+	return enc, nil
+}
+
+// Returns the name of the encoding used by model.
+func EncodingForModel(model string) string {
+	for _, prefix := range []string{"gpt-4o", "gpt-4.1", "gpt-4.5", "gpt-5", "o1", "o3", "o4", "chatgpt-4o"} {
+		if strings.HasPrefix(model, prefix) {
+			return EncodingO200kBase
+		}
+	}
+	return EncodingCL100kBase
+}
+
+// Splits text into the pieces that are byte pair encoded independently.
+func (e *Encoding) Split(text string) []string {
+	var pieces []string
+	for len(text) > 0 {
+		loc := e.pattern.FindStringIndex(text)
+		end := utf8RuneLen(text)
+		if loc != nil && loc[1] > 0 {
+			end = loc[1]
+		}
+		end = trimTrailingSpace(text, end)
+		pieces = append(pieces, text[:end])
+		text = text[end:]
+	}
+	return pieces
+}
+
+// Emulates `\s+(?!\S)`: a whitespace run followed by a non space character leaves its
+// last character to the next piece, so " world" stays one piece.
+func trimTrailingSpace(text string, end int) int {
+	if end >= len(text) {
+		return end
+	}
+	piece := text[:end]
+	last, size := utf8.DecodeLastRuneInString(piece)
+	if size == len(piece) || last == '\r' || last == '\n' {
+		return end
+	}
+	for _, r := range piece {
+		if !unicode.IsSpace(r) {
+			return end
+		}
+	}
+	return end - size
+}
+
+func utf8RuneLen(text string) int {
+	_, size := utf8.DecodeRuneInString(text)
+	return size
+}
+
+// Encodes text into token ids. Special tokens in the text are encoded as ordinary text.
+func (e *Encoding) Encode(text string) []int {
+	var ids []int
+	for _, piece := range e.Split(text) {
+		if id, ok := e.ranks[piece]; ok {
+			ids = append(ids, id)
+			continue
+		}
+		ids = append(ids, e.bytePairEncode(piece)...)
+	}
+	return ids
+}
+
+// Returns the number of tokens of text.
+func (e *Encoding) Count(text string) int {
+	return len(e.Encode(text))
+}
+
+// Decodes token ids back into text, including special tokens.
+func (e *Encoding) Decode(ids []int) (string, error) {
+	var sb strings.Builder
+	for _, id := range ids {
+		if token, ok := e.tokens[id]; ok {
+			sb.WriteString(token)
+			continue
+		}
+		found := false
+		for token, special := range e.special {
+			if special == id {
+				sb.WriteString(token)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", fmt.Errorf("unknown token id %d", id)
+		}
+	}
+	return sb.String(), nil
+}
+
+// Merges the bytes of piece, always merging the adjacent pair with the lowest rank first,
+// the leftmost one on ties. The candidate pairs are kept in a heap, so a piece of n bytes
+// takes O(n log n) instead of rescanning every pair after each merge.
+func (e *Encoding) bytePairEncode(piece string) []int {
+	n := len(piece)
+	// The parts form a linked list of their start offsets; next[i] is n for the last part.
+	next := make([]int, n)
+	prev := make([]int, n)
+	for i := range next {
+		next[i], prev[i] = i+1, i-1
+	}
+	merged := make([]bool, n)
+	pairs := &mergeHeap{}
+	push := func(start int) {
+		if start < 0 || next[start] >= n {
+			return
+		}
+		end := next[next[start]]
+		if rank, ok := e.ranks[piece[start:end]]; ok {
+			heap.Push(pairs, bpeMerge{rank: rank, start: start, end: end})
+		}
+	}
+	for i := 0; i < n-1; i++ {
+		push(i)
+	}
+	for pairs.Len() > 0 {
+		m := heap.Pop(pairs).(bpeMerge)
+		// Skip pairs changed by an earlier merge.
+		if merged[m.start] || next[m.start] >= n || next[next[m.start]] != m.end {
+			continue
+		}
+		merged[next[m.start]] = true
+		next[m.start] = m.end
+		if m.end < n {
+			prev[m.end] = m.start
+		}
+		push(prev[m.start])
+		push(m.start)
+	}
+	var ids []int
+	for i := 0; i < n; i = next[i] {
+		ids = append(ids, e.ranks[piece[i:next[i]]])
+	}
+	return ids
+}
+
+// A candidate merge of the parts spanning piece[start:end].
+type bpeMerge struct {
+	rank, start, end int
+}
+
+// Min-heap of merges ordered by rank, then by position.
+type mergeHeap []bpeMerge
+
+func (h mergeHeap) Len() int { return len(h) }
+func (h mergeHeap) Less(i, j int) bool {
+	if h[i].rank != h[j].rank {
+		return h[i].rank < h[j].rank
+	}
+	return h[i].start < h[j].start
+}
+func (h mergeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *mergeHeap) Push(x interface{}) { *h = append(*h, x.(bpeMerge)) }
+func (h *mergeHeap) Pop() interface{} {
+	old := *h
+	m := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return m
+}
+
+// Counts the prompt tokens of messages for model, including the per message overhead of the API.
+func (e *Encoding) CountChatTokens(model string, messages []ChatMessage) (int, error) {
+	return countChatTokens(model, messages, e.Count), nil
+}
+
+// BPETokenCounter is a TokenCounter using the encoding of each model, see GetEncoding.
+type BPETokenCounter struct{}
+
+func (BPETokenCounter) CountChatTokens(model string, messages []ChatMessage) (int, error) {
+	return CountChatTokens(model, messages)
+}
+
+// Counts the prompt tokens of messages with the encoding of model, see GetEncoding.
+func CountChatTokens(model string, messages []ChatMessage) (int, error) {
+	enc, err := GetEncoding(EncodingForModel(model))
+	if err != nil {
+		return 0, err
+	}
+	return enc.CountChatTokens(model, messages)
+}
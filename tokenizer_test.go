@@ -0,0 +1,155 @@
+package openai_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/EthanCampana/go-openai"
+)
+
+// Builds a tiny vocabulary: every single byte plus a few merges.
+func testEncoding(t *testing.T, name string) *Encoding {
+	t.Helper()
+	var sb strings.Builder
+	for i := 0; i < 256; i++ {
+		fmt.Fprintf(&sb, "%s %d\n", base64.StdEncoding.EncodeToString([]byte{byte(i)}), i)
+	}
+	merges := []string{"He", "ll", "llo", "Hello", " w", "or", " wor", "ld", " world"}
+	for i, m := range merges {
+		fmt.Fprintf(&sb, "%s %d\n", base64.StdEncoding.EncodeToString([]byte(m)), 256+i)
+	}
+	enc, err := LoadEncoding(name, strings.NewReader(sb.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return enc
+}
+
+func TestEncoding_Split(t *testing.T) {
+	tests := []struct {
+		encoding string
+		text     string
+		want     []string
+	}{
+		{EncodingCL100kBase, "Hello world", []string{"Hello", " world"}},
+		{EncodingCL100kBase, "I'm  here\n\nok 12345!!", []string{"I", "'m", " ", " here", "\n\n", "ok", " ", "123", "45", "!!"}},
+		{EncodingCL100kBase, "don't", []string{"don", "'t"}},
+		{EncodingCL100kBase, "a　　b  ", []string{"a", "　", "　b", "  "}},
+		{EncodingO200kBase, "don't", []string{"don't"}},
+		{EncodingO200kBase, "HelloWorld path/to\n", []string{"Hello", "World", " path", "/to", "\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.encoding+" "+tt.text, func(t *testing.T) {
+			if got := testEncoding(t, tt.encoding).Split(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Encoding.Split() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncoding_EncodeDecode(t *testing.T) {
+	enc := testEncoding(t, EncodingCL100kBase)
+	tests := []struct {
+		text string
+		want []int
+	}{
+		{"Hello world", []int{259, 264}},
+		{"Hellos", []int{259, 's'}},
+		{"hello", []int{'h', 'e', 258}},
+		{"héllo", []int{'h', 0xc3, 0xa9, 258}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := enc.Encode(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Encoding.Encode() = %v, want %v", got, tt.want)
+			}
+			if text, err := enc.Decode(got); err != nil || text != tt.text {
+				t.Errorf("Encoding.Decode() = %q, %v", text, err)
+			}
+		})
+	}
+	if text, err := enc.Decode([]int{259, 100257}); err != nil || text != "Hello<|endoftext|>" {
+		t.Errorf("Encoding.Decode() special = %q, %v", text, err)
+	}
+	if _, err := enc.Decode([]int{99999}); err == nil {
+		t.Error("Encoding.Decode() expected error for unknown id")
+	}
+}
+
+func TestEncoding_EncodeLongPiece(t *testing.T) {
+	enc := testEncoding(t, EncodingCL100kBase)
+	got := enc.Encode(strings.Repeat("Hello", 20000))
+	if len(got) != 20000 || got[0] != 259 || got[len(got)-1] != 259 {
+		t.Errorf("Encoding.Encode() of a long piece = %d tokens, want 20000 Hello tokens", len(got))
+	}
+}
+
+// Token ids produced by tiktoken. Set OPENAI_TOKENIZER_DIR to a directory with the
+// cl100k_base.tiktoken and o200k_base.tiktoken vocabularies to run them.
+func TestEncoding_Golden(t *testing.T) {
+	if TokenizerDir == "" {
+		t.Skip("OPENAI_TOKENIZER_DIR is not set")
+	}
+	tests := []struct {
+		encoding string
+		text     string
+		want     []int
+	}{
+		{EncodingCL100kBase, "hello world", []int{15339, 1917}},
+		{EncodingCL100kBase, "tiktoken is great!", []int{83, 1609, 5963, 374, 2294, 0}},
+		{EncodingCL100kBase, "antidisestablishmentarianism", []int{519, 85342, 34500, 479, 8997, 2191}},
+		{EncodingCL100kBase, "2 + 2 = 4", []int{17, 489, 220, 17, 284, 220, 19}},
+		{EncodingCL100kBase, "お誕生日おめでとう", []int{33334, 45918, 243, 21990, 9080, 33334, 62004, 16556, 78699}},
+		{EncodingO200kBase, "tiktoken is great!", []int{83, 8251, 2488, 382, 2212, 0}},
+		{EncodingO200kBase, "antidisestablishmentarianism", []int{493, 129901, 376, 160388, 21203, 2367}},
+		{EncodingO200kBase, "2 + 2 = 4", []int{17, 659, 220, 17, 314, 220, 19}},
+		{EncodingO200kBase, "お誕生日おめでとう", []int{8930, 9697, 243, 128225, 8930, 17693, 4344, 48669}},
+	}
+	encodings := map[string]*Encoding{}
+	for _, name := range []string{EncodingCL100kBase, EncodingO200kBase} {
+		// Loaded from the files directly, GetEncoding may return the test vocabularies.
+		enc, err := LoadEncodingFile(name, filepath.Join(TokenizerDir, name+".tiktoken"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		encodings[name] = enc
+	}
+	for _, tt := range tests {
+		t.Run(tt.encoding+" "+tt.text, func(t *testing.T) {
+			enc := encodings[tt.encoding]
+			if got := enc.Encode(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Encoding.Encode() = %v, want %v", got, tt.want)
+			}
+			if text, err := enc.Decode(tt.want); err != nil || text != tt.text {
+				t.Errorf("Encoding.Decode() = %q, %v", text, err)
+			}
+		})
+	}
+}
+
+func TestCountChatTokens(t *testing.T) {
+	RegisterEncoding(testEncoding(t, EncodingCL100kBase))
+	messages := []ChatMessage{{Role: ChatMessageRoleUser, Content: "Hello world"}}
+	// reply primer 3 + message overhead 3 + role "user" 4 bytes + content 2
+	got, err := BPETokenCounter{}.CountChatTokens("gpt-4", messages)
+	if err != nil || got != 12 {
+		t.Errorf("BPETokenCounter.CountChatTokens() = %d, %v, want 12", got, err)
+	}
+	if got, err := NewConversation("gpt-4", messages...).Tokens(); err != nil || got != 12 {
+		t.Errorf("Conversation.Tokens() with a registered encoding = %d, %v, want 12", got, err)
+	}
+	if TokenizerDir == "" {
+		want, _ := ApproximateTokenCounter{}.CountChatTokens("gpt-4o", messages)
+		if got, err := NewConversation("gpt-4o", messages...).Tokens(); err != nil || got != want {
+			t.Errorf("Conversation.Tokens() without an encoding = %d, %v, want the approximation %d", got, err, want)
+		}
+	}
+	if EncodingForModel("gpt-4o-mini") != EncodingO200kBase || EncodingForModel("gpt-3.5-turbo") != EncodingCL100kBase {
+		t.Error("EncodingForModel() returned the wrong encoding")
+	}
+}
@@ -0,0 +1,237 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// DefaultMaxSteps is the number of model calls a ToolRunner makes when MaxSteps is not set.
+const DefaultMaxSteps = 10
+
+// ErrMaxSteps is returned when the model still requests tool calls after MaxSteps model calls.
+var ErrMaxSteps = errors.New("tool runner reached the maximum number of steps")
+
+// A ToolHandler executes a single tool call and returns the content of the tool message.
+type ToolHandler func(ctx context.Context, call ToolCall) (string, error)
+
+// One model call of a run together with the tool calls it requested and their results.
+type RunStep struct {
+	Index    int
+	Response ChatCompletionResponse
+	Results  []ChatMessage
+}
+
+type RunResult struct {
+	// Final assistant message.
+	Message ChatMessage
+	// Complete conversation including the request messages, tool calls and tool results.
+	Messages []ChatMessage
+	Steps    int
+	Usage    Usage
+}
+
+// ToolRunner drives a chat completion until the model stops calling tools.
+//
+// Every step calls the model, executes the requested tool calls, appends their results
+// and calls the model again until it answers without tool calls or MaxSteps is reached.
+type ToolRunner struct {
+	client *Client
+	tools  map[string]registeredTool
+	order  []string
+
+	MaxSteps int
+	// Parallel executes the tool calls of a step concurrently.
+	Parallel bool
+	// Approve is consulted before every tool call. Rejected calls are answered with a
+	// message telling the model the call was denied, an error aborts the run.
+	Approve func(ctx context.Context, call ToolCall) (bool, error)
+	// OnStep is called after the tool calls of every step are executed, and for the step
+	// of the final answer, which has no Results.
+	OnStep func(step RunStep)
+	// OnPanic is called with the recovered value and the stack trace when a handler panics.
+	// The panic is reported to the model either way. It may be called concurrently when
+	// Parallel is set.
+	OnPanic func(call ToolCall, value interface{}, stack []byte)
+}
+
+type registeredTool struct {
+	tool    Tool
+	handler ToolHandler
+}
+
+// Creates a ToolRunner sending its requests through the given Client.
+func NewToolRunner(c *Client) *ToolRunner {
+	return &ToolRunner{
+		client:   c,
+		tools:    map[string]registeredTool{},
+		MaxSteps: DefaultMaxSteps,
+	}
+}
+
+// Registers a tool with a raw handler. The tool replaces any tool with the same name.
+func (r *ToolRunner) Register(tool Tool, handler ToolHandler) error {
+	name := tool.Function.Name
+	if name == "" {
+		return errors.New("tool name must not be empty")
+	}
+	if handler == nil {
+		return fmt.Errorf("handler of tool %s must not be nil", name)
+	}
+	if _, ok := r.tools[name]; !ok {
+		r.order = append(r.order, name)
+	}
+	r.tools[name] = registeredTool{tool: tool, handler: handler}
+	return nil
+}
+
+// Registers a Go function as a strict function tool. The parameters schema is reflected from
+// Args and the result is sent back to the model as JSON, strings are sent as is.
+func RegisterTool[Args any, Result any](r *ToolRunner, name, description string, fn func(context.Context, Args) (Result, error)) error {
+	var zero Args
+	tool, err := NewStrictFunctionTool(name, description, zero)
+	if err != nil {
+		return err
+	}
+	return r.Register(tool, func(ctx context.Context, call ToolCall) (string, error) {
+		var args Args
+		if err := call.DecodeArguments(&args); err != nil {
+			return "", err
+		}
+		res, err := fn(ctx, args)
+		if err != nil {
+			return "", err
+		}
+		if s, ok := interface{}(res).(string); ok {
+			return s, nil
+		}
+		b, err := json.Marshal(res)
+		return string(b), err
+	})
+}
+
+// Returns the registered tools in registration order.
+func (r *ToolRunner) Tools() []Tool {
+	tools := make([]Tool, 0, len(r.order))
+	for _, name := range r.order {
+		tools = append(tools, r.tools[name].tool)
+	}
+	return tools
+}
+
+// Runs the conversation of chatReq until the model returns a final answer. The registered
+// tools are added to the tools of the request, the passed request is not modified.
+//
+// @Returns the RunResult so far together with ErrMaxSteps if the model did not finish in time.
+func (r *ToolRunner) Run(ctx context.Context, chatReq *ChatCompletionRequest) (RunResult, error) {
+	var result RunResult
+	req := *chatReq
+	req.Tools = append(append([]Tool{}, chatReq.Tools...), r.Tools()...)
+	req.Messages = append([]ChatMessage{}, chatReq.Messages...)
+	maxSteps := r.MaxSteps
+	if maxSteps <= 0 {
+		maxSteps = DefaultMaxSteps
+	}
+	for step := 0; step < maxSteps; step++ {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		res, err := r.client.CreateChatCompletion(ctx, &req)
+		if err != nil {
+			return result, err
+		}
+		result.Steps++
+		result.Usage.Add(res.Usage)
+		if len(res.Choices) == 0 {
+			return result, errors.New("response contains no choices")
+		}
+		msg := res.Choices[0].Message
+		req.Messages = append(req.Messages, msg)
+		result.Message = msg
+		result.Messages = req.Messages
+		if len(msg.ToolCalls) == 0 {
+			if r.OnStep != nil {
+				r.OnStep(RunStep{Index: step, Response: res})
+			}
+			return result, nil
+		}
+		results, err := r.dispatch(ctx, msg.ToolCalls)
+		if err != nil {
+			return result, err
+		}
+		req.Messages = append(req.Messages, results...)
+		result.Messages = req.Messages
+		if r.OnStep != nil {
+			r.OnStep(RunStep{Index: step, Response: res, Results: results})
+		}
+	}
+	return result, ErrMaxSteps
+}
+
+// Executes the calls and returns their results in order, or the first approval error.
+func (r *ToolRunner) dispatch(ctx context.Context, calls []ToolCall) ([]ChatMessage, error) {
+	results := make([]ChatMessage, len(calls))
+	errs := make([]error, len(calls))
+	if !r.Parallel {
+		for i, call := range calls {
+			if results[i], errs[i] = r.execute(ctx, call); errs[i] != nil {
+				return nil, errs[i]
+			}
+		}
+		return results, nil
+	}
+	var wg sync.WaitGroup
+	for i, call := range calls {
+		wg.Add(1)
+		go func(i int, call ToolCall) {
+			defer wg.Done()
+			results[i], errs[i] = r.execute(ctx, call)
+		}(i, call)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// Executes a single call. Failures, panics of the handler included, are reported to the
+// model so it can recover from them. Only an error of Approve is returned.
+func (r *ToolRunner) execute(ctx context.Context, call ToolCall) (ChatMessage, error) {
+	if r.Approve != nil {
+		ok, err := r.Approve(ctx, call)
+		if err != nil {
+			return ChatMessage{}, fmt.Errorf("approving tool call %s: %w", call.ID, err)
+		}
+		if !ok {
+			return NewToolMessage(call.ID, "error: the tool call was denied by the user"), nil
+		}
+	}
+	t, ok := r.tools[call.Function.Name]
+	if !ok {
+		return NewToolMessage(call.ID, fmt.Sprintf("error: unknown tool %s", call.Function.Name)), nil
+	}
+	content, err := r.call(ctx, t.handler, call)
+	if err != nil {
+		return NewToolMessage(call.ID, fmt.Sprintf("error: %v", err)), nil
+	}
+	return NewToolMessage(call.ID, content), nil
+}
+
+// Calls the handler, turning a panic into an error after passing it to OnPanic.
+func (r *ToolRunner) call(ctx context.Context, handler ToolHandler, call ToolCall) (content string, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			if r.OnPanic != nil {
+				r.OnPanic(call, p, debug.Stack())
+			}
+			err = fmt.Errorf("tool %s panicked: %v", call.Function.Name, p)
+		}
+	}()
+	return handler(ctx, call)
+}
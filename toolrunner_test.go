@@ -0,0 +1,181 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/EthanCampana/go-openai"
+)
+
+type addArgs struct {
+	A int `json:"a"`
+	B int `json:"b"`
+}
+
+type addResult struct {
+	Sum int `json:"sum"`
+}
+
+func toolCallResponse(calls ...string) string {
+	return fmt.Sprintf(`{"choices":[{"index":0,"finish_reason":"tool_calls","message":{"role":"assistant","tool_calls":[%s]}}],
+		"usage":{"prompt_tokens":10,"completion_tokens":2,"total_tokens":12}}`, strings.Join(calls, ","))
+}
+
+func toolCall(id, name, args string) string {
+	return fmt.Sprintf(`{"id":%q,"type":"function","function":{"name":%q,"arguments":%q}}`, id, name, args)
+}
+
+func TestToolRunner_Run(t *testing.T) {
+	var calls int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req ChatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			if len(req.Tools) != 2 || !req.Tools[0].Function.Strict {
+				t.Errorf("tools were not sent: %+v", req.Tools)
+			}
+			fmt.Fprint(w, toolCallResponse(
+				toolCall("call_1", "add", `{"a":2,"b":3}`),
+				toolCall("call_2", "delete_everything", `{}`),
+				toolCall("call_3", "missing", `{}`),
+			))
+		default:
+			results := req.Messages[len(req.Messages)-3:]
+			want := []string{`{"sum":5}`, "error: the tool call was denied by the user", "error: unknown tool missing"}
+			for i, msg := range results {
+				if msg.Role != ChatMessageRoleTool || msg.Content != want[i] {
+					t.Errorf("tool result %d = %+v, want %s", i, msg, want[i])
+				}
+			}
+			fmt.Fprint(w, `{"choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"2 + 3 = 5"}}],
+				"usage":{"prompt_tokens":20,"completion_tokens":5,"total_tokens":25}}`)
+		}
+	})
+
+	runner := NewToolRunner(c)
+	runner.Parallel = true
+	runner.Approve = func(ctx context.Context, call ToolCall) (bool, error) {
+		return call.Function.Name != "delete_everything", nil
+	}
+	var steps []RunStep
+	runner.OnStep = func(step RunStep) { steps = append(steps, step) }
+	err := RegisterTool(runner, "add", "Adds two numbers", func(ctx context.Context, args addArgs) (addResult, error) {
+		return addResult{Sum: args.A + args.B}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = RegisterTool(runner, "delete_everything", "Deletes everything", func(ctx context.Context, args struct{}) (string, error) {
+		t.Error("denied tool was executed")
+		return "", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := &ChatCompletionRequest{Model: "gpt-4o", Messages: []ChatMessage{{Role: ChatMessageRoleUser, Content: "What is 2 + 3?"}}}
+	res, err := runner.Run(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Message.Content != "2 + 3 = 5" || res.Steps != 2 || len(res.Messages) != 6 {
+		t.Errorf("ToolRunner.Run() = %+v", res)
+	}
+	if res.Usage.TotalTokens != 37 {
+		t.Errorf("ToolRunner.Run() usage = %+v", res.Usage)
+	}
+	if len(steps) != 2 || len(steps[0].Results) != 3 || steps[1].Index != 1 || len(steps[1].Results) != 0 ||
+		steps[1].Response.Choices[0].Message.Content != "2 + 3 = 5" {
+		t.Errorf("OnStep calls = %+v", steps)
+	}
+	if len(req.Messages) != 1 || len(req.Tools) != 0 {
+		t.Error("ToolRunner.Run() modified the request")
+	}
+}
+
+func TestToolRunner_MaxSteps(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, toolCallResponse(toolCall("call_1", "noop", `{}`)))
+	})
+	runner := NewToolRunner(c)
+	runner.MaxSteps = 3
+	err := runner.Register(NewFunctionTool("noop", "", nil), func(ctx context.Context, call ToolCall) (string, error) {
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := runner.Run(context.Background(), &ChatCompletionRequest{Model: "gpt-4o"})
+	if !errors.Is(err, ErrMaxSteps) || res.Steps != 3 {
+		t.Errorf("ToolRunner.Run() = %d steps, %v", res.Steps, err)
+	}
+}
+
+func TestToolRunner_HandlerPanic(t *testing.T) {
+	var calls int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req ChatCompletionRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if atomic.AddInt32(&calls, 1) == 1 {
+			fmt.Fprint(w, toolCallResponse(toolCall("call_1", "crash", `{}`), toolCall("call_2", "noop", `{}`)))
+			return
+		}
+		results := req.Messages[len(req.Messages)-2:]
+		if results[0].Content != "error: tool crash panicked: boom" || results[1].Content != "ok" {
+			t.Errorf("tool results = %+v", results)
+		}
+		fmt.Fprint(w, `{"choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"done"}}]}`)
+	})
+	runner := NewToolRunner(c)
+	runner.Parallel = true
+	var panicked []string
+	runner.OnPanic = func(call ToolCall, value interface{}, stack []byte) {
+		panicked = append(panicked, fmt.Sprintf("%s %v", call.ID, value))
+		if len(stack) == 0 {
+			t.Error("OnPanic got no stack trace")
+		}
+	}
+	runner.Register(NewFunctionTool("crash", "", nil), func(ctx context.Context, call ToolCall) (string, error) {
+		panic("boom")
+	})
+	runner.Register(NewFunctionTool("noop", "", nil), func(ctx context.Context, call ToolCall) (string, error) {
+		return "ok", nil
+	})
+	res, err := runner.Run(context.Background(), &ChatCompletionRequest{Model: "gpt-4o"})
+	if err != nil || res.Message.Content != "done" {
+		t.Errorf("ToolRunner.Run() = %+v, %v", res, err)
+	}
+	if len(panicked) != 1 || panicked[0] != "call_1 boom" {
+		t.Errorf("OnPanic calls = %q", panicked)
+	}
+}
+
+func TestToolRunner_ApproveError(t *testing.T) {
+	var calls int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		fmt.Fprint(w, toolCallResponse(toolCall("call_1", "noop", `{}`)))
+	})
+	runner := NewToolRunner(c)
+	errNoUser := errors.New("no user to ask")
+	runner.Approve = func(ctx context.Context, call ToolCall) (bool, error) {
+		return false, errNoUser
+	}
+	runner.Register(NewFunctionTool("noop", "", nil), func(ctx context.Context, call ToolCall) (string, error) {
+		t.Error("tool was executed without approval")
+		return "ok", nil
+	})
+	res, err := runner.Run(context.Background(), &ChatCompletionRequest{Model: "gpt-4o"})
+	if !errors.Is(err, errNoUser) || res.Steps != 1 || calls != 1 {
+		t.Errorf("ToolRunner.Run() = %d steps, %v, want the Approve error after one step", res.Steps, err)
+	}
+}
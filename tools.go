@@ -0,0 +1,120 @@
+package openai
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const ToolTypeFunction = "function"
+
+const (
+	ToolChoiceNone     = "none"
+	ToolChoiceAuto     = "auto"
+	ToolChoiceRequired = "required"
+)
+
+// Describes a function the model may call. Parameters holds a JSON Schema object,
+// either as raw JSON, a map or any value that marshals into a schema.
+type FunctionDefinition struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Parameters  interface{} `json:"parameters,omitempty"`
+	Strict      bool        `json:"strict,omitempty"`
+}
+
+type Tool struct {
+	Type     string             `json:"type"`
+	Function FunctionDefinition `json:"function"`
+}
+
+type FunctionCall struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+}
+
+// A tool call made by the assistant. Index is only set on streaming deltas.
+type ToolCall struct {
+	Index    *int         `json:"index,omitempty"`
+	ID       string       `json:"id,omitempty"`
+	Type     string       `json:"type,omitempty"`
+	Function FunctionCall `json:"function"`
+}
+
+// Decodes the JSON arguments of the call into v.
+func (tc ToolCall) DecodeArguments(v interface{}) error {
+	if err := json.Unmarshal([]byte(tc.Function.Arguments), v); err != nil {
+		return fmt.Errorf("decoding arguments of %s: %w", tc.Function.Name, err)
+	}
+	return nil
+}
+
+// ToolChoice controls which tool the model calls.
+//
+// Mode is one of none, auto or required. Setting Function forces a call to that function.
+type ToolChoice struct {
+	Mode     string
+	Function string
+}
+
+// Creates a Tool of type function.
+func NewFunctionTool(name, description string, parameters interface{}) Tool {
+	return Tool{
+		Type: ToolTypeFunction,
+		Function: FunctionDefinition{
+			Name:        name,
+			Description: description,
+			Parameters:  parameters,
+		},
+	}
+}
+
+// Creates a strict function Tool whose parameters are reflected from the type of args, which must be a struct.
+func NewStrictFunctionTool(name, description string, args interface{}) (Tool, error) {
+	schema, err := GenerateStrictSchema(args)
+	if err != nil {
+		return Tool{}, fmt.Errorf("parameters of %s: %w", name, err)
+	}
+	tool := NewFunctionTool(name, description, schema)
+	tool.Function.Strict = true
+	return tool, nil
+}
+
+// Returns a ToolChoice forcing the model to call the named function.
+func ToolChoiceFunction(name string) *ToolChoice {
+	return &ToolChoice{Function: name}
+}
+
+// Returns a ToolChoice of the given mode: none, auto or required.
+func ToolChoiceMode(mode string) *ToolChoice {
+	return &ToolChoice{Mode: mode}
+}
+
+type toolChoiceFunction struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name string `json:"name"`
+	} `json:"function"`
+}
+
+func (tc ToolChoice) MarshalJSON() ([]byte, error) {
+	if tc.Function == "" {
+		return json.Marshal(tc.Mode)
+	}
+	var f toolChoiceFunction
+	f.Type = ToolTypeFunction
+	f.Function.Name = tc.Function
+	return json.Marshal(f)
+}
+
+func (tc *ToolChoice) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] == '"' {
+		*tc = ToolChoice{}
+		return json.Unmarshal(b, &tc.Mode)
+	}
+	var f toolChoiceFunction
+	if err := json.Unmarshal(b, &f); err != nil {
+		return err
+	}
+	*tc = ToolChoice{Function: f.Function.Name}
+	return nil
+}
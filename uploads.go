@@ -0,0 +1,292 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	UploadStatusPending   = "pending"
+	UploadStatusCompleted = "completed"
+	UploadStatusCancelled = "cancelled"
+	UploadStatusExpired   = "expired"
+)
+
+// Largest part accepted by AddUploadPart.
+const MaxUploadPartSize = 64 << 20
+
+const (
+	defaultUploadConcurrency = 4
+	defaultUploadPartRetries = 3
+	defaultUploadRetryDelay  = 500 * time.Millisecond
+	uploadCancelTimeout      = 10 * time.Second
+)
+
+type Upload struct {
+	ID        string `json:"id"`
+	Object    string `json:"object"`
+	Bytes     int64  `json:"bytes"`
+	CreatedAt int64  `json:"created_at"`
+	ExpiresAt int64  `json:"expires_at"`
+	Filename  string `json:"filename"`
+	Purpose   string `json:"purpose"`
+	Status    string `json:"status"`
+	File      *File  `json:"file,omitempty"`
+}
+
+// Bytes must be the exact size of the file that is going to be uploaded.
+type UploadRequest struct {
+	Filename string `json:"filename"`
+	Purpose  string `json:"purpose"`
+	Bytes    int64  `json:"bytes"`
+	MimeType string `json:"mime_type"`
+}
+
+type UploadPart struct {
+	ID        string `json:"id"`
+	Object    string `json:"object"`
+	CreatedAt int64  `json:"created_at"`
+	UploadID  string `json:"upload_id"`
+}
+
+// PartIDs lists the parts in the order they are joined in. MD5 is the optional hex
+// digest of the whole file, the upload fails when it does not match the uploaded bytes.
+type CompleteUploadRequest struct {
+	PartIDs []string `json:"part_ids"`
+	MD5     string   `json:"md5,omitempty"`
+}
+
+// Options of UploadLargeFile. The zero value uploads 64 MB parts, 4 at a time, and
+// retries every part up to 3 times.
+type UploadOptions struct {
+	// Size of every part but the last, at most MaxUploadPartSize.
+	PartSize int
+	// Number of parts uploaded at the same time. Up to Concurrency+1 parts are held in memory.
+	Concurrency int
+	// Retries per part after rate limits, server and network errors. A negative value disables retries.
+	MaxRetries int
+	// Delay before the first retry of a part, doubled on every further retry.
+	RetryDelay time.Duration
+	// Expected hex MD5 digest of the content. When set, the upload is cancelled instead of
+	// completed if the content read does not match it.
+	MD5 string
+}
+
+func (o *UploadOptions) withDefaults() (UploadOptions, error) {
+	var opts UploadOptions
+	if o != nil {
+		opts = *o
+	}
+	if opts.PartSize <= 0 {
+		opts.PartSize = MaxUploadPartSize
+	}
+	if opts.PartSize > MaxUploadPartSize {
+		return opts, fmt.Errorf("part size %d exceeds the limit of %d bytes", opts.PartSize, MaxUploadPartSize)
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultUploadConcurrency
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = defaultUploadPartRetries
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = defaultUploadRetryDelay
+	}
+	return opts, nil
+}
+
+// Utilizes the CreateUpload OpenAI API.
+//
+// @Returns the pending openai.Upload to add parts to.
+func (c *Client) CreateUpload(ctx context.Context, uploadReq *UploadRequest) (Upload, error) {
+	var res Upload
+	err := c.sendJSON(ctx, http.MethodPost, "uploads", uploadReq, &res)
+	return res, err
+}
+
+// Utilizes the AddUploadPart OpenAI API. A part holds at most MaxUploadPartSize bytes.
+//
+// @Returns openai.UploadPart.
+func (c *Client) AddUploadPart(ctx context.Context, uploadID string, data []byte) (UploadPart, error) {
+	var res UploadPart
+	var buff bytes.Buffer
+	buffW := multipart.NewWriter(&buff)
+	fw, err := buffW.CreateFormFile("data", "blob")
+	if err != nil {
+		return res, err
+	}
+	if _, err = fw.Write(data); err != nil {
+		return res, err
+	}
+	if err = buffW.Close(); err != nil {
+		return res, err
+	}
+	url := fmt.Sprintf("%s/uploads/%s/parts", apiURL, uploadID)
+	req, err := http.NewRequest("POST", url, &buff)
+	if err != nil {
+		return res, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", buffW.FormDataContentType())
+	err = c.SendRequest(req, &res)
+	return res, err
+}
+
+// Utilizes the CompleteUpload OpenAI API.
+//
+// @Returns the completed openai.Upload, its File is ready to use.
+func (c *Client) CompleteUpload(ctx context.Context, uploadID string, completeReq *CompleteUploadRequest) (Upload, error) {
+	var res Upload
+	err := c.sendJSON(ctx, http.MethodPost, "uploads/"+uploadID+"/complete", completeReq, &res)
+	return res, err
+}
+
+// Utilizes the CancelUpload OpenAI API.
+//
+// @Returns the cancelled openai.Upload.
+func (c *Client) CancelUpload(ctx context.Context, uploadID string) (Upload, error) {
+	var res Upload
+	err := c.sendJSON(ctx, http.MethodPost, "uploads/"+uploadID+"/cancel", nil, &res)
+	return res, err
+}
+
+// Uploads the content of r, which must be exactly uploadReq.Bytes long, through the
+// Uploads API. r is split into parts that are uploaded concurrently, a failed part is
+// retried on its own. The MD5 digest of the content is sent along to have the upload
+// verified. On failure the upload is cancelled. opts may be nil.
+//
+// @Returns the openai.File the upload was completed into.
+func (c *Client) UploadLargeFile(ctx context.Context, r io.Reader, uploadReq *UploadRequest, opts *UploadOptions) (File, error) {
+	o, err := opts.withDefaults()
+	if err != nil {
+		return File{}, err
+	}
+	if uploadReq.Bytes <= 0 {
+		return File{}, errors.New("upload size is required")
+	}
+	upload, err := c.CreateUpload(ctx, uploadReq)
+	if err != nil {
+		return File{}, err
+	}
+	partIDs, size, sum, err := c.uploadParts(ctx, upload.ID, r, o)
+	if err == nil && size != uploadReq.Bytes {
+		err = fmt.Errorf("read %d bytes, expected %d", size, uploadReq.Bytes)
+	}
+	if err == nil && o.MD5 != "" && o.MD5 != sum {
+		err = fmt.Errorf("md5 mismatch: content has %s, expected %s", sum, o.MD5)
+	}
+	if err != nil {
+		// Best effort, an upload that is left pending expires on its own.
+		cancelCtx, cancel := context.WithTimeout(context.Background(), uploadCancelTimeout)
+		defer cancel()
+		c.CancelUpload(cancelCtx, upload.ID)
+		return File{}, err
+	}
+	upload, err = c.CompleteUpload(ctx, upload.ID, &CompleteUploadRequest{PartIDs: partIDs, MD5: sum})
+	if err != nil {
+		return File{}, err
+	}
+	if upload.File == nil {
+		return File{}, fmt.Errorf("upload %s completed without a file", upload.ID)
+	}
+	return *upload.File, nil
+}
+
+// Reads r in parts of o.PartSize and uploads them with at most o.Concurrency in flight.
+//
+// @Returns the part ids in order, the number of bytes read and their hex MD5 digest.
+func (c *Client) uploadParts(ctx context.Context, uploadID string, r io.Reader, o UploadOptions) ([]string, int64, string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		partIDs  []string
+		firstErr error
+		size     int64
+	)
+	// Keeps the failure that caused the cancellation rather than the uploads it cancelled.
+	fail := func(err error) {
+		mu.Lock()
+		if firstErr == nil || errors.Is(firstErr, context.Canceled) {
+			firstErr = err
+		}
+		mu.Unlock()
+		cancel()
+	}
+	hash := md5.New()
+	sem := make(chan struct{}, o.Concurrency)
+read:
+	for i := 0; ; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			fail(ctx.Err())
+			break read
+		}
+		buf := make([]byte, o.PartSize)
+		n, err := io.ReadFull(r, buf)
+		if n == 0 {
+			<-sem
+		} else {
+			hash.Write(buf[:n])
+			size += int64(n)
+			mu.Lock()
+			partIDs = append(partIDs, "")
+			mu.Unlock()
+			wg.Add(1)
+			go func(i int, data []byte) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				part, err := c.addUploadPartWithRetry(ctx, uploadID, data, o)
+				if err != nil {
+					fail(fmt.Errorf("uploading part %d: %w", i+1, err))
+					return
+				}
+				mu.Lock()
+				partIDs[i] = part.ID
+				mu.Unlock()
+			}(i, buf[:n])
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			fail(err)
+			break
+		}
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, size, "", firstErr
+	}
+	return partIDs, size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (c *Client) addUploadPartWithRetry(ctx context.Context, uploadID string, data []byte, o UploadOptions) (UploadPart, error) {
+	delay := o.RetryDelay
+	for attempt := 0; ; attempt++ {
+		part, err := c.AddUploadPart(ctx, uploadID, data)
+		var apiErr *APIError
+		if err == nil || attempt >= o.MaxRetries || ctx.Err() != nil || errors.As(err, &apiErr) && !apiErr.Temporary() {
+			return part, err
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return part, ctx.Err()
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
@@ -0,0 +1,120 @@
+package openai_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/EthanCampana/go-openai"
+)
+
+type fakeUploads struct {
+	mu        sync.Mutex
+	parts     map[string][]byte
+	attempts  int
+	failFirst bool
+	cancelled bool
+	completed []byte
+}
+
+func (f *fakeUploads) handle(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		switch r.URL.Path {
+		case "/v1/uploads":
+			var req UploadRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.Filename != "big.jsonl" || req.Bytes != 10 || req.Purpose != FilePurposeBatch {
+				t.Errorf("CreateUpload request = %+v", req)
+			}
+			fmt.Fprint(w, `{"id":"upload_1","status":"pending"}`)
+		case "/v1/uploads/upload_1/parts":
+			f.attempts++
+			if f.failFirst && f.attempts == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			file, _, err := r.FormFile("data")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			data, _ := io.ReadAll(file)
+			id := fmt.Sprintf("part_%d", len(f.parts))
+			f.parts[id] = data
+			fmt.Fprintf(w, `{"id":%q,"upload_id":"upload_1"}`, id)
+		case "/v1/uploads/upload_1/complete":
+			var req CompleteUploadRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			var content []byte
+			for _, id := range req.PartIDs {
+				content = append(content, f.parts[id]...)
+			}
+			sum := md5.Sum(content)
+			if req.MD5 != hex.EncodeToString(sum[:]) {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":{"message":"md5 mismatch"}}`)
+				return
+			}
+			f.completed = content
+			fmt.Fprintf(w, `{"id":"upload_1","status":"completed","file":{"id":"file-1","bytes":%d}}`, len(content))
+		case "/v1/uploads/upload_1/cancel":
+			f.cancelled = true
+			fmt.Fprint(w, `{"id":"upload_1","status":"cancelled"}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}
+}
+
+func TestClient_UploadLargeFile(t *testing.T) {
+	const content = "0123456789"
+	uploadReq := &UploadRequest{Filename: "big.jsonl", Purpose: FilePurposeBatch, Bytes: 10, MimeType: "application/jsonl"}
+	sum := md5.Sum([]byte(content))
+
+	tests := []struct {
+		name          string
+		failFirst     bool
+		opts          UploadOptions
+		wantErr       string
+		wantAttempts  int
+		wantCancelled bool
+	}{
+		{name: "parts", opts: UploadOptions{PartSize: 3, Concurrency: 2, MD5: hex.EncodeToString(sum[:])}, wantAttempts: 4},
+		{name: "retry", failFirst: true, opts: UploadOptions{PartSize: 4, RetryDelay: time.Millisecond}, wantAttempts: 4},
+		{name: "no retries", failFirst: true, opts: UploadOptions{PartSize: 4, MaxRetries: -1}, wantErr: "status code: 502", wantCancelled: true},
+		{name: "md5 mismatch", opts: UploadOptions{PartSize: 4, MD5: "00"}, wantErr: "md5 mismatch", wantAttempts: 3, wantCancelled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeUploads{parts: map[string][]byte{}, failFirst: tt.failFirst}
+			client := newTestClient(t, fake.handle(t))
+			file, err := client.UploadLargeFile(context.Background(), strings.NewReader(content), uploadReq, &tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("UploadLargeFile() error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			} else if file.ID != "file-1" || !bytes.Equal(fake.completed, []byte(content)) {
+				t.Errorf("UploadLargeFile() = %+v, uploaded %q", file, fake.completed)
+			}
+			if tt.wantAttempts != 0 && fake.attempts != tt.wantAttempts {
+				t.Errorf("part requests = %d, want %d", fake.attempts, tt.wantAttempts)
+			}
+			if fake.cancelled != tt.wantCancelled {
+				t.Errorf("cancelled = %v, want %v", fake.cancelled, tt.wantCancelled)
+			}
+		})
+	}
+}
@@ -0,0 +1,363 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrBudgetExceeded is returned by every call of a Client once the estimated cost
+// recorded by its UsageTracker reached the budget.
+var ErrBudgetExceeded = errors.New("usage budget exceeded")
+
+// Price of a text model in dollars per one million tokens.
+type ModelPrice struct {
+	Input       float64
+	CachedInput float64
+	Output      float64
+}
+
+// Pricing holds the prices used to estimate costs.
+//
+// Models are matched on the longest prefix of the model name. Images are keyed by
+// ImagePriceKey(model, quality, size) and priced per generated image.
+type Pricing struct {
+	Models map[string]ModelPrice
+	Images map[string]float64
+}
+
+// Returns the key of an image price. Empty quality and size resolve to the model defaults.
+func ImagePriceKey(model, quality, size string) string {
+	if model == "" {
+		model = DefaultImageModel
+	}
+	if quality == "" || quality == "auto" {
+		switch model {
+		case ImageModelGPTImage1:
+			// auto lets the API pick, price it as the most expensive quality.
+			quality = "high"
+		default:
+			quality = "standard"
+		}
+	}
+	if size == "" || size == AUTO {
+		size = LARGE
+	}
+	return model + "|" + quality + "|" + size
+}
+
+// Returns the list prices published by OpenAI at the time of writing.
+// Prices change, override them with UsageTracker.SetPricing where they matter.
+func DefaultPricing() Pricing {
+	return Pricing{
+		Models: map[string]ModelPrice{
+			"gpt-3.5-turbo":          {Input: 0.50, Output: 1.50},
+			"gpt-4":                  {Input: 30, Output: 60},
+			"gpt-4-turbo":            {Input: 10, Output: 30},
+			"gpt-4o":                 {Input: 2.50, CachedInput: 1.25, Output: 10},
+			"gpt-4o-mini":            {Input: 0.15, CachedInput: 0.075, Output: 0.60},
+			"gpt-4.1":                {Input: 2, CachedInput: 0.50, Output: 8},
+			"gpt-4.1-mini":           {Input: 0.40, CachedInput: 0.10, Output: 1.60},
+			"gpt-4.1-nano":           {Input: 0.10, CachedInput: 0.025, Output: 0.40},
+			"gpt-5":                  {Input: 1.25, CachedInput: 0.125, Output: 10},
+			"gpt-5-mini":             {Input: 0.25, CachedInput: 0.025, Output: 2},
+			"gpt-5-nano":             {Input: 0.05, CachedInput: 0.005, Output: 0.40},
+			"o1":                     {Input: 15, CachedInput: 7.50, Output: 60},
+			"o3":                     {Input: 2, CachedInput: 0.50, Output: 8},
+			"o3-mini":                {Input: 1.10, CachedInput: 0.55, Output: 4.40},
+			"o4-mini":                {Input: 1.10, CachedInput: 0.275, Output: 4.40},
+			"text-embedding-3-small": {Input: 0.02},
+			"text-embedding-3-large": {Input: 0.13},
+			"text-embedding-ada-002": {Input: 0.10},
+		},
+		Images: map[string]float64{
+			ImagePriceKey(ImageModelDallE2, "", SMALL):             0.016,
+			ImagePriceKey(ImageModelDallE2, "", MEDIUM):            0.018,
+			ImagePriceKey(ImageModelDallE2, "", LARGE):             0.020,
+			ImagePriceKey(ImageModelDallE3, "standard", LARGE):     0.040,
+			ImagePriceKey(ImageModelDallE3, "standard", LANDSCAPE): 0.080,
+			ImagePriceKey(ImageModelDallE3, "standard", PORTRAIT):  0.080,
+			ImagePriceKey(ImageModelDallE3, "hd", LARGE):           0.080,
+			ImagePriceKey(ImageModelDallE3, "hd", LANDSCAPE):       0.120,
+			ImagePriceKey(ImageModelDallE3, "hd", PORTRAIT):        0.120,
+			ImagePriceKey(ImageModelGPTImage1, "low", LARGE):       0.011,
+			ImagePriceKey(ImageModelGPTImage1, "low", WIDE):        0.016,
+			ImagePriceKey(ImageModelGPTImage1, "low", TALL):        0.016,
+			ImagePriceKey(ImageModelGPTImage1, "medium", LARGE):    0.042,
+			ImagePriceKey(ImageModelGPTImage1, "medium", WIDE):     0.063,
+			ImagePriceKey(ImageModelGPTImage1, "medium", TALL):     0.063,
+			ImagePriceKey(ImageModelGPTImage1, "high", LARGE):      0.167,
+			ImagePriceKey(ImageModelGPTImage1, "high", WIDE):       0.250,
+			ImagePriceKey(ImageModelGPTImage1, "high", TALL):       0.250,
+		},
+	}
+}
+
+// Returns the price of model, matched on the longest prefix.
+func (p Pricing) ModelPrice(model string) (ModelPrice, bool) {
+	best := ""
+	for prefix := range p.Models {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	price, ok := p.Models[best]
+	return price, ok && best != ""
+}
+
+// Returns the estimated cost in dollars of usage on model. Unknown models cost nothing.
+func (p Pricing) ChatCost(model string, usage Usage) float64 {
+	price, ok := p.ModelPrice(model)
+	if !ok {
+		return 0
+	}
+	cached := usage.PromptTokensDetails.CachedTokens
+	cachedPrice := price.CachedInput
+	if cachedPrice == 0 {
+		cachedPrice = price.Input
+	}
+	return (float64(usage.PromptTokens-cached)*price.Input +
+		float64(cached)*cachedPrice +
+		float64(usage.CompletionTokens)*price.Output) / 1e6
+}
+
+// Accumulated usage and estimated cost.
+type UsageTotals struct {
+	Requests         int
+	PromptTokens     int
+	CompletionTokens int
+	CachedTokens     int
+	ReasoningTokens  int
+	// Number of generated images by ImagePriceKey.
+	Images map[string]int
+	Cost   float64
+}
+
+func (t *UsageTotals) add(o UsageTotals) {
+	t.Requests += o.Requests
+	t.PromptTokens += o.PromptTokens
+	t.CompletionTokens += o.CompletionTokens
+	t.CachedTokens += o.CachedTokens
+	t.ReasoningTokens += o.ReasoningTokens
+	t.Cost += o.Cost
+	for k, n := range o.Images {
+		if t.Images == nil {
+			t.Images = map[string]int{}
+		}
+		t.Images[k] += n
+	}
+}
+
+// UsageTracker accumulates the usage of every response of a Client, see Client.SetUsageTracker.
+// Totals are kept per model, per user (the User field of the request) and per tag (see WithUsageTags).
+// Assistant runs are recorded when they finish, through WaitForRun or a RunStream, and
+// realtime sessions with every response.done event.
+type UsageTracker struct {
+	mu      sync.Mutex
+	pricing Pricing
+	budget  float64
+	total   UsageTotals
+	byModel map[string]*UsageTotals
+	byUser  map[string]*UsageTotals
+	byTag   map[string]*UsageTotals
+}
+
+// Creates a UsageTracker using DefaultPricing.
+func NewUsageTracker() *UsageTracker {
+	return &UsageTracker{
+		pricing: DefaultPricing(),
+		byModel: map[string]*UsageTotals{},
+		byUser:  map[string]*UsageTotals{},
+		byTag:   map[string]*UsageTotals{},
+	}
+}
+
+// Replaces the pricing used for future records.
+func (t *UsageTracker) SetPricing(p Pricing) *UsageTracker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pricing = p
+	return t
+}
+
+// Sets the budget in dollars. Once the estimated total cost reaches it every call of the
+// Client fails with ErrBudgetExceeded. 0 disables the budget.
+func (t *UsageTracker) SetBudget(dollars float64) *UsageTracker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.budget = dollars
+	return t
+}
+
+// Returns the totals of every recorded response.
+func (t *UsageTracker) Total() UsageTotals {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return copyTotals(&t.total)
+}
+
+// Returns the totals per model.
+func (t *UsageTracker) ByModel() map[string]UsageTotals {
+	return t.snapshot(func() map[string]*UsageTotals { return t.byModel })
+}
+
+// Returns the totals per user. Requests without a user are recorded under "".
+func (t *UsageTracker) ByUser() map[string]UsageTotals {
+	return t.snapshot(func() map[string]*UsageTotals { return t.byUser })
+}
+
+// Returns the totals per tag. Requests with several tags count towards each of them.
+func (t *UsageTracker) ByTag() map[string]UsageTotals {
+	return t.snapshot(func() map[string]*UsageTotals { return t.byTag })
+}
+
+// Clears every recorded total.
+func (t *UsageTracker) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.total = UsageTotals{}
+	t.byModel = map[string]*UsageTotals{}
+	t.byUser = map[string]*UsageTotals{}
+	t.byTag = map[string]*UsageTotals{}
+}
+
+// Records the usage of a chat or embedding response.
+func (t *UsageTracker) RecordChat(ctx context.Context, model, user string, usage Usage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.record(ctx, model, user, UsageTotals{
+		Requests:         1,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		CachedTokens:     usage.PromptTokensDetails.CachedTokens,
+		ReasoningTokens:  usage.CompletionTokensDetails.ReasoningTokens,
+		Cost:             t.pricing.ChatCost(model, usage),
+	})
+}
+
+// Records n generated images.
+func (t *UsageTracker) RecordImages(ctx context.Context, model, quality, size, user string, n int) {
+	key := ImagePriceKey(model, quality, size)
+	if model == "" {
+		model = DefaultImageModel
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.record(ctx, model, user, UsageTotals{
+		Requests: 1,
+		Images:   map[string]int{key: n},
+		Cost:     t.pricing.Images[key] * float64(n),
+	})
+}
+
+func (t *UsageTracker) record(ctx context.Context, model, user string, totals UsageTotals) {
+	t.total.add(totals)
+	entry(t.byModel, model).add(totals)
+	entry(t.byUser, user).add(totals)
+	for _, tag := range usageTags(ctx) {
+		entry(t.byTag, tag).add(totals)
+	}
+}
+
+func (t *UsageTracker) snapshot(m func() map[string]*UsageTotals) map[string]UsageTotals {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	res := map[string]UsageTotals{}
+	for k, v := range m() {
+		res[k] = copyTotals(v)
+	}
+	return res
+}
+
+func (t *UsageTracker) checkBudget() error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.budget > 0 && t.total.Cost >= t.budget {
+		return fmt.Errorf("%w: spent $%.4f of $%.4f", ErrBudgetExceeded, t.total.Cost, t.budget)
+	}
+	return nil
+}
+
+func entry(m map[string]*UsageTotals, key string) *UsageTotals {
+	e, ok := m[key]
+	if !ok {
+		e = &UsageTotals{}
+		m[key] = e
+	}
+	return e
+}
+
+func copyTotals(t *UsageTotals) UsageTotals {
+	c := *t
+	c.Images = nil
+	for k, n := range t.Images {
+		if c.Images == nil {
+			c.Images = map[string]int{}
+		}
+		c.Images[k] = n
+	}
+	return c
+}
+
+type usageTagsKey struct{}
+
+// Returns a context whose requests are recorded under the given tags in addition to
+// the tags already on ctx.
+func WithUsageTags(ctx context.Context, tags ...string) context.Context {
+	all := append(append([]string{}, usageTags(ctx)...), tags...)
+	return context.WithValue(ctx, usageTagsKey{}, all)
+}
+
+func usageTags(ctx context.Context) []string {
+	tags, _ := ctx.Value(usageTagsKey{}).([]string)
+	return tags
+}
+
+// Sets the UsageTracker recording every response of the client. Pass nil to disable it.
+func (c *Client) SetUsageTracker(t *UsageTracker) *Client {
+	c.usage = t
+	return c
+}
+
+// Returns the UsageTracker of the client, nil if none is set.
+func (c *Client) UsageTracker() *UsageTracker {
+	return c.usage
+}
+
+func (c *Client) recordChatUsage(ctx context.Context, req *ChatCompletionRequest, model string, usage Usage) {
+	if c.usage == nil {
+		return
+	}
+	if model == "" {
+		model = req.Model
+	}
+	c.usage.RecordChat(ctx, model, req.User, usage)
+}
+
+// Records the usage of a finished run. Runs only carry their usage once they are done,
+// and only WaitForRun and run streams record it so that a run is counted once.
+func (c *Client) recordRunUsage(ctx context.Context, run Run) {
+	if c.usage == nil || !run.Done() || run.Usage == nil {
+		return
+	}
+	c.usage.RecordChat(ctx, run.Model, "", *run.Usage)
+}
+
+func (c *Client) recordImageUsage(ctx context.Context, req Request, res ImageResponse) {
+	if c.usage == nil {
+		return
+	}
+	switch r := req.(type) {
+	case *ImageRequest:
+		c.usage.RecordImages(ctx, r.Model, r.Quality, r.Size, r.User, len(res.Data))
+	case *ImageEditRequest:
+		c.usage.RecordImages(ctx, r.Model, "", r.Size, r.User, len(res.Data))
+	case *ImageVariationRequest:
+		c.usage.RecordImages(ctx, r.Model, "", r.Size, r.User, len(res.Data))
+	}
+}
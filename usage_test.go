@@ -0,0 +1,145 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/EthanCampana/go-openai"
+)
+
+func TestPricing_ChatCost(t *testing.T) {
+	p := DefaultPricing()
+	tests := []struct {
+		name  string
+		model string
+		usage Usage
+		want  float64
+	}{
+		{name: "Exact", model: "gpt-4o", usage: Usage{PromptTokens: 1000000, CompletionTokens: 1000000}, want: 12.5},
+		{name: "LongestPrefix", model: "gpt-4o-mini-2024-07-18", usage: Usage{PromptTokens: 1000000}, want: 0.15},
+		{name: "Cached", model: "gpt-4o", usage: Usage{PromptTokens: 1000000, PromptTokensDetails: PromptTokensDetails{CachedTokens: 500000}}, want: 1.875},
+		{name: "Unknown", model: "my-model", usage: Usage{PromptTokens: 1000}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.ChatCost(tt.model, tt.usage); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("ChatCost(%s) = %v, want %v", tt.model, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUsageTracker_Records(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/chat/completions":
+			fmt.Fprint(w, `{"model":"gpt-4o-2024-08-06","choices":[{"message":{"role":"assistant","content":"hi"}}],`+
+				`"usage":{"prompt_tokens":100,"completion_tokens":50,"total_tokens":150,`+
+				`"prompt_tokens_details":{"cached_tokens":20},"completion_tokens_details":{"reasoning_tokens":10}}}`)
+		case "/v1/images/generations":
+			fmt.Fprint(w, `{"created":1,"data":[{"url":"a"},{"url":"b"}]}`)
+		}
+	})
+	tracker := NewUsageTracker()
+	client.SetUsageTracker(tracker)
+
+	ctx := WithUsageTags(context.Background(), "feature-a")
+	if _, err := client.CreateChatCompletion(ctx, &ChatCompletionRequest{Model: "gpt-4o", User: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	img := &ImageRequest{Model: ImageModelDallE3, Prompt: "cat", Quality: "hd", Size: LANDSCAPE}
+	if _, err := client.CreateImage(ctx, img); err != nil {
+		t.Fatal(err)
+	}
+
+	total := tracker.Total()
+	if total.Requests != 2 || total.PromptTokens != 100 || total.CompletionTokens != 50 ||
+		total.CachedTokens != 20 || total.ReasoningTokens != 10 {
+		t.Errorf("Total() = %+v", total)
+	}
+	if n := total.Images[ImagePriceKey(ImageModelDallE3, "hd", LANDSCAPE)]; n != 2 {
+		t.Errorf("Total().Images = %v, want 2 hd landscape images", total.Images)
+	}
+	wantCost := (80*2.5+20*1.25+50*10)/1e6 + 2*0.12
+	if math.Abs(total.Cost-wantCost) > 1e-9 {
+		t.Errorf("Total().Cost = %v, want %v", total.Cost, wantCost)
+	}
+	if got := tracker.ByModel()["gpt-4o-2024-08-06"].Requests; got != 1 {
+		t.Errorf("ByModel()[gpt-4o-2024-08-06].Requests = %d, want 1", got)
+	}
+	if got := tracker.ByUser()["alice"].PromptTokens; got != 100 {
+		t.Errorf("ByUser()[alice].PromptTokens = %d, want 100", got)
+	}
+	if got := tracker.ByTag()["feature-a"].Requests; got != 2 {
+		t.Errorf("ByTag()[feature-a].Requests = %d, want 2", got)
+	}
+}
+
+func TestUsageTracker_Runs(t *testing.T) {
+	const completed = `{"id":"run_1","thread_id":"thread_1","status":"completed","model":"gpt-4o",` +
+		`"usage":{"prompt_tokens":100,"completion_tokens":10,"total_tokens":110}}`
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/threads/thread_1/runs/run_1":
+			fmt.Fprint(w, completed)
+		case "/v1/threads/thread_1/runs":
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "event: thread.run.in_progress\ndata: {\"id\":\"run_1\",\"status\":\"in_progress\",\"model\":\"gpt-4o\"}\n\n")
+			fmt.Fprint(w, "event: thread.run.completed\ndata: "+completed+"\n\n")
+			fmt.Fprint(w, "event: done\ndata: [DONE]\n\n")
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	})
+	tracker := NewUsageTracker()
+	client.SetUsageTracker(tracker)
+	ctx := context.Background()
+
+	if _, err := client.WaitForRun(ctx, "thread_1", "run_1", time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetRun(ctx, "thread_1", "run_1"); err != nil {
+		t.Fatal(err)
+	}
+	if total := tracker.Total(); total.Requests != 1 || total.PromptTokens != 100 {
+		t.Errorf("Total() after WaitForRun and GetRun = %+v, want the run once", total)
+	}
+
+	tracker.Reset()
+	stream, err := client.CreateRunStream(ctx, "thread_1", &RunRequest{AssistantID: "asst_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = stream.Consume(RunEventHandler{}); err != nil {
+		t.Fatal(err)
+	}
+	if total := tracker.Total(); total.Requests != 1 || total.CompletionTokens != 10 {
+		t.Errorf("Total() after a run stream = %+v, want the finished run once", total)
+	}
+}
+
+func TestUsageTracker_Budget(t *testing.T) {
+	calls := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"model":"gpt-4","choices":[],"usage":{"prompt_tokens":1000,"completion_tokens":1000}}`)
+	})
+	client.SetUsageTracker(NewUsageTracker().SetBudget(0.05))
+
+	req := &ChatCompletionRequest{Model: "gpt-4"}
+	if _, err := client.CreateChatCompletion(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.CreateChatCompletion(context.Background(), req)
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("CreateChatCompletion() error = %v, want ErrBudgetExceeded", err)
+	}
+	if calls != 1 {
+		t.Errorf("server received %d calls, want 1", calls)
+	}
+}
@@ -0,0 +1,415 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	UsageBucketWidthMinute = "1m"
+	UsageBucketWidthHour   = "1h"
+	UsageBucketWidthDay    = "1d"
+)
+
+// Fields usage and cost results can be grouped by. The costs endpoint only supports
+// project_id and line_item.
+const (
+	UsageGroupByProjectID = "project_id"
+	UsageGroupByUserID    = "user_id"
+	UsageGroupByAPIKeyID  = "api_key_id"
+	UsageGroupByModel     = "model"
+	UsageGroupByBatch     = "batch"
+	UsageGroupBySource    = "source"
+	UsageGroupBySize      = "size"
+	UsageGroupByLineItem  = "line_item"
+)
+
+// Query of the usage and costs endpoints. StartTime is required, the other fields are
+// left to the API defaults when empty. Filters that do not apply to an endpoint are ignored.
+type UsageQuery struct {
+	StartTime time.Time
+	EndTime   time.Time
+	// UsageBucketWidthMinute, Hour or Day. Costs are only bucketed by day.
+	BucketWidth string
+	ProjectIDs  []string
+	UserIDs     []string
+	APIKeyIDs   []string
+	Models      []string
+	// Only count batch or non batch requests when set.
+	Batch   *bool
+	GroupBy []string
+	// Number of buckets per page.
+	Limit int
+	// Cursor of the page to return, the NextPage of the previous one.
+	Page string
+}
+
+func (q *UsageQuery) encode(path string) (string, error) {
+	if q == nil || q.StartTime.IsZero() {
+		return "", errors.New("usage query start time is required")
+	}
+	v := url.Values{}
+	v.Set("start_time", strconv.FormatInt(q.StartTime.Unix(), 10))
+	if !q.EndTime.IsZero() {
+		v.Set("end_time", strconv.FormatInt(q.EndTime.Unix(), 10))
+	}
+	if q.BucketWidth != "" {
+		v.Set("bucket_width", q.BucketWidth)
+	}
+	for key, values := range map[string][]string{
+		"project_ids[]": q.ProjectIDs,
+		"user_ids[]":    q.UserIDs,
+		"api_key_ids[]": q.APIKeyIDs,
+		"models[]":      q.Models,
+		"group_by[]":    q.GroupBy,
+	} {
+		for _, value := range values {
+			v.Add(key, value)
+		}
+	}
+	if q.Batch != nil {
+		v.Set("batch", strconv.FormatBool(*q.Batch))
+	}
+	if q.Limit > 0 {
+		v.Set("limit", strconv.Itoa(q.Limit))
+	}
+	if q.Page != "" {
+		v.Set("page", q.Page)
+	}
+	return path + "?" + v.Encode(), nil
+}
+
+// A page of time buckets returned by the usage and costs endpoints.
+type UsagePage[T any] struct {
+	Object   string           `json:"object"`
+	Data     []UsageBucket[T] `json:"data"`
+	HasMore  bool             `json:"has_more"`
+	NextPage string           `json:"next_page,omitempty"`
+}
+
+// The results of one time bucket, one per group when the query is grouped.
+type UsageBucket[T any] struct {
+	Object    string `json:"object"`
+	StartTime int64  `json:"start_time"`
+	EndTime   int64  `json:"end_time"`
+	Results   []T    `json:"results"`
+}
+
+// Fields of a usage result that are only set when the query groups by them.
+type UsageGroup struct {
+	ProjectID string `json:"project_id,omitempty"`
+	UserID    string `json:"user_id,omitempty"`
+	APIKeyID  string `json:"api_key_id,omitempty"`
+	Model     string `json:"model,omitempty"`
+}
+
+type CompletionsUsageResult struct {
+	UsageGroup
+	Batch             *bool `json:"batch,omitempty"`
+	InputTokens       int64 `json:"input_tokens"`
+	InputCachedTokens int64 `json:"input_cached_tokens"`
+	OutputTokens      int64 `json:"output_tokens"`
+	InputAudioTokens  int64 `json:"input_audio_tokens"`
+	OutputAudioTokens int64 `json:"output_audio_tokens"`
+	NumModelRequests  int64 `json:"num_model_requests"`
+}
+
+type EmbeddingsUsageResult struct {
+	UsageGroup
+	InputTokens      int64 `json:"input_tokens"`
+	NumModelRequests int64 `json:"num_model_requests"`
+}
+
+type ImagesUsageResult struct {
+	UsageGroup
+	Source           string `json:"source,omitempty"`
+	Size             string `json:"size,omitempty"`
+	Images           int64  `json:"images"`
+	NumModelRequests int64  `json:"num_model_requests"`
+}
+
+type AudioSpeechesUsageResult struct {
+	UsageGroup
+	Characters       int64 `json:"characters"`
+	NumModelRequests int64 `json:"num_model_requests"`
+}
+
+type AudioTranscriptionsUsageResult struct {
+	UsageGroup
+	Seconds          int64 `json:"seconds"`
+	NumModelRequests int64 `json:"num_model_requests"`
+}
+
+type CostAmount struct {
+	Value    float64 `json:"value"`
+	Currency string  `json:"currency"`
+}
+
+type CostResult struct {
+	Amount    CostAmount `json:"amount"`
+	LineItem  string     `json:"line_item,omitempty"`
+	ProjectID string     `json:"project_id,omitempty"`
+}
+
+func getUsagePage[T any](ctx context.Context, a *AdminClient, path string, q *UsageQuery) (UsagePage[T], error) {
+	var res UsagePage[T]
+	path, err := q.encode(path)
+	if err != nil {
+		return res, err
+	}
+	err = a.client.sendJSON(ctx, http.MethodGet, path, nil, &res)
+	return res, err
+}
+
+// Returns an iterator over the buckets of every page, starting at q.Page. Every iteration
+// starts from q again. An error is yielded last.
+func usageBuckets[T any](ctx context.Context, a *AdminClient, path string, q *UsageQuery) iter.Seq2[UsageBucket[T], error] {
+	return func(yield func(UsageBucket[T], error) bool) {
+		if q == nil {
+			yield(UsageBucket[T]{}, errors.New("usage query start time is required"))
+			return
+		}
+		pageQuery := *q
+		for {
+			page, err := getUsagePage[T](ctx, a, path, &pageQuery)
+			if err != nil {
+				yield(UsageBucket[T]{}, err)
+				return
+			}
+			for _, bucket := range page.Data {
+				if !yield(bucket, nil) {
+					return
+				}
+			}
+			if !page.HasMore || page.NextPage == "" {
+				return
+			}
+			pageQuery.Page = page.NextPage
+		}
+	}
+}
+
+// Returns the buckets of every page, starting at q.Page.
+func getAllUsagePages[T any](ctx context.Context, a *AdminClient, path string, q *UsageQuery) ([]UsageBucket[T], error) {
+	var buckets []UsageBucket[T]
+	for bucket, err := range usageBuckets[T](ctx, a, path, q) {
+		if err != nil {
+			return buckets, err
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets, nil
+}
+
+// Utilizes the CompletionsUsage OpenAI Admin API.
+//
+// @Returns a page of completions usage buckets.
+func (a *AdminClient) GetCompletionsUsage(ctx context.Context, q *UsageQuery) (UsagePage[CompletionsUsageResult], error) {
+	return getUsagePage[CompletionsUsageResult](ctx, a, "organization/usage/completions", q)
+}
+
+// Iterates over the completions usage buckets matching q, fetching the pages as needed.
+func (a *AdminClient) CompletionsUsage(ctx context.Context, q *UsageQuery) iter.Seq2[UsageBucket[CompletionsUsageResult], error] {
+	return usageBuckets[CompletionsUsageResult](ctx, a, "organization/usage/completions", q)
+}
+
+// Utilizes the EmbeddingsUsage OpenAI Admin API.
+//
+// @Returns a page of embeddings usage buckets.
+func (a *AdminClient) GetEmbeddingsUsage(ctx context.Context, q *UsageQuery) (UsagePage[EmbeddingsUsageResult], error) {
+	return getUsagePage[EmbeddingsUsageResult](ctx, a, "organization/usage/embeddings", q)
+}
+
+// Iterates over the embeddings usage buckets matching q, fetching the pages as needed.
+func (a *AdminClient) EmbeddingsUsage(ctx context.Context, q *UsageQuery) iter.Seq2[UsageBucket[EmbeddingsUsageResult], error] {
+	return usageBuckets[EmbeddingsUsageResult](ctx, a, "organization/usage/embeddings", q)
+}
+
+// Utilizes the ImagesUsage OpenAI Admin API.
+//
+// @Returns a page of images usage buckets.
+func (a *AdminClient) GetImagesUsage(ctx context.Context, q *UsageQuery) (UsagePage[ImagesUsageResult], error) {
+	return getUsagePage[ImagesUsageResult](ctx, a, "organization/usage/images", q)
+}
+
+// Iterates over the images usage buckets matching q, fetching the pages as needed.
+func (a *AdminClient) ImagesUsage(ctx context.Context, q *UsageQuery) iter.Seq2[UsageBucket[ImagesUsageResult], error] {
+	return usageBuckets[ImagesUsageResult](ctx, a, "organization/usage/images", q)
+}
+
+// Utilizes the AudioSpeechesUsage OpenAI Admin API.
+//
+// @Returns a page of text to speech usage buckets.
+func (a *AdminClient) GetAudioSpeechesUsage(ctx context.Context, q *UsageQuery) (UsagePage[AudioSpeechesUsageResult], error) {
+	return getUsagePage[AudioSpeechesUsageResult](ctx, a, "organization/usage/audio_speeches", q)
+}
+
+// Iterates over the text to speech usage buckets matching q, fetching the pages as needed.
+func (a *AdminClient) AudioSpeechesUsage(ctx context.Context, q *UsageQuery) iter.Seq2[UsageBucket[AudioSpeechesUsageResult], error] {
+	return usageBuckets[AudioSpeechesUsageResult](ctx, a, "organization/usage/audio_speeches", q)
+}
+
+// Utilizes the AudioTranscriptionsUsage OpenAI Admin API.
+//
+// @Returns a page of speech to text usage buckets.
+func (a *AdminClient) GetAudioTranscriptionsUsage(ctx context.Context, q *UsageQuery) (UsagePage[AudioTranscriptionsUsageResult], error) {
+	return getUsagePage[AudioTranscriptionsUsageResult](ctx, a, "organization/usage/audio_transcriptions", q)
+}
+
+// Iterates over the speech to text usage buckets matching q, fetching the pages as needed.
+func (a *AdminClient) AudioTranscriptionsUsage(ctx context.Context, q *UsageQuery) iter.Seq2[UsageBucket[AudioTranscriptionsUsageResult], error] {
+	return usageBuckets[AudioTranscriptionsUsageResult](ctx, a, "organization/usage/audio_transcriptions", q)
+}
+
+// Utilizes the Costs OpenAI Admin API.
+//
+// @Returns a page of daily cost buckets.
+func (a *AdminClient) GetCosts(ctx context.Context, q *UsageQuery) (UsagePage[CostResult], error) {
+	return getUsagePage[CostResult](ctx, a, "organization/costs", q)
+}
+
+// Iterates over the daily cost buckets matching q, fetching the pages as needed.
+func (a *AdminClient) Costs(ctx context.Context, q *UsageQuery) iter.Seq2[UsageBucket[CostResult], error] {
+	return usageBuckets[CostResult](ctx, a, "organization/costs", q)
+}
+
+// The completions usage and costs of a project on a day. Rows that sum up all projects
+// have an empty ProjectID, rows that sum up all days a zero Day.
+type UsageReportRow struct {
+	// Midnight UTC of the day.
+	Day               time.Time
+	ProjectID         string
+	Requests          int64
+	InputTokens       int64
+	CachedInputTokens int64
+	OutputTokens      int64
+	Cost              float64
+	// Currency of Cost, e.g. usd. Empty when there are no costs.
+	Currency string
+}
+
+// Usage and costs per day and project, sorted by day and project.
+type UsageReport []UsageReportRow
+
+type usageReportKey struct {
+	day       time.Time
+	projectID string
+}
+
+// Builds the per day and project report of completions usage and cost buckets. Buckets
+// narrower than a day are summed up into their day. The results should be grouped by
+// project_id, or they are reported without a project. Costs in more than one currency
+// can not be summed up and are reported as an error.
+func NewUsageReport(completions []UsageBucket[CompletionsUsageResult], costs []UsageBucket[CostResult]) (UsageReport, error) {
+	rows := map[usageReportKey]*UsageReportRow{}
+	row := func(startTime int64, projectID string) *UsageReportRow {
+		key := usageReportKey{day: time.Unix(startTime, 0).UTC().Truncate(24 * time.Hour), projectID: projectID}
+		if rows[key] == nil {
+			rows[key] = &UsageReportRow{Day: key.day, ProjectID: projectID}
+		}
+		return rows[key]
+	}
+	for _, bucket := range completions {
+		for _, res := range bucket.Results {
+			r := row(bucket.StartTime, res.ProjectID)
+			r.Requests += res.NumModelRequests
+			r.InputTokens += res.InputTokens
+			r.CachedInputTokens += res.InputCachedTokens
+			r.OutputTokens += res.OutputTokens
+		}
+	}
+	currency := ""
+	for _, bucket := range costs {
+		for _, res := range bucket.Results {
+			if currency != "" && res.Amount.Currency != currency {
+				return nil, fmt.Errorf("costs are reported in %s and %s", currency, res.Amount.Currency)
+			}
+			currency = res.Amount.Currency
+			row(bucket.StartTime, res.ProjectID).Cost += res.Amount.Value
+		}
+	}
+	for _, r := range rows {
+		r.Currency = currency
+	}
+	return collectUsageReport(rows), nil
+}
+
+func collectUsageReport(rows map[usageReportKey]*UsageReportRow) UsageReport {
+	report := make(UsageReport, 0, len(rows))
+	for _, r := range rows {
+		report = append(report, *r)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if !report[i].Day.Equal(report[j].Day) {
+			return report[i].Day.Before(report[j].Day)
+		}
+		return report[i].ProjectID < report[j].ProjectID
+	})
+	return report
+}
+
+func (r UsageReport) group(key func(UsageReportRow) usageReportKey) UsageReport {
+	rows := map[usageReportKey]*UsageReportRow{}
+	for _, row := range r {
+		k := key(row)
+		sum := rows[k]
+		if sum == nil {
+			sum = &UsageReportRow{Day: k.day, ProjectID: k.projectID, Currency: row.Currency}
+			rows[k] = sum
+		}
+		sum.Requests += row.Requests
+		sum.InputTokens += row.InputTokens
+		sum.CachedInputTokens += row.CachedInputTokens
+		sum.OutputTokens += row.OutputTokens
+		sum.Cost += row.Cost
+	}
+	return collectUsageReport(rows)
+}
+
+// Sums up the projects of every day.
+func (r UsageReport) ByDay() UsageReport {
+	return r.group(func(row UsageReportRow) usageReportKey { return usageReportKey{day: row.Day} })
+}
+
+// Sums up the days of every project.
+func (r UsageReport) ByProject() UsageReport {
+	return r.group(func(row UsageReportRow) usageReportKey { return usageReportKey{projectID: row.ProjectID} })
+}
+
+// Returns the sum of all rows.
+func (r UsageReport) Total() UsageReportRow {
+	total := r.group(func(UsageReportRow) usageReportKey { return usageReportKey{} })
+	if len(total) == 0 {
+		return UsageReportRow{}
+	}
+	return total[0]
+}
+
+// Fetches every page of completions usage and costs between start and end, grouped by
+// project, and builds the per day and project report from them. projectIDs optionally
+// restricts the report to the given projects.
+func (a *AdminClient) GetUsageReport(ctx context.Context, start, end time.Time, projectIDs ...string) (UsageReport, error) {
+	q := &UsageQuery{
+		StartTime:   start,
+		EndTime:     end,
+		BucketWidth: UsageBucketWidthDay,
+		ProjectIDs:  projectIDs,
+		GroupBy:     []string{UsageGroupByProjectID},
+	}
+	completions, err := getAllUsagePages[CompletionsUsageResult](ctx, a, "organization/usage/completions", q)
+	if err != nil {
+		return nil, err
+	}
+	costs, err := getAllUsagePages[CostResult](ctx, a, "organization/costs", q)
+	if err != nil {
+		return nil, err
+	}
+	return NewUsageReport(completions, costs)
+}
@@ -0,0 +1,157 @@
+package openai_test
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/EthanCampana/go-openai"
+)
+
+const (
+	day1 = 1730419200 // 2024-11-01 UTC
+	day2 = day1 + 86400
+)
+
+func TestAdminClient_GetUsageReport(t *testing.T) {
+	admin := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("start_time") != fmt.Sprint(day1) || q.Get("bucket_width") != "1d" ||
+			q.Get("group_by[]") != "project_id" || q.Get("project_ids[]") != "proj_a" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		switch r.URL.Path + "?page=" + q.Get("page") {
+		case "/v1/organization/usage/completions?page=":
+			fmt.Fprintf(w, `{"object":"page","has_more":true,"next_page":"page_2","data":[
+				{"start_time":%d,"results":[
+					{"project_id":"proj_a","input_tokens":100,"input_cached_tokens":40,"output_tokens":10,"num_model_requests":2},
+					{"project_id":"proj_b","input_tokens":50,"output_tokens":5,"num_model_requests":1}]}]}`, day1)
+		case "/v1/organization/usage/completions?page=page_2":
+			fmt.Fprintf(w, `{"object":"page","has_more":false,"data":[
+				{"start_time":%d,"results":[{"project_id":"proj_a","input_tokens":200,"output_tokens":20,"num_model_requests":3}]}]}`, day2)
+		case "/v1/organization/costs?page=":
+			fmt.Fprintf(w, `{"object":"page","has_more":false,"data":[
+				{"start_time":%d,"results":[{"project_id":"proj_a","amount":{"value":0.5,"currency":"usd"}},{"project_id":"proj_b","amount":{"value":0.25,"currency":"usd"}}]},
+				{"start_time":%d,"results":[{"project_id":"proj_a","amount":{"value":1,"currency":"usd"}}]}]}`, day1, day2)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	})
+
+	report, err := admin.GetUsageReport(context.Background(), time.Unix(day1, 0), time.Unix(day2+86400, 0), "proj_a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d1, d2 := time.Unix(day1, 0).UTC(), time.Unix(day2, 0).UTC()
+	tests := []struct {
+		name string
+		got  UsageReport
+		want UsageReport
+	}{
+		{"by day and project", report, UsageReport{
+			{Day: d1, ProjectID: "proj_a", Requests: 2, InputTokens: 100, CachedInputTokens: 40, OutputTokens: 10, Cost: 0.5, Currency: "usd"},
+			{Day: d1, ProjectID: "proj_b", Requests: 1, InputTokens: 50, OutputTokens: 5, Cost: 0.25, Currency: "usd"},
+			{Day: d2, ProjectID: "proj_a", Requests: 3, InputTokens: 200, OutputTokens: 20, Cost: 1, Currency: "usd"},
+		}},
+		{"by day", report.ByDay(), UsageReport{
+			{Day: d1, Requests: 3, InputTokens: 150, CachedInputTokens: 40, OutputTokens: 15, Cost: 0.75, Currency: "usd"},
+			{Day: d2, Requests: 3, InputTokens: 200, OutputTokens: 20, Cost: 1, Currency: "usd"},
+		}},
+		{"by project", report.ByProject(), UsageReport{
+			{ProjectID: "proj_a", Requests: 5, InputTokens: 300, CachedInputTokens: 40, OutputTokens: 30, Cost: 1.5, Currency: "usd"},
+			{ProjectID: "proj_b", Requests: 1, InputTokens: 50, OutputTokens: 5, Cost: 0.25, Currency: "usd"},
+		}},
+		{"total", UsageReport{report.Total()}, UsageReport{
+			{Requests: 6, InputTokens: 350, CachedInputTokens: 40, OutputTokens: 35, Cost: 1.75, Currency: "usd"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.got) != len(tt.want) {
+				t.Fatalf("got %+v, want %+v", tt.got, tt.want)
+			}
+			for i, want := range tt.want {
+				got := tt.got[i]
+				if !got.Day.Equal(want.Day) || math.Abs(got.Cost-want.Cost) > 1e-9 {
+					t.Errorf("row %d = %+v, want %+v", i, got, want)
+				}
+				got.Day, got.Cost, want.Day, want.Cost = time.Time{}, 0, time.Time{}, 0
+				if got != want {
+					t.Errorf("row %d = %+v, want %+v", i, tt.got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAdminClient_GetCompletionsUsage(t *testing.T) {
+	admin := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/organization/usage/completions" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		q := r.URL.Query()
+		if q.Get("batch") != "true" || q.Get("bucket_width") != "1h" || len(q["models[]"]) != 2 || q.Get("limit") != "24" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"object":"page","data":[{"start_time":1,"end_time":3601,"results":[{"model":"gpt-4o","batch":true,"input_tokens":7}]}]}`)
+	})
+	batch := true
+	page, err := admin.GetCompletionsUsage(context.Background(), &UsageQuery{
+		StartTime:   time.Unix(1, 0),
+		BucketWidth: UsageBucketWidthHour,
+		Models:      []string{"gpt-4o", "gpt-4o-mini"},
+		Batch:       &batch,
+		Limit:       24,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := page.Data[0].Results[0]
+	if res.Model != "gpt-4o" || res.Batch == nil || !*res.Batch || res.InputTokens != 7 {
+		t.Errorf("GetCompletionsUsage() = %+v", page)
+	}
+	if _, err = admin.GetCosts(context.Background(), &UsageQuery{}); err == nil {
+		t.Error("GetCosts() without start time succeeded")
+	}
+}
+
+func TestAdminClient_CompletionsUsage(t *testing.T) {
+	admin := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "":
+			fmt.Fprint(w, `{"object":"page","has_more":true,"next_page":"p2","data":[{"start_time":1,"results":[]},{"start_time":2,"results":[]}]}`)
+		case "p2":
+			fmt.Fprint(w, `{"object":"page","has_more":false,"data":[{"start_time":3,"results":[]}]}`)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	})
+	var starts []int64
+	for bucket, err := range admin.CompletionsUsage(context.Background(), &UsageQuery{StartTime: time.Unix(1, 0)}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		starts = append(starts, bucket.StartTime)
+	}
+	if fmt.Sprint(starts) != "[1 2 3]" {
+		t.Errorf("CompletionsUsage() buckets start at %v", starts)
+	}
+	for _, err := range admin.Costs(context.Background(), nil) {
+		if err == nil {
+			t.Error("Costs() without query succeeded")
+		}
+	}
+}
+
+func TestNewUsageReport_MixedCurrencies(t *testing.T) {
+	costs := []UsageBucket[CostResult]{
+		{StartTime: day1, Results: []CostResult{{Amount: CostAmount{Value: 1, Currency: "usd"}}}},
+		{StartTime: day2, Results: []CostResult{{Amount: CostAmount{Value: 1, Currency: "eur"}}}},
+	}
+	if report, err := NewUsageReport(nil, costs); err == nil {
+		t.Errorf("NewUsageReport() = %+v, want an error for mixed currencies", report)
+	}
+}
@@ -0,0 +1,484 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	VectorStoreStatusExpired    = "expired"
+	VectorStoreStatusInProgress = "in_progress"
+	VectorStoreStatusCompleted  = "completed"
+	VectorStoreStatusCancelled  = "cancelled"
+	VectorStoreStatusFailed     = "failed"
+)
+
+const (
+	ChunkingStrategyAuto   = "auto"
+	ChunkingStrategyStatic = "static"
+)
+
+// Number of files UploadDirToVectorStore uploads concurrently.
+const vectorStoreUploadConcurrency = 4
+
+// Maximum number of file ids of a single file batch request.
+const MaxVectorStoreFileBatchSize = 500
+
+type VectorStoreFileCounts struct {
+	InProgress int `json:"in_progress"`
+	Completed  int `json:"completed"`
+	Failed     int `json:"failed"`
+	Cancelled  int `json:"cancelled"`
+	Total      int `json:"total"`
+}
+
+// Expires a vector store Days after the anchor, which is always "last_active_at".
+type VectorStoreExpiration struct {
+	Anchor string `json:"anchor"`
+	Days   int    `json:"days"`
+}
+
+type StaticChunking struct {
+	MaxChunkSizeTokens int `json:"max_chunk_size_tokens"`
+	ChunkOverlapTokens int `json:"chunk_overlap_tokens"`
+}
+
+// How files are split into chunks, ChunkingStrategyAuto or ChunkingStrategyStatic.
+type ChunkingStrategy struct {
+	Type   string          `json:"type"`
+	Static *StaticChunking `json:"static,omitempty"`
+}
+
+type VectorStore struct {
+	ID           string                 `json:"id"`
+	Object       string                 `json:"object"`
+	CreatedAt    int64                  `json:"created_at"`
+	Name         string                 `json:"name"`
+	UsageBytes   int64                  `json:"usage_bytes"`
+	FileCounts   VectorStoreFileCounts  `json:"file_counts"`
+	Status       string                 `json:"status"`
+	ExpiresAfter *VectorStoreExpiration `json:"expires_after,omitempty"`
+	ExpiresAt    int64                  `json:"expires_at,omitempty"`
+	LastActiveAt int64                  `json:"last_active_at,omitempty"`
+	Metadata     map[string]string      `json:"metadata,omitempty"`
+}
+
+// Creates a vector store, optionally indexing FileIDs right away.
+type VectorStoreRequest struct {
+	Name             string                 `json:"name,omitempty"`
+	FileIDs          []string               `json:"file_ids,omitempty"`
+	ExpiresAfter     *VectorStoreExpiration `json:"expires_after,omitempty"`
+	ChunkingStrategy *ChunkingStrategy      `json:"chunking_strategy,omitempty"`
+	Metadata         map[string]string      `json:"metadata,omitempty"`
+}
+
+// Generates the correct http.Request object for the given API Request Struct.
+func (vsr *VectorStoreRequest) GenerateHTTPRequest(ctx context.Context) (*http.Request, error) {
+	return newJSONRequest(ctx, http.MethodPost, "vector_stores", vsr)
+}
+
+// Modifies a vector store. Fields left empty are not changed.
+type VectorStoreModifyRequest struct {
+	VectorStoreID string                 `json:"-"`
+	Name          string                 `json:"name,omitempty"`
+	ExpiresAfter  *VectorStoreExpiration `json:"expires_after,omitempty"`
+	Metadata      map[string]string      `json:"metadata,omitempty"`
+}
+
+// Generates the correct http.Request object for the given API Request Struct.
+func (vsr *VectorStoreModifyRequest) GenerateHTTPRequest(ctx context.Context) (*http.Request, error) {
+	return newJSONRequest(ctx, http.MethodPost, vectorStorePath(vsr.VectorStoreID), vsr)
+}
+
+type VectorStoreFileError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type VectorStoreFile struct {
+	ID               string                 `json:"id"`
+	Object           string                 `json:"object"`
+	CreatedAt        int64                  `json:"created_at"`
+	VectorStoreID    string                 `json:"vector_store_id"`
+	Status           string                 `json:"status"`
+	UsageBytes       int64                  `json:"usage_bytes"`
+	LastError        *VectorStoreFileError  `json:"last_error,omitempty"`
+	ChunkingStrategy *ChunkingStrategy      `json:"chunking_strategy,omitempty"`
+	Attributes       map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// Attaches an uploaded file to a vector store.
+type VectorStoreFileRequest struct {
+	VectorStoreID    string                 `json:"-"`
+	FileID           string                 `json:"file_id"`
+	ChunkingStrategy *ChunkingStrategy      `json:"chunking_strategy,omitempty"`
+	Attributes       map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// Generates the correct http.Request object for the given API Request Struct.
+func (vfr *VectorStoreFileRequest) GenerateHTTPRequest(ctx context.Context) (*http.Request, error) {
+	return newJSONRequest(ctx, http.MethodPost, vectorStorePath(vfr.VectorStoreID, "files"), vfr)
+}
+
+type VectorStoreFileBatch struct {
+	ID            string                `json:"id"`
+	Object        string                `json:"object"`
+	CreatedAt     int64                 `json:"created_at"`
+	VectorStoreID string                `json:"vector_store_id"`
+	Status        string                `json:"status"`
+	FileCounts    VectorStoreFileCounts `json:"file_counts"`
+}
+
+// Attaches several uploaded files to a vector store at once.
+type VectorStoreFileBatchRequest struct {
+	VectorStoreID    string                 `json:"-"`
+	FileIDs          []string               `json:"file_ids"`
+	ChunkingStrategy *ChunkingStrategy      `json:"chunking_strategy,omitempty"`
+	Attributes       map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// Generates the correct http.Request object for the given API Request Struct.
+func (vbr *VectorStoreFileBatchRequest) GenerateHTTPRequest(ctx context.Context) (*http.Request, error) {
+	return newJSONRequest(ctx, http.MethodPost, vectorStorePath(vbr.VectorStoreID, "file_batches"), vbr)
+}
+
+type VectorStoreRankingOptions struct {
+	Ranker         string   `json:"ranker,omitempty"`
+	ScoreThreshold *float64 `json:"score_threshold,omitempty"`
+}
+
+// Searches the chunks of a vector store.
+type VectorStoreSearchRequest struct {
+	VectorStoreID string `json:"-"`
+	Query         string `json:"query"`
+	MaxNumResults int    `json:"max_num_results,omitempty"`
+	RewriteQuery  bool   `json:"rewrite_query,omitempty"`
+	// A comparison or compound filter on the file attributes.
+	Filters        interface{}                `json:"filters,omitempty"`
+	RankingOptions *VectorStoreRankingOptions `json:"ranking_options,omitempty"`
+}
+
+// Generates the correct http.Request object for the given API Request Struct.
+func (vsr *VectorStoreSearchRequest) GenerateHTTPRequest(ctx context.Context) (*http.Request, error) {
+	return newJSONRequest(ctx, http.MethodPost, vectorStorePath(vsr.VectorStoreID, "search"), vsr)
+}
+
+type VectorStoreSearchContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type VectorStoreSearchResult struct {
+	FileID     string                     `json:"file_id"`
+	Filename   string                     `json:"filename"`
+	Score      float64                    `json:"score"`
+	Attributes map[string]interface{}     `json:"attributes,omitempty"`
+	Content    []VectorStoreSearchContent `json:"content"`
+}
+
+type VectorStoreSearchResponse struct {
+	Object      string                    `json:"object"`
+	SearchQuery []string                  `json:"search_query"`
+	Data        []VectorStoreSearchResult `json:"data"`
+	HasMore     bool                      `json:"has_more"`
+	NextPage    string                    `json:"next_page,omitempty"`
+}
+
+func vectorStorePath(vectorStoreID string, parts ...string) string {
+	return strings.Join(append([]string{"vector_stores", vectorStoreID}, parts...), "/")
+}
+
+// Utilizes the CreateVectorStore OpenAI API.
+//
+// @Returns the created openai.VectorStore.
+func (c *Client) CreateVectorStore(ctx context.Context, vsReq *VectorStoreRequest) (VectorStore, error) {
+	var res VectorStore
+	req, err := vsReq.GenerateHTTPRequest(ctx)
+	if err != nil {
+		return res, err
+	}
+	err = c.SendRequest(req, &res)
+	return res, err
+}
+
+// Utilizes the RetrieveVectorStore OpenAI API.
+//
+// @Returns openai.VectorStore.
+func (c *Client) GetVectorStore(ctx context.Context, vectorStoreID string) (VectorStore, error) {
+	var res VectorStore
+	err := c.sendJSON(ctx, http.MethodGet, vectorStorePath(vectorStoreID), nil, &res)
+	return res, err
+}
+
+// Utilizes the ModifyVectorStore OpenAI API.
+//
+// @Returns the modified openai.VectorStore.
+func (c *Client) ModifyVectorStore(ctx context.Context, vsReq *VectorStoreModifyRequest) (VectorStore, error) {
+	var res VectorStore
+	req, err := vsReq.GenerateHTTPRequest(ctx)
+	if err != nil {
+		return res, err
+	}
+	err = c.SendRequest(req, &res)
+	return res, err
+}
+
+// Utilizes the DeleteVectorStore OpenAI API. The files themselves are not deleted.
+//
+// @Returns openai.DeletionStatus.
+func (c *Client) DeleteVectorStore(ctx context.Context, vectorStoreID string) (DeletionStatus, error) {
+	var res DeletionStatus
+	err := c.sendJSON(ctx, http.MethodDelete, vectorStorePath(vectorStoreID), nil, &res)
+	return res, err
+}
+
+// Utilizes the ListVectorStores OpenAI API. params may be nil.
+//
+// @Returns a page of openai.VectorStore.
+func (c *Client) ListVectorStores(ctx context.Context, params *ListParams) (List[VectorStore], error) {
+	var res List[VectorStore]
+	err := c.sendJSON(ctx, http.MethodGet, params.encode("vector_stores"), nil, &res)
+	return res, err
+}
+
+// Iterates over every openai.VectorStore, fetching the pages as needed. params may be nil.
+func (c *Client) VectorStores(ctx context.Context, params *ListParams) iter.Seq2[VectorStore, error] {
+	return paginate(ctx, params, c.ListVectorStores)
+}
+
+// Utilizes the CreateVectorStoreFile OpenAI API to attach a file to a vector store.
+//
+// @Returns openai.VectorStoreFile, usually still in progress.
+func (c *Client) CreateVectorStoreFile(ctx context.Context, fileReq *VectorStoreFileRequest) (VectorStoreFile, error) {
+	var res VectorStoreFile
+	req, err := fileReq.GenerateHTTPRequest(ctx)
+	if err != nil {
+		return res, err
+	}
+	err = c.SendRequest(req, &res)
+	return res, err
+}
+
+// Utilizes the RetrieveVectorStoreFile OpenAI API.
+//
+// @Returns openai.VectorStoreFile.
+func (c *Client) GetVectorStoreFile(ctx context.Context, vectorStoreID, fileID string) (VectorStoreFile, error) {
+	var res VectorStoreFile
+	err := c.sendJSON(ctx, http.MethodGet, vectorStorePath(vectorStoreID, "files", fileID), nil, &res)
+	return res, err
+}
+
+// Utilizes the DeleteVectorStoreFile OpenAI API to detach a file from a vector store.
+//
+// @Returns openai.DeletionStatus.
+func (c *Client) DeleteVectorStoreFile(ctx context.Context, vectorStoreID, fileID string) (DeletionStatus, error) {
+	var res DeletionStatus
+	err := c.sendJSON(ctx, http.MethodDelete, vectorStorePath(vectorStoreID, "files", fileID), nil, &res)
+	return res, err
+}
+
+// Utilizes the ListVectorStoreFiles OpenAI API. params may be nil.
+//
+// @Returns a page of openai.VectorStoreFile.
+func (c *Client) ListVectorStoreFiles(ctx context.Context, vectorStoreID string, params *ListParams) (List[VectorStoreFile], error) {
+	var res List[VectorStoreFile]
+	err := c.sendJSON(ctx, http.MethodGet, params.encode(vectorStorePath(vectorStoreID, "files")), nil, &res)
+	return res, err
+}
+
+// Iterates over every openai.VectorStoreFile of the vector store, fetching the pages as needed. params may be nil.
+func (c *Client) VectorStoreFiles(ctx context.Context, vectorStoreID string, params *ListParams) iter.Seq2[VectorStoreFile, error] {
+	return paginate(ctx, params, func(ctx context.Context, params *ListParams) (List[VectorStoreFile], error) {
+		return c.ListVectorStoreFiles(ctx, vectorStoreID, params)
+	})
+}
+
+// Utilizes the CreateVectorStoreFileBatch OpenAI API.
+//
+// @Returns openai.VectorStoreFileBatch, use WaitForVectorStoreFileBatch to wait for the indexing.
+func (c *Client) CreateVectorStoreFileBatch(ctx context.Context, batchReq *VectorStoreFileBatchRequest) (VectorStoreFileBatch, error) {
+	var res VectorStoreFileBatch
+	req, err := batchReq.GenerateHTTPRequest(ctx)
+	if err != nil {
+		return res, err
+	}
+	err = c.SendRequest(req, &res)
+	return res, err
+}
+
+// Utilizes the RetrieveVectorStoreFileBatch OpenAI API.
+//
+// @Returns openai.VectorStoreFileBatch.
+func (c *Client) GetVectorStoreFileBatch(ctx context.Context, vectorStoreID, batchID string) (VectorStoreFileBatch, error) {
+	var res VectorStoreFileBatch
+	err := c.sendJSON(ctx, http.MethodGet, vectorStorePath(vectorStoreID, "file_batches", batchID), nil, &res)
+	return res, err
+}
+
+// Utilizes the CancelVectorStoreFileBatch OpenAI API.
+//
+// @Returns openai.VectorStoreFileBatch.
+func (c *Client) CancelVectorStoreFileBatch(ctx context.Context, vectorStoreID, batchID string) (VectorStoreFileBatch, error) {
+	var res VectorStoreFileBatch
+	err := c.sendJSON(ctx, http.MethodPost, vectorStorePath(vectorStoreID, "file_batches", batchID, "cancel"), nil, &res)
+	return res, err
+}
+
+// Utilizes the ListVectorStoreFilesInBatch OpenAI API. params may be nil.
+//
+// @Returns a page of openai.VectorStoreFile.
+func (c *Client) ListVectorStoreFileBatchFiles(ctx context.Context, vectorStoreID, batchID string, params *ListParams) (List[VectorStoreFile], error) {
+	var res List[VectorStoreFile]
+	path := vectorStorePath(vectorStoreID, "file_batches", batchID, "files")
+	err := c.sendJSON(ctx, http.MethodGet, params.encode(path), nil, &res)
+	return res, err
+}
+
+// Iterates over every openai.VectorStoreFile of the file batch, fetching the pages as needed. params may be nil.
+func (c *Client) VectorStoreFileBatchFiles(ctx context.Context, vectorStoreID, batchID string, params *ListParams) iter.Seq2[VectorStoreFile, error] {
+	return paginate(ctx, params, func(ctx context.Context, params *ListParams) (List[VectorStoreFile], error) {
+		return c.ListVectorStoreFileBatchFiles(ctx, vectorStoreID, batchID, params)
+	})
+}
+
+// Polls the file batch every interval until it is no longer in progress. An interval of 0
+// uses DefaultPollInterval.
+//
+// @Returns the last retrieved openai.VectorStoreFileBatch, check its Status and FileCounts.
+func (c *Client) WaitForVectorStoreFileBatch(ctx context.Context, vectorStoreID, batchID string, interval time.Duration) (VectorStoreFileBatch, error) {
+	var batch VectorStoreFileBatch
+	err := poll(ctx, interval, func() (done bool, err error) {
+		batch, err = c.GetVectorStoreFileBatch(ctx, vectorStoreID, batchID)
+		return batch.Status != VectorStoreStatusInProgress, err
+	})
+	return batch, err
+}
+
+// Utilizes the SearchVectorStore OpenAI API.
+//
+// @Returns openai.VectorStoreSearchResponse with the matching chunks, best match first.
+func (c *Client) SearchVectorStore(ctx context.Context, searchReq *VectorStoreSearchRequest) (VectorStoreSearchResponse, error) {
+	var res VectorStoreSearchResponse
+	req, err := searchReq.GenerateHTTPRequest(ctx)
+	if err != nil {
+		return res, err
+	}
+	err = c.SendRequest(req, &res)
+	return res, err
+}
+
+// Uploads every regular file below dir, skipping hidden files and directories, attaches them
+// to the vector store in file batches of at most MaxVectorStoreFileBatchSize files and waits
+// until the batches are indexed. If an upload or creating a batch fails, the uploaded files
+// not yet attached to the vector store are deleted again.
+//
+// @Returns the finished []openai.VectorStoreFileBatch. Files that failed to index are counted
+// in their FileCounts, they do not make the call fail.
+func (c *Client) UploadDirToVectorStore(ctx context.Context, vectorStoreID, dir string) ([]VectorStoreFileBatch, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%s contains no files", dir)
+	}
+	fileIDs, err := c.uploadFiles(ctx, paths, FilePurposeAssistants)
+	if err != nil {
+		return nil, c.deleteFiles(ctx, fileIDs, err)
+	}
+	var batches []VectorStoreFileBatch
+	for start := 0; start < len(fileIDs); start += MaxVectorStoreFileBatchSize {
+		end := min(start+MaxVectorStoreFileBatchSize, len(fileIDs))
+		batch, err := c.CreateVectorStoreFileBatch(ctx, &VectorStoreFileBatchRequest{
+			VectorStoreID: vectorStoreID,
+			FileIDs:       fileIDs[start:end],
+		})
+		if err != nil {
+			return batches, c.deleteFiles(ctx, fileIDs[start:], err)
+		}
+		batches = append(batches, batch)
+	}
+	for i, batch := range batches {
+		if batches[i], err = c.WaitForVectorStoreFileBatch(ctx, vectorStoreID, batch.ID, 0); err != nil {
+			return batches, err
+		}
+	}
+	return batches, nil
+}
+
+// Deletes the uploaded files after err, even if ctx is done. Empty ids are skipped.
+//
+// @Returns err, joined with the errors of the deletions.
+func (c *Client) deleteFiles(ctx context.Context, fileIDs []string, err error) error {
+	ctx = context.WithoutCancel(ctx)
+	errs := []error{err}
+	for _, id := range fileIDs {
+		if id == "" {
+			continue
+		}
+		if _, delErr := c.DeleteFile(ctx, id); delErr != nil {
+			errs = append(errs, fmt.Errorf("deleting uploaded file %s: %w", id, delErr))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Uploads the files concurrently and returns their ids in the order of paths. On error the
+// ids of the files uploaded before are returned too, failed and skipped uploads are empty.
+// After a failure no new upload is started, but the running ones are left to finish so
+// that every file stored by the API has its id returned.
+func (c *Client) uploadFiles(ctx context.Context, paths []string, purpose string) ([]string, error) {
+	stop, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ids := make([]string, len(paths))
+	errs := make([]error, len(paths))
+	sem := make(chan struct{}, vectorStoreUploadConcurrency)
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		wg.Add(1)
+		go func(i int, path string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if stop.Err() != nil {
+				return
+			}
+			file, err := c.UploadFile(ctx, &FileRequest{FilePath: path, Purpose: purpose})
+			if err != nil {
+				errs[i] = fmt.Errorf("uploading %s: %w", path, err)
+				cancel()
+				return
+			}
+			ids[i] = file.ID
+		}(i, path)
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return ids, err
+	}
+	// Uploads may only have been skipped because ctx is done.
+	return ids, ctx.Err()
+}
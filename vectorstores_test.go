@@ -0,0 +1,204 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/EthanCampana/go-openai"
+)
+
+func TestClient_UploadDirToVectorStore(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"a.md":          "alpha",
+		"sub/b.txt":     "beta",
+		".hidden":       "skip",
+		".git/config":   "skip",
+		"sub/.DS_Store": "skip",
+	} {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var mu sync.Mutex
+	var uploaded []string
+	var batchFiles []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/v1/files":
+			if r.FormValue("purpose") != FilePurposeAssistants {
+				t.Errorf("purpose = %q", r.FormValue("purpose"))
+			}
+			f, header, err := r.FormFile("file")
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.Close()
+			uploaded = append(uploaded, header.Filename)
+			fmt.Fprintf(w, `{"id":"file-%s","filename":%q}`, header.Filename, header.Filename)
+		case "/v1/vector_stores/vs_1/file_batches":
+			var body struct {
+				FileIDs []string `json:"file_ids"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			batchFiles = body.FileIDs
+			fmt.Fprint(w, `{"id":"vsfb_1","vector_store_id":"vs_1","status":"in_progress"}`)
+		case "/v1/vector_stores/vs_1/file_batches/vsfb_1":
+			fmt.Fprint(w, `{"id":"vsfb_1","status":"completed","file_counts":{"completed":2,"total":2}}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	batches, err := client.UploadDirToVectorStore(ctx, "vs_1", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) != 1 || batches[0].Status != VectorStoreStatusCompleted || batches[0].FileCounts.Completed != 2 {
+		t.Errorf("UploadDirToVectorStore() = %+v", batches)
+	}
+	sort.Strings(uploaded)
+	if strings.Join(uploaded, ",") != "a.md,b.txt" {
+		t.Errorf("uploaded files = %v, want a.md and b.txt", uploaded)
+	}
+	if strings.Join(batchFiles, ",") != "file-a.md,file-b.txt" {
+		t.Errorf("batch file ids = %v", batchFiles)
+	}
+}
+
+// Serves uploads, deletions and file batches, failing the upload of fail.txt and,
+// with failBatch, the creation of file batches.
+func newVectorStoreUploadClient(t *testing.T, failBatch bool) (*Client, func() (uploaded, deleted []string, batches [][]string)) {
+	var mu sync.Mutex
+	var uploaded, deleted []string
+	var batches [][]string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.URL.Path == "/v1/files":
+			_, header, err := r.FormFile("file")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if header.Filename == "fail.txt" {
+				http.Error(w, `{"error":{"message":"bad file"}}`, http.StatusBadRequest)
+				return
+			}
+			uploaded = append(uploaded, "file-"+header.Filename)
+			fmt.Fprintf(w, `{"id":"file-%s"}`, header.Filename)
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/v1/files/"):
+			id := strings.TrimPrefix(r.URL.Path, "/v1/files/")
+			deleted = append(deleted, id)
+			fmt.Fprintf(w, `{"id":%q,"deleted":true}`, id)
+		case r.URL.Path == "/v1/vector_stores/vs_1/file_batches":
+			if failBatch {
+				http.Error(w, `{"error":{"message":"too many files"}}`, http.StatusBadRequest)
+				return
+			}
+			var body struct {
+				FileIDs []string `json:"file_ids"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			batches = append(batches, body.FileIDs)
+			fmt.Fprintf(w, `{"id":"vsfb_%d","status":"in_progress"}`, len(batches))
+		case strings.HasPrefix(r.URL.Path, "/v1/vector_stores/vs_1/file_batches/"):
+			fmt.Fprintf(w, `{"id":%q,"status":"completed"}`, strings.TrimPrefix(r.URL.Path, "/v1/vector_stores/vs_1/file_batches/"))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	return client, func() ([]string, []string, [][]string) {
+		mu.Lock()
+		defer mu.Unlock()
+		sort.Strings(uploaded)
+		sort.Strings(deleted)
+		return uploaded, deleted, batches
+	}
+}
+
+func TestClient_UploadDirToVectorStore_Cleanup(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     []string
+		failBatch bool
+	}{
+		{"failed upload", []string{"a.txt", "b.txt", "fail.txt"}, false},
+		{"failed batch", []string{"a.txt", "b.txt"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range tt.files {
+				os.WriteFile(filepath.Join(dir, name), []byte(name), 0o644)
+			}
+			client, requests := newVectorStoreUploadClient(t, tt.failBatch)
+			if _, err := client.UploadDirToVectorStore(context.Background(), "vs_1", dir); err == nil {
+				t.Fatal("UploadDirToVectorStore() succeeded")
+			}
+			uploaded, deleted, _ := requests()
+			if strings.Join(deleted, ",") != strings.Join(uploaded, ",") {
+				t.Errorf("deleted %v, want the uploaded files %v", deleted, uploaded)
+			}
+		})
+	}
+}
+
+func TestClient_UploadDirToVectorStore_Batches(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i <= MaxVectorStoreFileBatchSize; i++ {
+		os.WriteFile(filepath.Join(dir, fmt.Sprintf("%03d.txt", i)), []byte("x"), 0o644)
+	}
+	client, requests := newVectorStoreUploadClient(t, false)
+	batches, err := client.UploadDirToVectorStore(context.Background(), "vs_1", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, deleted, sent := requests()
+	if len(batches) != 2 || batches[1].ID != "vsfb_2" || batches[1].Status != VectorStoreStatusCompleted || len(deleted) != 0 {
+		t.Errorf("UploadDirToVectorStore() = %+v, deleted %v", batches, deleted)
+	}
+	if len(sent) != 2 || len(sent[0]) != MaxVectorStoreFileBatchSize || len(sent[1]) != 1 {
+		t.Errorf("file batches of %d and %d files", len(sent[0]), len(sent[len(sent)-1]))
+	}
+}
+
+func TestClient_SearchVectorStore(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/vector_stores/vs_1/search" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		if beta := r.Header.Get("OpenAI-Beta"); beta != "assistants=v2" {
+			t.Errorf("OpenAI-Beta = %q", beta)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["query"] != "refund policy" || body["vector_store_id"] != nil {
+			t.Errorf("request body = %v", body)
+		}
+		fmt.Fprint(w, `{"object":"vector_store.search_results.page","search_query":["refund policy"],`+
+			`"data":[{"file_id":"file-1","filename":"policy.md","score":0.9,"content":[{"type":"text","text":"30 days"}]}]}`)
+	})
+	res, err := client.SearchVectorStore(context.Background(), &VectorStoreSearchRequest{VectorStoreID: "vs_1", Query: "refund policy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Data) != 1 || res.Data[0].Content[0].Text != "30 days" {
+		t.Errorf("SearchVectorStore() = %+v", res)
+	}
+}
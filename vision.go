@@ -0,0 +1,101 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+)
+
+const (
+	ChatMessagePartTypeText     = "text"
+	ChatMessagePartTypeImageURL = "image_url"
+)
+
+const (
+	ImageDetailAuto = "auto"
+	ImageDetailLow  = "low"
+	ImageDetailHigh = "high"
+)
+
+type ChatMessageImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// A single part of a multi-part message, either text or an image.
+type ChatMessagePart struct {
+	Type     string               `json:"type"`
+	Text     string               `json:"text,omitempty"`
+	ImageURL *ChatMessageImageURL `json:"image_url,omitempty"`
+}
+
+// Creates a user message from the given parts.
+func NewMultiPartMessage(parts ...ChatMessagePart) ChatMessage {
+	return ChatMessage{Role: ChatMessageRoleUser, MultiContent: parts}
+}
+
+// Creates a text part.
+func NewTextPart(text string) ChatMessagePart {
+	return ChatMessagePart{Type: ChatMessagePartTypeText, Text: text}
+}
+
+// Creates an image part from a http(s) or data URL.
+//
+// detail: ImageDetailAuto, ImageDetailLow or ImageDetailHigh. Empty leaves the API default.
+func NewImageURLPart(url, detail string) ChatMessagePart {
+	return ChatMessagePart{
+		Type:     ChatMessagePartTypeImageURL,
+		ImageURL: &ChatMessageImageURL{URL: url, Detail: detail},
+	}
+}
+
+// Creates an image part from encoded image bytes, sent inline as a base64 data URL.
+// The MIME type is sniffed from the content and must be png, jpeg, gif or webp.
+func NewImageBytesPart(b []byte, detail string) (ChatMessagePart, error) {
+	mime, ok := imageMIMEType(b)
+	if !ok {
+		return ChatMessagePart{}, fmt.Errorf("unsupported image type %s", mime)
+	}
+	url := fmt.Sprintf("data:%s;base64,%s", mime, base64.StdEncoding.EncodeToString(b))
+	return NewImageURLPart(url, detail), nil
+}
+
+// Creates an image part from the image file at path.
+func NewImageFilePart(path, detail string) (ChatMessagePart, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return ChatMessagePart{}, err
+	}
+	part, err := NewImageBytesPart(b, detail)
+	if err != nil {
+		return part, fmt.Errorf("%s: %w", path, err)
+	}
+	return part, nil
+}
+
+// Creates an image part from a decoded image, encoded as png. Images larger than maxSide
+// pixels on their longest side are downscaled first, 0 keeps the original size.
+func NewImagePart(img image.Image, maxSide int, detail string) (ChatMessagePart, error) {
+	img = ResizeImage(img, maxSide)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return ChatMessagePart{}, err
+	}
+	return NewImageBytesPart(buf.Bytes(), detail)
+}
+
+// Creates an image part from a generated image so it can be fed back into a vision model.
+// URL results are referenced directly, b64_json results are sent inline.
+func (d ImageData) ChatPart(detail string) (ChatMessagePart, error) {
+	if d.B64JSON == "" && d.URL != "" {
+		return NewImageURLPart(d.URL, detail), nil
+	}
+	b, err := d.Bytes()
+	if err != nil {
+		return ChatMessagePart{}, err
+	}
+	return NewImageBytesPart(b, detail)
+}
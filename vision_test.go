@@ -0,0 +1,108 @@
+package openai_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/EthanCampana/go-openai"
+)
+
+func TestChatMessage_JSON(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  ChatMessage
+		want string
+	}{
+		{
+			name: "Text",
+			msg:  ChatMessage{Role: ChatMessageRoleUser, Content: "hi"},
+			want: `{"content":"hi","role":"user"}`,
+		},
+		{
+			name: "Empty tool result keeps content",
+			msg:  NewToolMessage("call_1", ""),
+			want: `{"content":"","role":"tool","tool_call_id":"call_1"}`,
+		},
+		{
+			name: "Assistant tool calls omit content",
+			msg:  ChatMessage{Role: ChatMessageRoleAssistant, ToolCalls: []ToolCall{{ID: "call_1", Type: ToolTypeFunction}}},
+			want: `{"role":"assistant","tool_calls":[{"id":"call_1","type":"function","function":{}}]}`,
+		},
+		{
+			name: "Multi part",
+			msg:  NewMultiPartMessage(NewTextPart("What is this?"), NewImageURLPart("https://example.com/a.png", ImageDetailLow)),
+			want: `{"content":[{"type":"text","text":"What is this?"},{"type":"image_url","image_url":{"url":"https://example.com/a.png","detail":"low"}}],"role":"user"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.msg)
+			if err != nil || string(b) != tt.want {
+				t.Fatalf("json.Marshal(ChatMessage) = %s, %v, want %s", b, err, tt.want)
+			}
+			var got ChatMessage
+			if err = json.Unmarshal(b, &got); err != nil {
+				t.Fatal(err)
+			}
+			if again, _ := json.Marshal(got); string(again) != tt.want {
+				t.Errorf("round trip = %s, want %s", again, tt.want)
+			}
+		})
+	}
+}
+
+func TestImageParts(t *testing.T) {
+	raw := testPNG(t)
+	want := "data:image/png;base64," + base64.StdEncoding.EncodeToString(raw)
+
+	path := filepath.Join(t.TempDir(), "img.png")
+	if err := os.WriteFile(path, raw, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	part, err := NewImageFilePart(path, ImageDetailHigh)
+	if err != nil || part.ImageURL.URL != want || part.ImageURL.Detail != ImageDetailHigh {
+		t.Errorf("NewImageFilePart() = %+v, %v", part, err)
+	}
+
+	part, err = ImageData{B64JSON: base64.StdEncoding.EncodeToString(raw)}.ChatPart("")
+	if err != nil || part.ImageURL.URL != want {
+		t.Errorf("ImageData.ChatPart() = %+v, %v", part, err)
+	}
+
+	if _, err = NewImageBytesPart([]byte("plain text"), ""); err == nil {
+		t.Error("NewImageBytesPart() expected error for non image bytes")
+	}
+
+	part, err = NewImagePart(image.NewRGBA(image.Rect(0, 0, 400, 100)), 200, ImageDetailAuto)
+	if err != nil || !strings.HasPrefix(part.ImageURL.URL, "data:image/png;base64,") {
+		t.Fatalf("NewImagePart() = %+v, %v", part, err)
+	}
+}
+
+func TestResizeImage(t *testing.T) {
+	tests := []struct {
+		name    string
+		w, h    int
+		maxSide int
+		wantW   int
+		wantH   int
+	}{
+		{name: "Landscape", w: 400, h: 100, maxSide: 200, wantW: 200, wantH: 50},
+		{name: "Portrait", w: 100, h: 400, maxSide: 200, wantW: 50, wantH: 200},
+		{name: "Fits", w: 100, h: 100, maxSide: 200, wantW: 100, wantH: 100},
+		{name: "Disabled", w: 400, h: 400, maxSide: 0, wantW: 400, wantH: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ResizeImage(image.NewRGBA(image.Rect(0, 0, tt.w, tt.h)), tt.maxSide).Bounds()
+			if got.Dx() != tt.wantW || got.Dy() != tt.wantH {
+				t.Errorf("ResizeImage() = %v, want %dx%d", got, tt.wantW, tt.wantH)
+			}
+		})
+	}
+}